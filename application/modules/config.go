@@ -6,10 +6,17 @@ import "github.com/noxyicm/wsf/config"
 type Config struct {
 	Type     string
 	Priority int
+
+	// Routes holds declarative routes of modules keyed by module name
+	Routes config.Config
 }
 
 // Populate populates Config values using given Config source
 func (c *Config) Populate(cfg config.Config) error {
+	if rcfg := cfg.Get("routes"); rcfg != nil {
+		c.Routes = rcfg
+	}
+
 	if err := cfg.Unmarshal(c); err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"sort"
 	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/controller"
 	"github.com/noxyicm/wsf/errors"
 	"github.com/noxyicm/wsf/registry"
 	"github.com/noxyicm/wsf/utils"
@@ -78,6 +79,10 @@ func (h *handler) Setup() (bool, error) {
 			return false, errors.Wrapf(err, "Unabele to setup module '%s'", mn)
 		}
 
+		if err := h.addRoutes(md); err != nil {
+			return false, errors.Wrapf(err, "Unabele to setup module '%s'", mn)
+		}
+
 		if err := md.InitRoutes(); err != nil {
 			return false, errors.Wrapf(err, "Unabele to setup module '%s'", mn)
 		}
@@ -96,6 +101,27 @@ func (h *handler) Setup() (bool, error) {
 	return true, nil
 }
 
+// addRoutes adds declarative routes of module to router
+func (h *handler) addRoutes(md Interface) error {
+	if h.options.Routes == nil {
+		return nil
+	}
+
+	rcfg := h.options.Routes.Get(md.Name())
+	if rcfg == nil {
+		return nil
+	}
+
+	router, ok := controller.Router().(interface {
+		AddRoutesFromConfig(cfg config.Config, module string) error
+	})
+	if !ok {
+		return errors.New("Router does not support declarative routes")
+	}
+
+	return router.AddRoutesFromConfig(rcfg, md.Name())
+}
+
 // Modules returns handler modules
 func (h *handler) Modules() map[string]Interface {
 	return h.modules
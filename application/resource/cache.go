@@ -2,6 +2,7 @@ package resource
 
 import (
 	"github.com/noxyicm/wsf/cache"
+	_ "github.com/noxyicm/wsf/cache/backend/database"
 	"github.com/noxyicm/wsf/config"
 )
 
@@ -1,8 +1,13 @@
 package resource
 
 import (
+	goctx "context"
+
 	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
 	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/db/migration"
+	"github.com/noxyicm/wsf/errors"
 )
 
 // TYPEDb id of resource
@@ -21,5 +26,24 @@ func NewDbResource(options config.Config) (Interface, error) {
 
 	db.SetInstance(dbb)
 	db.SetDefaultAdapter(dbb.Adapter())
+
+	if mcfg := options.Get("migrations"); mcfg != nil {
+		mgr, err := migration.NewMigrator(mcfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to create database migrator")
+		}
+
+		if mgr.Options.AutoMigrate {
+			ctx, err := context.NewContext(goctx.Background())
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := mgr.Migrate(ctx); err != nil {
+				return nil, errors.Wrap(err, "Unable to migrate database")
+			}
+		}
+	}
+
 	return dbb, nil
 }
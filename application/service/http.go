@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/noxyicm/wsf/config"
 	"github.com/noxyicm/wsf/service"
+	_ "github.com/noxyicm/wsf/service/cache"
 	"github.com/noxyicm/wsf/service/http"
 )
 
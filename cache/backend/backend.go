@@ -11,6 +11,15 @@ const (
 	TYPEBackend = "backend"
 )
 
+// Cleaning modes of Clear
+const (
+	CleaningModeAll = iota
+	CleaningModeOld
+	CleaningModeMatchingTag
+	CleaningModeNotMatchingTag
+	CleaningModeMatchingAnyTag
+)
+
 var (
 	buildHandlers = map[string]func(config.Config) (Interface, error){}
 )
@@ -1,8 +1,12 @@
 package backend
 
+import (
+	"github.com/noxyicm/wsf/config"
+)
+
 // GCInterface represents backend cache gc interface
 type GCInterface interface {
-	Init(options *FileConfig) (bool, error)
+	Init(options config.Config) (bool, error)
 	Start()
 	Stop()
 }
@@ -0,0 +1,535 @@
+package database
+
+import (
+	goctx "context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/cache/backend"
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+const (
+	// TYPEDb is a name of backend cache
+	TYPEDb = "db"
+)
+
+func init() {
+	backend.Register(TYPEDb, NewDbBackendCache)
+}
+
+// Db cache handler storing items in a database table
+// Item tags are stored in a separate table indexed by tag
+type Db struct {
+	backend.Backend
+	Options *DbConfig
+	Adapter db.Adapter
+	GC      *DbGC
+	created bool
+	mu      sync.Mutex
+}
+
+// Init the database backend cache
+func (b *Db) Init(options config.Config) (bool, error) {
+	if _, err := b.adapter(); err != nil {
+		return false, err
+	}
+
+	return b.GC.Init(options)
+}
+
+// Load stored data
+func (b *Db) Load(id string, testCacheValidity bool) ([]byte, error) {
+	adp, err := b.adapter()
+	if err != nil {
+		return nil, err
+	}
+
+	slct := adp.Select().
+		From(b.Options.Table, []string{"data", "expires"}).
+		Where(adp.QuoteIdentifier("id", true)+" = ?", id)
+	if testCacheValidity {
+		b.whereValid(adp, slct)
+	}
+
+	row, err := adp.QueryRow(b.context(), slct)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to load item '%s'", id)
+	}
+
+	if len(row) == 0 || row["data"] == nil {
+		return []byte{}, nil
+	}
+
+	switch v := row["data"].(type) {
+	case []byte:
+		return v, nil
+
+	case string:
+		return []byte(v), nil
+	}
+
+	return nil, errors.Errorf("Unexpected data type '%T' of item '%s'", row["data"], id)
+}
+
+// Test if key exists
+func (b *Db) Test(id string) bool {
+	adp, err := b.adapter()
+	if err != nil {
+		return false
+	}
+
+	slct := adp.Select().
+		From(b.Options.Table, []string{"id"}).
+		Where(adp.QuoteIdentifier("id", true)+" = ?", id)
+	b.whereValid(adp, slct)
+
+	row, err := adp.QueryRow(b.context(), slct)
+	return err == nil && len(row) > 0
+}
+
+// Save data by key
+// Item is upserted, so concurrent saves of the same id do not fail on its primary key
+func (b *Db) Save(data []byte, id string, tags []string, specificLifetime int64) error {
+	adp, err := b.adapter()
+	if err != nil {
+		return err
+	}
+
+	var expires int64
+	if specificLifetime != 0 {
+		expires = time.Now().Unix() + specificLifetime
+	}
+
+	err = db.RunInTransaction(b.context(), adp, func(tx db.Transaction) error {
+		upsert := db.NewInsert(adp).
+			Into(b.Options.Table).
+			Values(map[string]interface{}{
+				"id":      id,
+				"data":    data,
+				"expires": expires,
+			}).
+			OnConflict([]string{"id"}, []string{"data", "expires"})
+		if _, err := tx.Execute(upsert); err != nil {
+			return err
+		}
+
+		if _, err := tx.Delete(b.Options.TagsTable, map[string]interface{}{adp.QuoteIdentifier("id", true) + " = ?": id}); err != nil {
+			return err
+		}
+
+		for _, tag := range utils.UniqueSSlice(tags) {
+			if _, err := tx.Insert(b.Options.TagsTable, map[string]interface{}{
+				"id":  id,
+				"tag": tag,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return errors.Wrapf(err, "Unable to save item '%s'", id)
+}
+
+// Remove data by key
+func (b *Db) Remove(id string) error {
+	return b.remove([]string{id})
+}
+
+// Clear stored data by tags
+func (b *Db) Clear(mode int64, tags []string) error {
+	adp, err := b.adapter()
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	switch mode {
+	case backend.CleaningModeAll:
+		return db.RunInTransaction(b.context(), adp, func(tx db.Transaction) error {
+			if _, err := tx.Delete(b.Options.TagsTable, nil); err != nil {
+				return err
+			}
+
+			_, err := tx.Delete(b.Options.Table, nil)
+			return err
+		})
+
+	case backend.CleaningModeOld:
+		slct := adp.Select().
+			From(b.Options.Table, []string{"id"}).
+			Where(adp.QuoteIdentifier("expires", true)+" > ?", 0).
+			Where(adp.QuoteIdentifier("expires", true)+" <= ?", time.Now().Unix())
+		ids, err = b.ids(adp, slct)
+
+	case backend.CleaningModeMatchingTag:
+		for i, tag := range tags {
+			tagged, err := b.taggedIDs(adp, tag)
+			if err != nil {
+				return err
+			}
+
+			if i == 0 {
+				ids = tagged
+			} else {
+				ids, _ = utils.IntersectSSlice(ids, tagged)
+			}
+		}
+
+	case backend.CleaningModeNotMatchingTag:
+		if ids, err = b.ids(adp, adp.Select().From(b.Options.Table, []string{"id"})); err != nil {
+			return err
+		}
+
+		tagged := make([]string, 0)
+		for _, tag := range tags {
+			t, err := b.taggedIDs(adp, tag)
+			if err != nil {
+				return err
+			}
+
+			tagged = append(tagged, t...)
+		}
+
+		matched := ids
+		ids = make([]string, 0, len(matched))
+		for _, id := range matched {
+			if !utils.InSSlice(id, tagged) {
+				ids = append(ids, id)
+			}
+		}
+
+	case backend.CleaningModeMatchingAnyTag:
+		for _, tag := range tags {
+			tagged, err := b.taggedIDs(adp, tag)
+			if err != nil {
+				return err
+			}
+
+			ids = append(ids, tagged...)
+		}
+
+		ids = utils.UniqueSSlice(ids)
+
+	default:
+		return errors.Errorf("Invalid cleaning mode %d", mode)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return b.remove(ids)
+}
+
+// IDs returns ids of stored items
+func (b *Db) IDs() ([]string, error) {
+	adp, err := b.adapter()
+	if err != nil {
+		return nil, err
+	}
+
+	slct := adp.Select().From(b.Options.Table, []string{"id"})
+	b.whereValid(adp, slct)
+	ids, err := b.ids(adp, slct)
+	sort.Strings(ids)
+	return ids, err
+}
+
+// Tags returns tags of stored items
+func (b *Db) Tags() ([]string, error) {
+	adp, err := b.adapter()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := adp.Query(b.context(), adp.Select().Distinct(true).From(b.Options.TagsTable, []string{"tag"}))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, stringOf(row["tag"]))
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// IDsMatchingTags returns ids of items tagged with all of the tags
+func (b *Db) IDsMatchingTags(tags []string) ([]string, error) {
+	adp, err := b.adapter()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	for i, tag := range tags {
+		tagged, err := b.taggedIDs(adp, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			ids = tagged
+		} else {
+			ids, _ = utils.IntersectSSlice(ids, tagged)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Metadata returns metadata of stored item or nil if item does not exist
+func (b *Db) Metadata(id string) (*backend.Metadata, error) {
+	adp, err := b.adapter()
+	if err != nil {
+		return nil, err
+	}
+
+	slct := adp.Select().
+		From(b.Options.Table, map[string]interface{}{
+			"expires": "expires",
+			"size":    db.NewExpr("LENGTH(" + adp.QuoteIdentifier("data", true) + ")"),
+		}).
+		Where(adp.QuoteIdentifier("id", true)+" = ?", id)
+	b.whereValid(adp, slct)
+
+	row, err := adp.QueryRow(b.context(), slct)
+	if err != nil || len(row) == 0 {
+		return nil, err
+	}
+
+	rows, err := adp.Query(b.context(), adp.Select().
+		From(b.Options.TagsTable, []string{"tag"}).
+		Where(adp.QuoteIdentifier("id", true)+" = ?", id))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &backend.Metadata{
+		ID:      id,
+		Expires: int64Of(row["expires"]),
+		Size:    int64Of(row["size"]),
+		Tags:    make([]string, 0, len(rows)),
+	}
+
+	for _, r := range rows {
+		meta.Tags = append(meta.Tags, stringOf(r["tag"]))
+	}
+
+	return meta, nil
+}
+
+// Stats returns usage of database backend
+func (b *Db) Stats() *backend.Stats {
+	stats := &backend.Stats{}
+	adp, err := b.adapter()
+	if err != nil {
+		return stats
+	}
+
+	row, err := adp.QueryRow(b.context(), adp.Select().From(b.Options.Table, map[string]*db.SQLExpr{
+		"entries": db.NewExpr("COUNT(*)"),
+		"size":    db.NewExpr("SUM(LENGTH(" + adp.QuoteIdentifier("data", true) + "))"),
+	}))
+	if err == nil {
+		stats.Entries = int64Of(row["entries"])
+		stats.Size = int64Of(row["size"])
+	}
+
+	return stats
+}
+
+// Removes items and their tags
+func (b *Db) remove(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	adp, err := b.adapter()
+	if err != nil {
+		return err
+	}
+
+	return db.RunInTransaction(b.context(), adp, func(tx db.Transaction) error {
+		for _, id := range ids {
+			if err := b.removeTx(tx, adp, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Removes item and its tags inside transaction
+func (b *Db) removeTx(tx db.Transaction, adp db.Adapter, id string) error {
+	cond := map[string]interface{}{adp.QuoteIdentifier("id", true) + " = ?": id}
+	if _, err := tx.Delete(b.Options.TagsTable, cond); err != nil {
+		return err
+	}
+
+	_, err := tx.Delete(b.Options.Table, cond)
+	return err
+}
+
+// Returns ids of items tagged with tag
+func (b *Db) taggedIDs(adp db.Adapter, tag string) ([]string, error) {
+	slct := adp.Select().
+		From(b.Options.TagsTable, []string{"id"}).
+		Where(adp.QuoteIdentifier("tag", true)+" = ?", tag)
+	return b.ids(adp, slct)
+}
+
+// Runs select and returns values of id column
+func (b *Db) ids(adp db.Adapter, slct db.Select) ([]string, error) {
+	rows, err := adp.Query(b.context(), slct)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, stringOf(row["id"]))
+	}
+
+	return ids, nil
+}
+
+// Limits select to items which are not expired
+func (b *Db) whereValid(adp db.Adapter, slct db.Select) {
+	expires := adp.QuoteIdentifier("expires", true)
+	slct.Where(adp.QuoteInto(expires+" = ?", 0, 1)+" OR "+adp.QuoteInto(expires+" > ?", time.Now().Unix(), 1), nil)
+}
+
+// Returns a context for database operations
+func (b *Db) context() context.Context {
+	ctx, _ := context.NewContext(goctx.Background())
+	return ctx
+}
+
+// Returns database adapter creating cache tables on first use
+func (b *Db) adapter() (db.Adapter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Adapter == nil {
+		var err error
+		if b.Options.Adapter != "" {
+			b.Adapter, err = db.SetupAdapter(b.Options.Adapter)
+		} else if b.Adapter = db.GetDefaultAdapter(); b.Adapter == nil {
+			err = errors.New("Default database adapter is not set")
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "Database backend cache requires database adapter")
+		}
+	}
+
+	if b.Options.AutoCreate && !b.created {
+		if err := b.createTables(b.Adapter); err != nil {
+			return nil, err
+		}
+	}
+
+	b.created = true
+	return b.Adapter, nil
+}
+
+// Creates cache tables if they do not exist
+func (b *Db) createTables(adp db.Adapter) error {
+	blob := "BLOB"
+	switch adp.GetOptions().Type {
+	case db.TYPEAdapterMySQL:
+		blob = "LONGBLOB"
+
+	case db.TYPEAdapterPostgres, db.TYPEAdapterCockroach:
+		blob = "BYTEA"
+	}
+
+	ctx := b.context()
+	table := adp.QuoteIdentifier(b.Options.Table, true)
+	tagsTable := adp.QuoteIdentifier(b.Options.TagsTable, true)
+	id, tag, expires := adp.QuoteIdentifier("id", true), adp.QuoteIdentifier("tag", true), adp.QuoteIdentifier("expires", true)
+	if _, err := adp.Exec(ctx, "CREATE TABLE IF NOT EXISTS "+table+" ("+
+		id+" VARCHAR(255) NOT NULL PRIMARY KEY, "+
+		adp.QuoteIdentifier("data", true)+" "+blob+", "+
+		expires+" BIGINT NOT NULL DEFAULT 0)"); err != nil {
+		return errors.Wrapf(err, "Unable to create cache table '%s'", b.Options.Table)
+	}
+
+	if _, err := adp.Exec(ctx, "CREATE TABLE IF NOT EXISTS "+tagsTable+" ("+
+		id+" VARCHAR(255) NOT NULL, "+
+		tag+" VARCHAR(255) NOT NULL, "+
+		"PRIMARY KEY ("+tag+", "+id+"))"); err != nil {
+		return errors.Wrapf(err, "Unable to create cache tags table '%s'", b.Options.TagsTable)
+	}
+
+	// Index may already exist and not every database supports IF NOT EXISTS for indexes
+	adp.Exec(ctx, "CREATE INDEX "+adp.QuoteIdentifier(b.Options.TagsTable+"_id", true)+" ON "+tagsTable+" ("+id+")")
+	adp.Exec(ctx, "CREATE INDEX "+adp.QuoteIdentifier(b.Options.Table+"_expires", true)+" ON "+table+" ("+expires+")")
+	return nil
+}
+
+// Converts a column value to string
+func stringOf(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+
+	case []byte:
+		return string(value)
+	}
+
+	return ""
+}
+
+// Converts a numeric column value to int64
+func int64Of(v interface{}) int64 {
+	switch value := v.(type) {
+	case int64:
+		return value
+
+	case int:
+		return int64(value)
+
+	case int32:
+		return int64(value)
+
+	case float64:
+		return int64(value)
+
+	case []byte, string:
+		n, _ := strconv.ParseFloat(stringOf(value), 64)
+		return int64(n)
+	}
+
+	return 0
+}
+
+// NewDbBackendCache creates new database backend cache
+func NewDbBackendCache(options config.Config) (bi backend.Interface, err error) {
+	b := &Db{}
+
+	cfg := &DbConfig{}
+	cfg.Defaults()
+	cfg.Populate(options)
+	b.Options = cfg
+
+	if b.GC, err = NewDbGC(b); err != nil {
+		return nil, errors.Wrap(err, "Failed to create database backend cache gc object")
+	}
+
+	return b, nil
+}
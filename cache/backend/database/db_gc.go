@@ -0,0 +1,81 @@
+package database
+
+import (
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/cache/backend"
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/log"
+)
+
+// DbGC periodically removes expired rows of database backend
+type DbGC struct {
+	Options  *DbConfig
+	StopChan chan bool
+	backend  *Db
+	running  bool
+
+	mu sync.Mutex
+}
+
+// Init the database gc
+func (g *DbGC) Init(options config.Config) (bool, error) {
+	if g.Options.GC > 0 {
+		g.Start()
+	}
+
+	return true, nil
+}
+
+// Start the gc
+func (g *DbGC) Start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.running {
+		return
+	}
+
+	g.running = true
+	go g.startRoutine(time.Duration(g.Options.GC) * time.Second)
+}
+
+// Stop the gc
+func (g *DbGC) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.running {
+		return
+	}
+
+	g.running = false
+	g.StopChan <- true
+}
+
+func (g *DbGC) startRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.StopChan:
+			return
+
+		case <-ticker.C:
+			if err := g.backend.Clear(backend.CleaningModeOld, nil); err != nil && log.Instance() != nil {
+				log.Warning("[Db] Unable to remove expired cache items: "+err.Error(), nil)
+			}
+		}
+	}
+}
+
+// NewDbGC creates a new database gc instance
+func NewDbGC(b *Db) (*DbGC, error) {
+	return &DbGC{
+		Options:  b.Options,
+		StopChan: make(chan bool, 1),
+		backend:  b,
+	}, nil
+}
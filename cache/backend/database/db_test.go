@@ -0,0 +1,104 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/noxyicm/wsf/cache/backend"
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/db/sqlite"
+)
+
+// Creates database backend storing items in in-memory database
+func newTestDb(t *testing.T) *Db {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{
+		"adapter": map[string]interface{}{
+			"type":   sqlite.TYPEAdapter,
+			"dbname": sqlite.Memory,
+		},
+	})
+
+	d, err := db.NewDB(cfg)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	db.SetInstance(d)
+
+	bi, err := NewDbBackendCache(config.NewBridge())
+	if err != nil {
+		t.Fatalf("NewDbBackendCache: %v", err)
+	}
+
+	b := bi.(*Db)
+	b.Adapter = d.Adapter()
+	return b
+}
+
+func TestDbSaveReplacesItem(t *testing.T) {
+	b := newTestDb(t)
+
+	if err := b.Save([]byte("first"), "a", []string{"t1", "t2"}, 0); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := b.Save([]byte("second"), "a", []string{"t3"}, 60); err != nil {
+		t.Fatalf("Save existing item: %v", err)
+	}
+
+	if data, err := b.Load("a", true); err != nil || string(data) != "second" {
+		t.Fatalf("Load = %q, %v", data, err)
+	}
+
+	meta, err := b.Metadata("a")
+	if err != nil || meta == nil {
+		t.Fatalf("Metadata = %+v, %v", meta, err)
+	}
+
+	if meta.Expires == 0 || meta.Size != 6 || !reflect.DeepEqual(meta.Tags, []string{"t3"}) {
+		t.Fatalf("Metadata = %+v", meta)
+	}
+
+	if tags, err := b.Tags(); err != nil || !reflect.DeepEqual(tags, []string{"t3"}) {
+		t.Fatalf("Tags = %v, %v", tags, err)
+	}
+
+	if stats := b.Stats(); stats.Entries != 1 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+}
+
+func TestDbClear(t *testing.T) {
+	b := newTestDb(t)
+	b.Save([]byte("a"), "a", []string{"t1", "t2"}, 0)
+	b.Save([]byte("b"), "b", []string{"t1"}, 0)
+	b.Save([]byte("c"), "c", []string{"t3"}, 0)
+
+	if err := b.Clear(backend.CleaningModeMatchingTag, []string{"t1", "t2"}); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if ids, err := b.IDs(); err != nil || !reflect.DeepEqual(ids, []string{"b", "c"}) {
+		t.Fatalf("IDs = %v, %v", ids, err)
+	}
+
+	if err := b.Clear(backend.CleaningModeNotMatchingTag, []string{"t1"}); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if ids, err := b.IDsMatchingTags([]string{"t1"}); err != nil || !reflect.DeepEqual(ids, []string{"b"}) {
+		t.Fatalf("IDsMatchingTags = %v, %v", ids, err)
+	}
+
+	if err := b.Clear(backend.CleaningModeAll, nil); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if ids, err := b.IDs(); err != nil || len(ids) != 0 {
+		t.Fatalf("IDs = %v, %v", ids, err)
+	}
+}
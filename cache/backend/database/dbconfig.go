@@ -0,0 +1,39 @@
+package database
+
+import (
+	"github.com/noxyicm/wsf/config"
+)
+
+// DbConfig represents database backend cache configuration
+type DbConfig struct {
+	Type       string
+	Adapter    string
+	Table      string
+	TagsTable  string
+	AutoCreate bool
+	GC         int64
+}
+
+// Populate populates Config values using given Config source
+func (c *DbConfig) Populate(cfg config.Config) error {
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *DbConfig) Defaults() error {
+	c.Type = "db"
+	c.Table = "cache"
+	c.TagsTable = "cache_tags"
+	c.AutoCreate = true
+	c.GC = 3600
+	return nil
+}
+
+// Valid validates the configuration
+func (c *DbConfig) Valid() error {
+	return nil
+}
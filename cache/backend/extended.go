@@ -0,0 +1,33 @@
+package backend
+
+// ExtendedInterface is implemented by backends able to list and inspect stored items
+type ExtendedInterface interface {
+	IDs() ([]string, error)
+	Tags() ([]string, error)
+	IDsMatchingTags(tags []string) ([]string, error)
+	Metadata(id string) (*Metadata, error)
+}
+
+// StatsInterface is implemented by backends reporting their usage
+type StatsInterface interface {
+	Stats() *Stats
+}
+
+// Metadata holds metadata of a stored item
+type Metadata struct {
+	ID string
+
+	// Expires is a unix time of item expiration, zero if item never expires
+	Expires int64
+
+	Tags []string
+	Size int64
+}
+
+// Stats holds usage of a backend
+// Values a backend is unable to report are left zero
+type Stats struct {
+	Entries   int64
+	Size      int64
+	Evictions int64
+}
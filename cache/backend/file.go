@@ -2,8 +2,11 @@ package backend
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -213,6 +216,8 @@ func (b *File) Remove(id string) error {
 				storedIDs = append(storedIDs[:key], storedIDs[key+1:]...)
 				if len(storedIDs) > 0 {
 					m[tag] = storedIDs
+				} else {
+					delete(m, tag)
 				}
 			}
 		}
@@ -235,6 +240,201 @@ func (b *File) Clear(mode int64, tags []string) error {
 	return nil
 }
 
+// IDs returns ids of stored items
+func (b *File) IDs() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ids()
+}
+
+// Tags returns tags of stored items
+func (b *File) Tags() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m, err := b.tags()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(m))
+	for tag := range m {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// IDsMatchingTags returns ids of items tagged with all of the tags
+func (b *File) IDsMatchingTags(tags []string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make([]string, 0)
+	if len(tags) == 0 {
+		return ids, nil
+	}
+
+	m, err := b.tags()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range m[tags[0]] {
+		matches := true
+		for _, tag := range tags[1:] {
+			if !utils.InSSlice(id, m[tag]) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Metadata returns metadata of stored item or nil if item does not exist
+func (b *File) Metadata(id string) (*Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fdt, err := b.read(id)
+	if err != nil || fdt == nil {
+		return nil, err
+	}
+
+	m, err := b.tags()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{
+		ID:      id,
+		Expires: fdt.Expires,
+		Tags:    make([]string, 0),
+		Size:    int64(len(fdt.Data)),
+	}
+
+	for tag, storedIDs := range m {
+		if utils.InSSlice(id, storedIDs) {
+			meta.Tags = append(meta.Tags, tag)
+		}
+	}
+
+	sort.Strings(meta.Tags)
+	return meta, nil
+}
+
+// Stats returns usage of file backend
+// Files are not evicted, so evictions are not reported
+func (b *File) Stats() *Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := &Stats{}
+	ids, err := b.ids()
+	if err != nil {
+		return stats
+	}
+
+	for _, id := range ids {
+		if fdt, err := b.read(id); err == nil && fdt != nil {
+			stats.Entries++
+			stats.Size += int64(len(fdt.Data))
+		}
+	}
+
+	return stats
+}
+
+// Returns ids of items which are not expired
+func (b *File) ids() ([]string, error) {
+	files, err := ioutil.ReadDir(b.Options.Dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read directory '%s'", b.Options.Dir)
+	}
+
+	ids := make([]string, 0, len(files))
+	for _, fi := range files {
+		name := fi.Name()
+		if fi.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, b.Options.Suffix) {
+			continue
+		}
+
+		if b.Options.TagsHolder != "" && name == b.Options.TagsHolder+b.Options.Suffix {
+			continue
+		}
+
+		id := strings.TrimSuffix(name, b.Options.Suffix)
+		if fdt, err := b.read(id); err == nil && fdt != nil {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Reads stored item, returns nil if item does not exist or is expired
+func (b *File) read(id string) (*FileData, error) {
+	filePath := b.Options.Dir + "/" + id + b.Options.Suffix
+	d, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "read failed for file '%s'", filePath)
+	}
+
+	fdt := &FileData{}
+	if err := json.Unmarshal(d, fdt); err != nil {
+		return nil, errors.Wrapf(err, "read failed for file '%s'", filePath)
+	}
+
+	if fdt.Expires != 0 && time.Now().After(time.Unix(fdt.Expires, 0)) {
+		return nil, nil
+	}
+
+	return fdt, nil
+}
+
+// Reads ids of items by tag, ids of items which are missing or expired are skipped
+func (b *File) tags() (map[string][]string, error) {
+	m := make(map[string][]string)
+	if b.Options.TagsHolder == "" {
+		return m, nil
+	}
+
+	tagsFilePath := b.Options.Dir + "/" + b.Options.TagsHolder + b.Options.Suffix
+	d, err := ioutil.ReadFile(tagsFilePath)
+	if os.IsNotExist(err) || len(d) == 0 {
+		return m, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "read failed for file '%s'", tagsFilePath)
+	}
+
+	stored := make(map[string][]string)
+	if err := json.Unmarshal(d, &stored); err != nil {
+		return nil, errors.Wrapf(err, "read failed for file '%s'", tagsFilePath)
+	}
+
+	for tag, storedIDs := range stored {
+		for _, id := range storedIDs {
+			if fdt, err := b.read(id); err == nil && fdt != nil {
+				m[tag] = append(m[tag], id)
+			}
+		}
+	}
+
+	return m, nil
+}
+
 // NewFileBackendCache creates new file backend cache
 func NewFileBackendCache(options config.Config) (bi Interface, err error) {
 	b := &File{}
@@ -109,6 +109,14 @@ func (g *FileGC) Check(path string, info os.FileInfo, err error) error {
 	return nil
 }
 
+// Stop the gc
+func (g *FileGC) Stop() {
+	select {
+	case g.StopChan <- true:
+	default:
+	}
+}
+
 func (g *FileGC) startRoutine() {
 Mainloop:
 	for {
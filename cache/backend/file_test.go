@@ -0,0 +1,71 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	_ ExtendedInterface = (*File)(nil)
+	_ StatsInterface    = (*File)(nil)
+)
+
+func newTestFile(t *testing.T) *File {
+	t.Helper()
+
+	return &File{
+		Options: &FileConfig{
+			Dir:        t.TempDir(),
+			Suffix:     ".cache",
+			TagsHolder: "tags",
+		},
+	}
+}
+
+func TestFileListing(t *testing.T) {
+	b := newTestFile(t)
+
+	b.Save([]byte("alpha"), "a", []string{"t1", "t2"}, 60)
+	b.Save([]byte("beta"), "b", []string{"t1"}, 0)
+	b.Save([]byte("gamma"), "c", nil, 0)
+	b.Save([]byte("expired"), "d", []string{"t3"}, -10)
+
+	if ids, err := b.IDs(); err != nil || !reflect.DeepEqual(ids, []string{"a", "b", "c"}) {
+		t.Fatalf("IDs = %v, %v", ids, err)
+	}
+
+	if tags, err := b.Tags(); err != nil || !reflect.DeepEqual(tags, []string{"t1", "t2"}) {
+		t.Fatalf("Tags = %v, %v", tags, err)
+	}
+
+	if ids, err := b.IDsMatchingTags([]string{"t1", "t2"}); err != nil || !reflect.DeepEqual(ids, []string{"a"}) {
+		t.Fatalf("IDsMatchingTags = %v, %v", ids, err)
+	}
+
+	meta, err := b.Metadata("a")
+	if err != nil || meta == nil {
+		t.Fatalf("Metadata = %+v, %v", meta, err)
+	}
+
+	if meta.Size != 5 || meta.Expires <= time.Now().Unix() || !reflect.DeepEqual(meta.Tags, []string{"t1", "t2"}) {
+		t.Fatalf("Metadata = %+v", meta)
+	}
+
+	if meta, err := b.Metadata("d"); err != nil || meta != nil {
+		t.Fatalf("Metadata of expired item = %+v, %v", meta, err)
+	}
+
+	if stats := b.Stats(); stats.Entries != 3 || stats.Size != 14 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+
+	// Removing the last item of a tag removes the tag
+	if err := b.Remove("a"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	if tags, err := b.Tags(); err != nil || !reflect.DeepEqual(tags, []string{"t1"}) {
+		t.Fatalf("Tags after Remove = %v, %v", tags, err)
+	}
+}
@@ -0,0 +1,354 @@
+package backend
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+)
+
+const (
+	// TYPEMemory is a name of backend cache
+	TYPEMemory = "memory"
+)
+
+func init() {
+	Register(TYPEMemory, NewMemoryBackendCache)
+}
+
+// Memory cache handler
+// Entries are evicted in least recently used order when entry or size limit is reached
+type Memory struct {
+	Backend
+	Options   *MemoryConfig
+	GC        *MemoryGC
+	lru       *list.List
+	items     map[string]*list.Element
+	tags      map[string]map[string]bool
+	size      int64
+	evictions int64
+	mu        sync.Mutex
+}
+
+// MemoryData holds a stored cache data
+type MemoryData struct {
+	ID      string
+	Expires int64
+	Data    []byte
+	Tags    []string
+}
+
+// Init the memory backend cache
+func (b *Memory) Init(options config.Config) (bool, error) {
+	return b.GC.Init(options)
+}
+
+// Load stored data
+func (b *Memory) Load(id string, testCacheValidity bool) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	el, ok := b.items[id]
+	if !ok {
+		return []byte{}, nil
+	}
+
+	item := el.Value.(*MemoryData)
+	if testCacheValidity && item.expired(time.Now().Unix()) {
+		b.remove(el)
+		return []byte{}, nil
+	}
+
+	b.lru.MoveToFront(el)
+	return append([]byte{}, item.Data...), nil
+}
+
+// Test if key exists
+func (b *Memory) Test(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	el, ok := b.items[id]
+	if !ok {
+		return false
+	}
+
+	return !el.Value.(*MemoryData).expired(time.Now().Unix())
+}
+
+// Save data by key
+func (b *Memory) Save(data []byte, id string, tags []string, specificLifetime int64) error {
+	if b.Options.MaxSize > 0 && int64(len(data)) > b.Options.MaxSize {
+		return errors.Errorf("Item '%s' of %d bytes exceeds memory cache size limit", id, len(data))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if el, ok := b.items[id]; ok {
+		b.remove(el)
+	}
+
+	var expires int64
+	if specificLifetime != 0 {
+		expires = time.Now().Unix() + specificLifetime
+	}
+
+	// Stored item is not shared with caller
+	item := &MemoryData{
+		ID:      id,
+		Expires: expires,
+		Data:    append([]byte{}, data...),
+		Tags:    append([]string{}, tags...),
+	}
+
+	b.items[id] = b.lru.PushFront(item)
+	b.size += int64(len(data))
+	for _, tag := range tags {
+		if _, ok := b.tags[tag]; !ok {
+			b.tags[tag] = make(map[string]bool)
+		}
+
+		b.tags[tag][id] = true
+	}
+
+	for (b.Options.MaxEntries > 0 && b.lru.Len() > b.Options.MaxEntries) || (b.Options.MaxSize > 0 && b.size > b.Options.MaxSize) {
+		b.remove(b.lru.Back())
+		b.evictions++
+	}
+
+	return nil
+}
+
+// Remove data by key
+func (b *Memory) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if el, ok := b.items[id]; ok {
+		b.remove(el)
+	}
+
+	return nil
+}
+
+// Clear stored data by tags
+func (b *Memory) Clear(mode int64, tags []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch mode {
+	case CleaningModeAll:
+		b.lru.Init()
+		b.items = make(map[string]*list.Element)
+		b.tags = make(map[string]map[string]bool)
+		b.size = 0
+
+	case CleaningModeOld:
+		now := time.Now().Unix()
+		for _, el := range b.items {
+			if el.Value.(*MemoryData).expired(now) {
+				b.remove(el)
+			}
+		}
+
+	case CleaningModeMatchingTag:
+		if len(tags) == 0 {
+			return nil
+		}
+
+		for id := range b.tags[tags[0]] {
+			matches := true
+			for _, tag := range tags[1:] {
+				if !b.tags[tag][id] {
+					matches = false
+					break
+				}
+			}
+
+			if matches {
+				b.remove(b.items[id])
+			}
+		}
+
+	case CleaningModeNotMatchingTag:
+		for id, el := range b.items {
+			matches := false
+			for _, tag := range tags {
+				if b.tags[tag][id] {
+					matches = true
+					break
+				}
+			}
+
+			if !matches {
+				b.remove(el)
+			}
+		}
+
+	case CleaningModeMatchingAnyTag:
+		for _, tag := range tags {
+			for id := range b.tags[tag] {
+				b.remove(b.items[id])
+			}
+		}
+
+	default:
+		return errors.Errorf("Invalid cleaning mode %d", mode)
+	}
+
+	return nil
+}
+
+// Len returns number of stored items
+func (b *Memory) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.lru.Len()
+}
+
+// Size returns size of stored data in bytes
+func (b *Memory) Size() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.size
+}
+
+// IDs returns ids of stored items
+func (b *Memory) IDs() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now().Unix()
+	ids := make([]string, 0, len(b.items))
+	for id, el := range b.items {
+		if !el.Value.(*MemoryData).expired(now) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Tags returns tags of stored items
+func (b *Memory) Tags() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tags := make([]string, 0, len(b.tags))
+	for tag := range b.tags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// IDsMatchingTags returns ids of items tagged with all of the tags
+func (b *Memory) IDsMatchingTags(tags []string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make([]string, 0)
+	if len(tags) == 0 {
+		return ids, nil
+	}
+
+	for id := range b.tags[tags[0]] {
+		matches := true
+		for _, tag := range tags[1:] {
+			if !b.tags[tag][id] {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Metadata returns metadata of stored item or nil if item does not exist
+func (b *Memory) Metadata(id string) (*Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	el, ok := b.items[id]
+	if !ok || el.Value.(*MemoryData).expired(time.Now().Unix()) {
+		return nil, nil
+	}
+
+	item := el.Value.(*MemoryData)
+	return &Metadata{
+		ID:      id,
+		Expires: item.Expires,
+		Tags:    append([]string{}, item.Tags...),
+		Size:    int64(len(item.Data)),
+	}, nil
+}
+
+// Stats returns usage of memory backend
+func (b *Memory) Stats() *Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return &Stats{
+		Entries:   int64(b.lru.Len()),
+		Size:      b.size,
+		Evictions: b.evictions,
+	}
+}
+
+// Removes element from list and indexes
+func (b *Memory) remove(el *list.Element) {
+	if el == nil {
+		return
+	}
+
+	item := b.lru.Remove(el).(*MemoryData)
+	delete(b.items, item.ID)
+	b.size -= int64(len(item.Data))
+	for _, tag := range item.Tags {
+		if ids, ok := b.tags[tag]; ok {
+			delete(ids, item.ID)
+			if len(ids) == 0 {
+				delete(b.tags, tag)
+			}
+		}
+	}
+}
+
+// Returns true if data is expired at the given unix time
+func (d *MemoryData) expired(now int64) bool {
+	return d.Expires != 0 && now >= d.Expires
+}
+
+// NewMemoryBackendCache creates new memory backend cache
+func NewMemoryBackendCache(options config.Config) (bi Interface, err error) {
+	b := &Memory{
+		lru:   list.New(),
+		items: make(map[string]*list.Element),
+		tags:  make(map[string]map[string]bool),
+	}
+
+	cfg := &MemoryConfig{}
+	cfg.Defaults()
+	cfg.Populate(options)
+	b.Options = cfg
+
+	if b.GC, err = NewMemoryGC(b); err != nil {
+		return nil, errors.Wrap(err, "Failed to create memory backend cache gc object")
+	}
+
+	return b, nil
+}
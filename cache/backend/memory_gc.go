@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+)
+
+// MemoryGC periodically removes expired items of memory backend
+type MemoryGC struct {
+	Options  *MemoryConfig
+	StopChan chan bool
+	backend  *Memory
+	running  bool
+
+	mu sync.Mutex
+}
+
+// Init the memory gc
+func (g *MemoryGC) Init(options config.Config) (bool, error) {
+	cfg := &MemoryConfig{}
+	cfg.Defaults()
+	if options != nil {
+		cfg.Populate(options)
+	}
+	g.Options = cfg
+
+	if g.Options.GC > 0 {
+		g.Start()
+	}
+
+	return true, nil
+}
+
+// Start the gc
+func (g *MemoryGC) Start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.running {
+		return
+	}
+
+	g.running = true
+	go g.startRoutine(time.Duration(g.Options.GC) * time.Second)
+}
+
+// Stop the gc
+func (g *MemoryGC) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.running {
+		return
+	}
+
+	g.running = false
+	g.StopChan <- true
+}
+
+func (g *MemoryGC) startRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.StopChan:
+			return
+
+		case <-ticker.C:
+			g.backend.Clear(CleaningModeOld, nil)
+		}
+	}
+}
+
+// NewMemoryGC creates a new memory gc instance
+func NewMemoryGC(b *Memory) (*MemoryGC, error) {
+	return &MemoryGC{
+		Options:  b.Options,
+		StopChan: make(chan bool, 1),
+		backend:  b,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+)
+
+var (
+	_ ExtendedInterface = (*Memory)(nil)
+	_ StatsInterface    = (*Memory)(nil)
+)
+
+func newTestMemory(t *testing.T, options map[string]interface{}) *Memory {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(options)
+	bi, err := NewMemoryBackendCache(cfg)
+	if err != nil {
+		t.Fatalf("NewMemoryBackendCache: %v", err)
+	}
+
+	return bi.(*Memory)
+}
+
+func TestMemoryCopiesData(t *testing.T) {
+	b := newTestMemory(t, nil)
+
+	data := []byte("alpha")
+	b.Save(data, "a", nil, 0)
+	data[0] = 'X'
+
+	loaded, _ := b.Load("a", true)
+	if string(loaded) != "alpha" {
+		t.Fatalf("Load after changing saved buffer = %q, want alpha", loaded)
+	}
+
+	loaded[0] = 'Y'
+	if loaded, _ := b.Load("a", true); string(loaded) != "alpha" {
+		t.Fatalf("Load after changing loaded buffer = %q, want alpha", loaded)
+	}
+}
+
+func TestMemoryEviction(t *testing.T) {
+	b := newTestMemory(t, map[string]interface{}{"maxEntries": 2})
+
+	b.Save([]byte("a"), "a", nil, 0)
+	b.Save([]byte("b"), "b", nil, 0)
+	b.Load("a", true)
+	b.Save([]byte("c"), "c", nil, 0)
+
+	// Least recently used item is evicted
+	if ids, _ := b.IDs(); !reflect.DeepEqual(ids, []string{"a", "c"}) {
+		t.Fatalf("IDs = %v, want [a c]", ids)
+	}
+
+	if stats := b.Stats(); stats.Entries != 2 || stats.Evictions != 1 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+
+	b = newTestMemory(t, map[string]interface{}{"maxSize": 10})
+	b.Save([]byte("aaaa"), "a", nil, 0)
+	b.Save([]byte("bbbb"), "b", nil, 0)
+	b.Save([]byte("cccc"), "c", nil, 0)
+
+	if ids, _ := b.IDs(); !reflect.DeepEqual(ids, []string{"b", "c"}) || b.Size() != 8 {
+		t.Fatalf("IDs = %v of %d bytes, want [b c] of 8 bytes", ids, b.Size())
+	}
+
+	if err := b.Save(make([]byte, 11), "d", nil, 0); err == nil {
+		t.Fatal("Save of item exceeding size limit = nil, want error")
+	}
+}
+
+func TestMemoryTags(t *testing.T) {
+	b := newTestMemory(t, nil)
+
+	b.Save([]byte("a"), "a", []string{"t1", "t2"}, 0)
+	b.Save([]byte("b"), "b", []string{"t1"}, 0)
+	b.Save([]byte("c"), "c", []string{"t3"}, 0)
+
+	if ids, _ := b.IDsMatchingTags([]string{"t1", "t2"}); !reflect.DeepEqual(ids, []string{"a"}) {
+		t.Fatalf("IDsMatchingTags = %v, want [a]", ids)
+	}
+
+	// Saving again replaces tags of item
+	b.Save([]byte("a"), "a", []string{"t3"}, 0)
+	if tags, _ := b.Tags(); !reflect.DeepEqual(tags, []string{"t1", "t3"}) {
+		t.Fatalf("Tags = %v, want [t1 t3]", tags)
+	}
+
+	if err := b.Clear(CleaningModeMatchingAnyTag, []string{"t1"}); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if ids, _ := b.IDs(); !reflect.DeepEqual(ids, []string{"a", "c"}) {
+		t.Fatalf("IDs = %v, want [a c]", ids)
+	}
+
+	if err := b.Clear(CleaningModeNotMatchingTag, []string{"t1"}); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if tags, _ := b.Tags(); len(tags) != 0 || b.Len() != 0 || b.Size() != 0 {
+		t.Fatalf("Tags = %v of %d items of %d bytes, want none", tags, b.Len(), b.Size())
+	}
+}
+
+func TestMemoryGC(t *testing.T) {
+	b := newTestMemory(t, nil)
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{"gc": 1})
+	if _, err := b.Init(cfg); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer b.GC.Stop()
+
+	b.Save([]byte("a"), "a", []string{"t1"}, -1)
+	b.Save([]byte("b"), "b", []string{"t1"}, 0)
+
+	if b.Test("a") {
+		t.Fatal("expired item exists")
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for b.Len() != 1 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if ids, _ := b.IDsMatchingTags([]string{"t1"}); b.Len() != 1 || !reflect.DeepEqual(ids, []string{"b"}) {
+		t.Fatalf("%d items with tagged %v left after gc, want [b]", b.Len(), ids)
+	}
+}
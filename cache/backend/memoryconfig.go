@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"github.com/noxyicm/wsf/config"
+)
+
+// MemoryConfig represents memory backend cache configuration
+type MemoryConfig struct {
+	Type       string
+	MaxEntries int
+	MaxSize    int64
+	GC         int64
+}
+
+// Populate populates Config values using given Config source
+func (c *MemoryConfig) Populate(cfg config.Config) error {
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *MemoryConfig) Defaults() error {
+	c.Type = "memory"
+	c.MaxEntries = 10000
+	c.MaxSize = 64 << 20
+	c.GC = 60
+	return nil
+}
+
+// Valid validates the configuration
+func (c *MemoryConfig) Valid() error {
+	return nil
+}
@@ -0,0 +1,450 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+)
+
+const (
+	// TYPERedis is a name of backend cache
+	TYPERedis = "redis"
+)
+
+func init() {
+	Register(TYPERedis, NewRedisBackendCache)
+}
+
+// Redis cache handler speaking redis protocol
+// Items are stored as strings with ttl, tags are stored as sets of item ids
+type Redis struct {
+	Backend
+	Options *RedisConfig
+	Pool    *RESPPool
+}
+
+// Init the redis backend cache
+func (b *Redis) Init(options config.Config) (bool, error) {
+	if _, err := b.do("PING"); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Load stored data
+func (b *Redis) Load(id string, testCacheValidity bool) ([]byte, error) {
+	reply, err := b.do("GET", b.itemKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if data, ok := reply.([]byte); ok {
+		return data, nil
+	}
+
+	return []byte{}, nil
+}
+
+// Test if key exists
+func (b *Redis) Test(id string) bool {
+	reply, err := b.do("EXISTS", b.itemKey(id))
+	if err != nil {
+		return false
+	}
+
+	n, _ := reply.(int64)
+	return n > 0
+}
+
+// Save data by key
+func (b *Redis) Save(data []byte, id string, tags []string, specificLifetime int64) error {
+	set := []interface{}{"SET", b.itemKey(id), data}
+	if specificLifetime > 0 {
+		set = append(set, "EX", specificLifetime)
+	}
+
+	stored, err := b.itemTags([]string{id})
+	if err != nil {
+		return err
+	}
+
+	cmds := b.unindexCommands(id, stored[id])
+	cmds = append(cmds, set, []interface{}{"SADD", b.key("ids"), id}, []interface{}{"DEL", b.itemTagsKey(id)})
+
+	if len(tags) > 0 {
+		sadd := []interface{}{"SADD", b.itemTagsKey(id)}
+		for _, tag := range tags {
+			sadd = append(sadd, tag)
+			cmds = append(cmds, []interface{}{"SADD", b.tagKey(tag), id})
+		}
+
+		cmds = append(cmds, sadd)
+	}
+
+	return b.pipeline(cmds)
+}
+
+// Remove data by key
+func (b *Redis) Remove(id string) error {
+	return b.remove([]string{id})
+}
+
+// Clear stored data by tags
+func (b *Redis) Clear(mode int64, tags []string) error {
+	var ids []string
+	var err error
+	switch mode {
+	case CleaningModeAll:
+		return b.clearAll()
+
+	case CleaningModeOld:
+		return b.clearOld()
+
+	case CleaningModeMatchingTag:
+		if len(tags) == 0 {
+			return nil
+		}
+
+		ids, err = b.members("SINTER", b.tagKeys(tags)...)
+
+	case CleaningModeNotMatchingTag:
+		ids, err = b.members("SDIFF", append([]string{b.key("ids")}, b.tagKeys(tags)...)...)
+
+	case CleaningModeMatchingAnyTag:
+		if len(tags) == 0 {
+			return nil
+		}
+
+		ids, err = b.members("SUNION", b.tagKeys(tags)...)
+
+	default:
+		return errors.Errorf("Invalid cleaning mode %d", mode)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return b.remove(ids)
+}
+
+// IDs returns ids of stored items
+func (b *Redis) IDs() ([]string, error) {
+	ids, err := b.members("SMEMBERS", b.key("ids"))
+	sort.Strings(ids)
+	return ids, err
+}
+
+// Tags returns tags of stored items
+func (b *Redis) Tags() ([]string, error) {
+	tags := make([]string, 0)
+	err := b.scan(b.tagKey("*"), func(keys []interface{}) error {
+		for _, key := range keys {
+			if k, ok := key.([]byte); ok {
+				tags = append(tags, strings.TrimPrefix(string(k), b.tagKey("")))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// IDsMatchingTags returns ids of items tagged with all of the tags
+func (b *Redis) IDsMatchingTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return []string{}, nil
+	}
+
+	ids, err := b.members("SINTER", b.tagKeys(tags)...)
+	sort.Strings(ids)
+	return ids, err
+}
+
+// Metadata returns metadata of stored item or nil if item does not exist
+func (b *Redis) Metadata(id string) (*Metadata, error) {
+	c, err := b.Pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer b.Pool.Put(c)
+
+	replies, err := c.Pipeline([][]interface{}{
+		{"TTL", b.itemKey(id)},
+		{"STRLEN", b.itemKey(id)},
+		{"SMEMBERS", b.itemTagsKey(id)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, _ := replies[0].(int64)
+	if ttl == -2 {
+		return nil, nil
+	}
+
+	meta := &Metadata{ID: id, Tags: make([]string, 0)}
+	if ttl > 0 {
+		meta.Expires = time.Now().Unix() + ttl
+	}
+
+	meta.Size, _ = replies[1].(int64)
+	items, _ := replies[2].([]interface{})
+	for _, item := range items {
+		if tag, ok := item.([]byte); ok {
+			meta.Tags = append(meta.Tags, string(tag))
+		}
+	}
+
+	return meta, nil
+}
+
+// Stats returns usage of redis backend
+// Evictions are reported by the server and are not limited to the prefix
+func (b *Redis) Stats() *Stats {
+	stats := &Stats{}
+	if reply, err := b.do("SCARD", b.key("ids")); err == nil {
+		stats.Entries, _ = reply.(int64)
+	}
+
+	if reply, err := b.do("INFO", "stats"); err == nil {
+		info, _ := reply.([]byte)
+		sc := bufio.NewScanner(bytes.NewReader(info))
+		for sc.Scan() {
+			if line := strings.TrimSpace(sc.Text()); strings.HasPrefix(line, "evicted_keys:") {
+				stats.Evictions, _ = strconv.ParseInt(strings.TrimPrefix(line, "evicted_keys:"), 10, 64)
+			}
+		}
+	}
+
+	return stats
+}
+
+// Close closes pool connections
+func (b *Redis) Close() {
+	b.Pool.Close()
+}
+
+// Removes items and their tag index entries
+func (b *Redis) remove(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	stored, err := b.itemTags(ids)
+	if err != nil {
+		return err
+	}
+
+	cmds := make([][]interface{}, 0)
+	for _, id := range ids {
+		cmds = append(cmds, b.unindexCommands(id, stored[id])...)
+		cmds = append(cmds, []interface{}{"DEL", b.itemKey(id), b.itemTagsKey(id)}, []interface{}{"SREM", b.key("ids"), id})
+	}
+
+	return b.pipeline(cmds)
+}
+
+// Returns stored tags of items
+func (b *Redis) itemTags(ids []string) (map[string][]string, error) {
+	cmds := make([][]interface{}, len(ids))
+	for i, id := range ids {
+		cmds[i] = []interface{}{"SMEMBERS", b.itemTagsKey(id)}
+	}
+
+	c, err := b.Pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer b.Pool.Put(c)
+
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string][]string)
+	for i, reply := range replies {
+		items, _ := reply.([]interface{})
+		for _, item := range items {
+			if tag, ok := item.([]byte); ok {
+				tags[ids[i]] = append(tags[ids[i]], string(tag))
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// Returns commands removing item id from sets of its tags
+func (b *Redis) unindexCommands(id string, tags []string) [][]interface{} {
+	cmds := make([][]interface{}, len(tags))
+	for i, tag := range tags {
+		cmds[i] = []interface{}{"SREM", b.tagKey(tag), id}
+	}
+
+	return cmds
+}
+
+// Removes all keys under prefix
+func (b *Redis) clearAll() error {
+	return b.scan(b.key("*"), func(keys []interface{}) error {
+		_, err := b.do(append([]interface{}{"DEL"}, keys...)...)
+		return err
+	})
+}
+
+// Iterates keys matching pattern calling fn for each non empty batch
+func (b *Redis) scan(pattern string, fn func(keys []interface{}) error) error {
+	cursor := "0"
+	for {
+		reply, err := b.do("SCAN", cursor, "MATCH", pattern, "COUNT", 1000)
+		if err != nil {
+			return err
+		}
+
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return errors.New("Malformed redis SCAN reply")
+		}
+
+		next, _ := parts[0].([]byte)
+		keys, _ := parts[1].([]interface{})
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// Removes index entries of items expired by redis
+// Tags of an item are kept without ttl so expired items can be removed from tag sets
+func (b *Redis) clearOld() error {
+	ids, err := b.members("SMEMBERS", b.key("ids"))
+	if err != nil {
+		return err
+	}
+
+	expired := make([]string, 0)
+	for _, id := range ids {
+		if !b.Test(id) {
+			expired = append(expired, id)
+		}
+	}
+
+	return b.remove(expired)
+}
+
+// Runs a set command and returns members as strings
+func (b *Redis) members(cmd string, keys ...string) ([]string, error) {
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, cmd)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	reply, err := b.do(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	items, _ := reply.([]interface{})
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if v, ok := item.([]byte); ok {
+			ids = append(ids, string(v))
+		}
+	}
+
+	return ids, nil
+}
+
+// Sends a command using pooled connection
+func (b *Redis) do(args ...interface{}) (interface{}, error) {
+	c, err := b.Pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer b.Pool.Put(c)
+
+	return c.Do(args...)
+}
+
+// Sends commands in a pipeline returning the first error reply
+func (b *Redis) pipeline(cmds [][]interface{}) error {
+	c, err := b.Pool.Get()
+	if err != nil {
+		return err
+	}
+	defer b.Pool.Put(c)
+
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return err
+	}
+
+	for _, reply := range replies {
+		if rerr, ok := reply.(RESPError); ok {
+			return rerr
+		}
+	}
+
+	return nil
+}
+
+func (b *Redis) key(name string) string {
+	return b.Options.Prefix + name
+}
+
+func (b *Redis) itemKey(id string) string {
+	return b.Options.Prefix + "item:" + id
+}
+
+func (b *Redis) itemTagsKey(id string) string {
+	return b.Options.Prefix + "itags:" + id
+}
+
+func (b *Redis) tagKey(tag string) string {
+	return b.Options.Prefix + "tag:" + tag
+}
+
+func (b *Redis) tagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = b.tagKey(tag)
+	}
+
+	return keys
+}
+
+// NewRedisBackendCache creates new redis backend cache
+func NewRedisBackendCache(options config.Config) (Interface, error) {
+	cfg := &RedisConfig{}
+	cfg.Defaults()
+	if err := cfg.Populate(options); err != nil {
+		return nil, errors.Wrap(err, "Failed to create redis backend cache object")
+	}
+
+	return &Redis{
+		Options: cfg,
+		Pool:    NewRESPPool(cfg),
+	}, nil
+}
@@ -0,0 +1,469 @@
+package backend
+
+import (
+	"bufio"
+	"net"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+)
+
+// respStandIn is an in-process server implementing redis commands used by the backend
+type respStandIn struct {
+	ln       net.Listener
+	password string
+	mu       sync.Mutex
+	values   map[string][]byte
+	sets     map[string]map[string]bool
+	expires  map[string]time.Time
+}
+
+// Starts stand-in listening on a random local port
+func newRESPStandIn(t *testing.T, password string) *respStandIn {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &respStandIn{
+		ln:       ln,
+		password: password,
+		values:   make(map[string][]byte),
+		sets:     make(map[string]map[string]bool),
+		expires:  make(map[string]time.Time),
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+// Serves commands of a connection
+func (s *respStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	rd := bufio.NewReader(conn)
+	wr := bufio.NewWriter(conn)
+	authenticated := s.password == ""
+	for {
+		value, err := ReadRESP(rd)
+		if err != nil {
+			return
+		}
+
+		items, _ := value.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+
+		var reply interface{}
+		switch {
+		case len(args) == 0:
+			reply = RESPError("ERR empty command")
+
+		case strings.ToUpper(args[0]) == "AUTH":
+			authenticated = len(args) == 2 && args[1] == s.password
+			reply = "OK"
+			if !authenticated {
+				reply = RESPError("WRONGPASS invalid password")
+			}
+
+		case !authenticated:
+			reply = RESPError("NOAUTH Authentication required")
+
+		default:
+			reply = s.command(strings.ToUpper(args[0]), args[1:])
+		}
+
+		if err := WriteRESP(wr, reply); err != nil {
+			return
+		}
+
+		if rd.Buffered() == 0 {
+			if err := wr.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Executes a command and returns its reply
+func (s *respStandIn) command(name string, args []string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, at := range s.expires {
+		if time.Now().After(at) {
+			s.delete(key)
+		}
+	}
+
+	switch name {
+	case "PING":
+		return "PONG"
+
+	case "SELECT":
+		return "OK"
+
+	case "GET":
+		if v, ok := s.values[args[0]]; ok {
+			return v
+		}
+
+		return nil
+
+	case "SET":
+		s.delete(args[0])
+		s.values[args[0]] = []byte(args[1])
+		if len(args) == 4 && strings.ToUpper(args[2]) == "EX" {
+			seconds, _ := strconv.Atoi(args[3])
+			s.expires[args[0]] = time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+
+		return "OK"
+
+	case "EXISTS", "DEL":
+		n := 0
+		for _, key := range args {
+			if s.exists(key) {
+				n++
+				if name == "DEL" {
+					s.delete(key)
+				}
+			}
+		}
+
+		return n
+
+	case "STRLEN":
+		return len(s.values[args[0]])
+
+	case "TTL":
+		if !s.exists(args[0]) {
+			return -2
+		}
+
+		if at, ok := s.expires[args[0]]; ok {
+			return int(time.Until(at).Seconds() + 0.5)
+		}
+
+		return -1
+
+	case "SADD", "SREM":
+		set, ok := s.sets[args[0]]
+		if !ok {
+			set = make(map[string]bool)
+			s.sets[args[0]] = set
+		}
+
+		n := 0
+		for _, member := range args[1:] {
+			if set[member] != (name == "SADD") {
+				n++
+			}
+
+			if name == "SADD" {
+				set[member] = true
+			} else {
+				delete(set, member)
+			}
+		}
+
+		if len(set) == 0 {
+			delete(s.sets, args[0])
+		}
+
+		return n
+
+	case "SCARD":
+		return len(s.sets[args[0]])
+
+	case "SMEMBERS", "SINTER", "SUNION", "SDIFF":
+		members := make(map[string]bool)
+		for member := range s.sets[args[0]] {
+			members[member] = true
+		}
+
+		for _, key := range args[1:] {
+			for member := range members {
+				if name == "SINTER" && !s.sets[key][member] || name == "SDIFF" && s.sets[key][member] {
+					delete(members, member)
+				}
+			}
+
+			if name == "SUNION" {
+				for member := range s.sets[key] {
+					members[member] = true
+				}
+			}
+		}
+
+		reply := make([]string, 0, len(members))
+		for member := range members {
+			reply = append(reply, member)
+		}
+
+		return bulkArray(reply)
+
+	case "SCAN":
+		pattern := "*"
+		for i := 1; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+
+		keys := make([]string, 0)
+		for _, key := range s.keys() {
+			if ok, _ := path.Match(pattern, key); ok {
+				keys = append(keys, key)
+			}
+		}
+
+		return []interface{}{[]byte("0"), bulkArray(keys)}
+
+	case "INFO":
+		return []byte("# Stats\r\nevicted_keys:3\r\n")
+	}
+
+	return RESPError("ERR unknown command '" + name + "'")
+}
+
+// Returns true if key holds a value or a set
+func (s *respStandIn) exists(key string) bool {
+	_, isValue := s.values[key]
+	_, isSet := s.sets[key]
+	return isValue || isSet
+}
+
+// Removes key of any type
+func (s *respStandIn) delete(key string) {
+	delete(s.values, key)
+	delete(s.sets, key)
+	delete(s.expires, key)
+}
+
+// Returns all keys
+func (s *respStandIn) keys() []string {
+	keys := make([]string, 0, len(s.values)+len(s.sets))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+
+	for key := range s.sets {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Expires key as if its ttl passed
+func (s *respStandIn) expire(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expires[key] = time.Now().Add(-time.Second)
+}
+
+// Returns strings as array of bulk replies
+func bulkArray(values []string) []interface{} {
+	sort.Strings(values)
+	reply := make([]interface{}, len(values))
+	for i, v := range values {
+		reply[i] = []byte(v)
+	}
+
+	return reply
+}
+
+// Creates redis backend connected to stand-in
+func newTestRedis(t *testing.T, server *respStandIn, options map[string]interface{}) *Redis {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{"address": server.ln.Addr().String()})
+	cfg.Merge(options)
+
+	bi, err := NewRedisBackendCache(cfg)
+	if err != nil {
+		t.Fatalf("NewRedisBackendCache: %v", err)
+	}
+
+	b := bi.(*Redis)
+	t.Cleanup(b.Close)
+	if _, err := b.Init(cfg); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	return b
+}
+
+func TestRedisSaveLoad(t *testing.T) {
+	b := newTestRedis(t, newRESPStandIn(t, "secret"), map[string]interface{}{"password": "secret", "database": 2})
+
+	if err := b.Save([]byte("alpha"), "a", []string{"t1", "t2"}, 60); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := b.Save([]byte("beta"), "b", nil, 0); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if data, err := b.Load("a", true); err != nil || string(data) != "alpha" {
+		t.Fatalf("Load = %q, %v", data, err)
+	}
+
+	if !b.Test("b") || b.Test("missing") {
+		t.Fatal("Test reports wrong existence")
+	}
+
+	meta, err := b.Metadata("a")
+	if err != nil {
+		t.Fatalf("Metadata: %v", err)
+	}
+
+	sort.Strings(meta.Tags)
+	if meta.Size != 5 || meta.Expires <= time.Now().Unix() || !reflect.DeepEqual(meta.Tags, []string{"t1", "t2"}) {
+		t.Fatalf("Metadata = %+v", meta)
+	}
+
+	if meta, err := b.Metadata("missing"); err != nil || meta != nil {
+		t.Fatalf("Metadata of missing item = %+v, %v", meta, err)
+	}
+
+	// Saving again replaces tags of item
+	if err := b.Save([]byte("alpha"), "a", []string{"t3"}, 0); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if tags, err := b.Tags(); err != nil || !reflect.DeepEqual(tags, []string{"t3"}) {
+		t.Fatalf("Tags = %v, %v", tags, err)
+	}
+
+	if ids, err := b.IDs(); err != nil || !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Fatalf("IDs = %v, %v", ids, err)
+	}
+
+	if stats := b.Stats(); stats.Entries != 2 || stats.Evictions != 3 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+}
+
+func TestRedisWrongPassword(t *testing.T) {
+	server := newRESPStandIn(t, "secret")
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{"address": server.ln.Addr().String(), "password": "wrong"})
+	bi, err := NewRedisBackendCache(cfg)
+	if err != nil {
+		t.Fatalf("NewRedisBackendCache: %v", err)
+	}
+
+	if ok, err := bi.Init(cfg); ok || err == nil {
+		t.Fatalf("Init = %v, %v, want authentication error", ok, err)
+	}
+}
+
+func TestRedisClear(t *testing.T) {
+	cases := []struct {
+		name string
+		mode int64
+		tags []string
+		ids  []string
+	}{
+		{"all", CleaningModeAll, nil, []string{}},
+		{"matching tag", CleaningModeMatchingTag, []string{"t1", "t2"}, []string{"b", "c"}},
+		{"not matching tag", CleaningModeNotMatchingTag, []string{"t1"}, []string{"a", "b"}},
+		{"matching any tag", CleaningModeMatchingAnyTag, []string{"t2", "t3"}, []string{"b"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := newTestRedis(t, newRESPStandIn(t, ""), nil)
+			b.Save([]byte("a"), "a", []string{"t1", "t2"}, 0)
+			b.Save([]byte("b"), "b", []string{"t1"}, 0)
+			b.Save([]byte("c"), "c", []string{"t3"}, 0)
+
+			if err := b.Clear(c.mode, c.tags); err != nil {
+				t.Fatalf("Clear: %v", err)
+			}
+
+			ids, err := b.IDs()
+			if err != nil {
+				t.Fatalf("IDs: %v", err)
+			}
+
+			if !reflect.DeepEqual(ids, c.ids) {
+				t.Fatalf("IDs = %v, want %v", ids, c.ids)
+			}
+
+			kept := make(map[string]bool)
+			for _, id := range c.ids {
+				kept[id] = true
+			}
+
+			for _, id := range []string{"a", "b", "c"} {
+				if b.Test(id) != kept[id] {
+					t.Errorf("item %s exists = %v, want %v", id, b.Test(id), kept[id])
+				}
+			}
+
+			// Tag sets hold removed items no more
+			tags, err := b.Tags()
+			if err != nil {
+				t.Fatalf("Tags: %v", err)
+			}
+
+			for _, tag := range tags {
+				ids, _ := b.IDsMatchingTags([]string{tag})
+				for _, id := range ids {
+					if !b.Test(id) {
+						t.Errorf("tag %s still holds removed item %s", tag, id)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRedisClearOld(t *testing.T) {
+	server := newRESPStandIn(t, "")
+	b := newTestRedis(t, server, map[string]interface{}{"prefix": "app:"})
+
+	b.Save([]byte("a"), "a", []string{"t1"}, 60)
+	b.Save([]byte("b"), "b", []string{"t1"}, 60)
+	server.expire("app:item:a")
+
+	if err := b.Clear(CleaningModeOld, nil); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if ids, _ := b.IDs(); !reflect.DeepEqual(ids, []string{"b"}) {
+		t.Fatalf("IDs = %v, want [b]", ids)
+	}
+
+	if ids, _ := b.IDsMatchingTags([]string{"t1"}); !reflect.DeepEqual(ids, []string{"b"}) {
+		t.Fatalf("IDsMatchingTags = %v, want [b]", ids)
+	}
+}
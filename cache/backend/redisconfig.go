@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// RedisConfig represents redis backend cache configuration
+type RedisConfig struct {
+	Type         string
+	Network      string
+	Address      string
+	Password     string
+	Database     int
+	Prefix       string
+	PoolSize     int
+	DialTimeout  int
+	ReadTimeout  int
+	WriteTimeout int
+}
+
+// Populate populates Config values using given Config source
+func (c *RedisConfig) Populate(cfg config.Config) error {
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *RedisConfig) Defaults() error {
+	c.Type = "redis"
+	c.Network = "tcp"
+	c.Address = "127.0.0.1:6379"
+	c.Database = 0
+	c.Prefix = "wsf:"
+	c.PoolSize = 10
+	c.DialTimeout = 5
+	c.ReadTimeout = 3
+	c.WriteTimeout = 3
+	return nil
+}
+
+// Valid validates the configuration
+func (c *RedisConfig) Valid() error {
+	switch c.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return nil
+	}
+
+	return errors.Errorf("Unsupported redis network '%s'", c.Network)
+}
@@ -0,0 +1,306 @@
+package backend
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// RESPError is an error reply of a redis protocol server
+type RESPError string
+
+// Error returns error message
+func (e RESPError) Error() string {
+	return string(e)
+}
+
+// RESPConn is a connection speaking redis serialization protocol
+type RESPConn struct {
+	conn         net.Conn
+	rd           *bufio.Reader
+	wr           *bufio.Writer
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	broken       bool
+}
+
+// Do sends a command and returns its reply
+// Replies are returned as string for status, int64 for integer, []byte or nil for bulk
+// and []interface{} for array replies, error replies are returned as RESPError
+func (c *RESPConn) Do(args ...interface{}) (interface{}, error) {
+	replies, err := c.Pipeline([][]interface{}{args})
+	if err != nil {
+		return nil, err
+	}
+
+	if rerr, ok := replies[0].(RESPError); ok {
+		return nil, rerr
+	}
+
+	return replies[0], nil
+}
+
+// Pipeline sends commands at once and returns their replies in order
+// Error replies are returned in place of replies
+func (c *RESPConn) Pipeline(cmds [][]interface{}) ([]interface{}, error) {
+	if c.writeTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+
+	for _, args := range cmds {
+		if err := c.writeCommand(args); err != nil {
+			c.broken = true
+			return nil, errors.Wrap(err, "Unable to send redis command")
+		}
+	}
+
+	if err := c.wr.Flush(); err != nil {
+		c.broken = true
+		return nil, errors.Wrap(err, "Unable to send redis command")
+	}
+
+	if c.readTimeout > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+	}
+
+	replies := make([]interface{}, len(cmds))
+	for i := range cmds {
+		reply, err := ReadRESP(c.rd)
+		if err != nil {
+			c.broken = true
+			return nil, errors.Wrap(err, "Unable to read redis reply")
+		}
+
+		replies[i] = reply
+	}
+
+	return replies, nil
+}
+
+// Close closes the connection
+func (c *RESPConn) Close() error {
+	return c.conn.Close()
+}
+
+// Writes command as array of bulk strings
+func (c *RESPConn) writeCommand(args []interface{}) error {
+	c.wr.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case []byte:
+			b = v
+
+		case string:
+			b = []byte(v)
+
+		case int:
+			b = []byte(strconv.Itoa(v))
+
+		case int64:
+			b = []byte(strconv.FormatInt(v, 10))
+
+		default:
+			return errors.Errorf("Unsupported redis argument type '%T'", arg)
+		}
+
+		c.wr.WriteString("$" + strconv.Itoa(len(b)) + "\r\n")
+		c.wr.Write(b)
+		if _, err := c.wr.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadRESP reads a single redis protocol value
+func ReadRESP(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.Errorf("Malformed redis protocol line %q", line)
+	}
+
+	payload := line[1 : len(line)-2]
+	switch line[0] {
+	case '+':
+		return payload, nil
+
+	case '-':
+		return RESPError(payload), nil
+
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "Malformed redis bulk length")
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+
+		return b[:n], nil
+
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "Malformed redis array length")
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		values := make([]interface{}, n)
+		for i := 0; i < n; i++ {
+			if values[i], err = ReadRESP(rd); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	}
+
+	return nil, errors.Errorf("Unknown redis protocol type '%c'", line[0])
+}
+
+// WriteRESP writes a redis protocol value
+// Accepts string as status, RESPError, int and int64 as integer, []byte as bulk,
+// nil as null bulk and []interface{} as array
+func WriteRESP(wr *bufio.Writer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		wr.WriteString("$-1\r\n")
+
+	case string:
+		wr.WriteString("+" + v + "\r\n")
+
+	case RESPError:
+		wr.WriteString("-" + string(v) + "\r\n")
+
+	case int:
+		wr.WriteString(":" + strconv.Itoa(v) + "\r\n")
+
+	case int64:
+		wr.WriteString(":" + strconv.FormatInt(v, 10) + "\r\n")
+
+	case []byte:
+		wr.WriteString("$" + strconv.Itoa(len(v)) + "\r\n")
+		wr.Write(v)
+		wr.WriteString("\r\n")
+
+	case []interface{}:
+		wr.WriteString("*" + strconv.Itoa(len(v)) + "\r\n")
+		for _, item := range v {
+			if err := WriteRESP(wr, item); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return errors.Errorf("Unsupported redis value type '%T'", value)
+	}
+
+	return nil
+}
+
+// RESPPool is a pool of redis protocol connections
+type RESPPool struct {
+	Options *RedisConfig
+	idle    chan *RESPConn
+}
+
+// Get returns an idle connection or dials a new one
+func (p *RESPPool) Get() (*RESPConn, error) {
+	select {
+	case c := <-p.idle:
+		return c, nil
+
+	default:
+	}
+
+	conn, err := net.DialTimeout(p.Options.Network, p.Options.Address, time.Duration(p.Options.DialTimeout)*time.Second)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to connect to redis at '%s'", p.Options.Address)
+	}
+
+	c := &RESPConn{
+		conn:         conn,
+		rd:           bufio.NewReader(conn),
+		wr:           bufio.NewWriter(conn),
+		readTimeout:  time.Duration(p.Options.ReadTimeout) * time.Second,
+		writeTimeout: time.Duration(p.Options.WriteTimeout) * time.Second,
+	}
+
+	if p.Options.Password != "" {
+		if _, err := c.Do("AUTH", p.Options.Password); err != nil {
+			c.Close()
+			return nil, errors.Wrap(err, "Redis authentication failed")
+		}
+	}
+
+	if p.Options.Database != 0 {
+		if _, err := c.Do("SELECT", p.Options.Database); err != nil {
+			c.Close()
+			return nil, errors.Wrapf(err, "Unable to select redis database %d", p.Options.Database)
+		}
+	}
+
+	return c, nil
+}
+
+// Put returns connection to the pool, broken connections are closed
+func (p *RESPPool) Put(c *RESPConn) {
+	if c.broken {
+		c.Close()
+		return
+	}
+
+	select {
+	case p.idle <- c:
+	default:
+		c.Close()
+	}
+}
+
+// Close closes all idle connections
+func (p *RESPPool) Close() {
+	for {
+		select {
+		case c := <-p.idle:
+			c.Close()
+
+		default:
+			return
+		}
+	}
+}
+
+// NewRESPPool creates a pool of redis protocol connections
+func NewRESPPool(options *RedisConfig) *RESPPool {
+	size := options.PoolSize
+	if size <= 0 {
+		size = 1
+	}
+
+	return &RESPPool{
+		Options: options,
+		idle:    make(chan *RESPConn, size),
+	}
+}
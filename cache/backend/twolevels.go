@@ -0,0 +1,239 @@
+package backend
+
+import (
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+)
+
+const (
+	// TYPETwoLevels is a name of backend cache
+	TYPETwoLevels = "twolevels"
+
+	// Tag of fast backend items copied from slow backend on read
+	// Their tags are unknown so they are removed on any tag based cleaning
+	twoLevelsFilledTag = "internal_twolevels_filled"
+)
+
+func init() {
+	Register(TYPETwoLevels, NewTwoLevelsBackendCache)
+}
+
+// TwoLevels cache handler chaining a fast local backend in front of a shared slow backend
+// Writes and cleanings go to both backends, reads are served by fast backend when possible.
+// Items in fast backend live at most FastLifetime seconds which bounds staleness
+// of items invalidated through another node sharing the slow backend
+type TwoLevels struct {
+	Backend
+	Options *TwoLevelsConfig
+	Fast    Interface
+	Slow    Interface
+}
+
+// Init the two levels backend cache
+func (b *TwoLevels) Init(options config.Config) (bool, error) {
+	if ok, err := b.Fast.Init(b.Options.Fast); !ok {
+		return false, errors.Wrap(err, "Unable to initialize fast backend")
+	}
+
+	if ok, err := b.Slow.Init(b.Options.Slow); !ok {
+		return false, errors.Wrap(err, "Unable to initialize slow backend")
+	}
+
+	return true, nil
+}
+
+// Load stored data
+func (b *TwoLevels) Load(id string, testCacheValidity bool) ([]byte, error) {
+	data, err := b.Fast.Load(id, testCacheValidity)
+	if err == nil && len(data) > 0 {
+		return data, nil
+	}
+
+	data, err = b.Slow.Load(id, testCacheValidity)
+	if err != nil || len(data) == 0 {
+		return data, err
+	}
+
+	lifetime, ok := b.remainingLifetime(id)
+	if !ok {
+		return data, nil
+	}
+
+	b.Fast.Save(data, id, []string{twoLevelsFilledTag}, b.fastLifetime(lifetime))
+	return data, nil
+}
+
+// Test if key exists
+func (b *TwoLevels) Test(id string) bool {
+	return b.Fast.Test(id) || b.Slow.Test(id)
+}
+
+// Save data by key
+func (b *TwoLevels) Save(data []byte, id string, tags []string, specificLifetime int64) error {
+	if err := b.Slow.Save(data, id, tags, specificLifetime); err != nil {
+		return err
+	}
+
+	if err := b.Fast.Save(data, id, tags, b.fastLifetime(specificLifetime)); err != nil {
+		b.Fast.Remove(id)
+		return errors.Wrapf(err, "Unable to save item '%s' into fast backend", id)
+	}
+
+	return nil
+}
+
+// Remove data by key
+func (b *TwoLevels) Remove(id string) error {
+	ferr := b.Fast.Remove(id)
+	if err := b.Slow.Remove(id); err != nil {
+		return err
+	}
+
+	return ferr
+}
+
+// Clear stored data by tags
+func (b *TwoLevels) Clear(mode int64, tags []string) error {
+	if err := b.Slow.Clear(mode, tags); err != nil {
+		return err
+	}
+
+	switch mode {
+	case CleaningModeMatchingTag:
+		if err := b.Fast.Clear(mode, tags); err != nil {
+			return err
+		}
+
+		return b.Fast.Clear(CleaningModeMatchingAnyTag, []string{twoLevelsFilledTag})
+
+	case CleaningModeMatchingAnyTag:
+		return b.Fast.Clear(mode, append([]string{twoLevelsFilledTag}, tags...))
+	}
+
+	return b.Fast.Clear(mode, tags)
+}
+
+// IDs returns ids of items stored in slow backend
+func (b *TwoLevels) IDs() ([]string, error) {
+	ext, err := b.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.IDs()
+}
+
+// Tags returns tags of items stored in slow backend
+func (b *TwoLevels) Tags() ([]string, error) {
+	ext, err := b.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.Tags()
+}
+
+// IDsMatchingTags returns ids of items tagged with all of the tags
+func (b *TwoLevels) IDsMatchingTags(tags []string) ([]string, error) {
+	ext, err := b.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.IDsMatchingTags(tags)
+}
+
+// Metadata returns metadata of item stored in slow backend
+func (b *TwoLevels) Metadata(id string) (*Metadata, error) {
+	ext, err := b.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.Metadata(id)
+}
+
+// Stats returns usage of slow backend with evictions of both backends
+func (b *TwoLevels) Stats() *Stats {
+	stats := &Stats{}
+	if s, ok := b.Slow.(StatsInterface); ok {
+		stats = s.Stats()
+	}
+
+	if s, ok := b.Fast.(StatsInterface); ok {
+		stats.Evictions += s.Stats().Evictions
+	}
+
+	return stats
+}
+
+// Returns slow backend as extended backend
+func (b *TwoLevels) extended() (ExtendedInterface, error) {
+	if ext, ok := b.Slow.(ExtendedInterface); ok {
+		return ext, nil
+	}
+
+	return nil, errors.New("Slow backend does not support listing of items")
+}
+
+// Close closes underlying backends
+func (b *TwoLevels) Close() {
+	if c, ok := b.Fast.(interface{ Close() }); ok {
+		c.Close()
+	}
+
+	if c, ok := b.Slow.(interface{ Close() }); ok {
+		c.Close()
+	}
+}
+
+// Returns lifetime of item in fast backend
+func (b *TwoLevels) fastLifetime(specificLifetime int64) int64 {
+	if b.Options.FastLifetime > 0 && (specificLifetime <= 0 || specificLifetime > b.Options.FastLifetime) {
+		return b.Options.FastLifetime
+	}
+
+	return specificLifetime
+}
+
+// Returns seconds left until item stored in slow backend expires, zero if unknown or never
+// False is returned if item expires before it could be copied into fast backend
+func (b *TwoLevels) remainingLifetime(id string) (int64, bool) {
+	ext, ok := b.Slow.(ExtendedInterface)
+	if !ok {
+		return 0, true
+	}
+
+	meta, err := ext.Metadata(id)
+	if err != nil || meta.Expires == 0 {
+		return 0, true
+	}
+
+	remaining := meta.Expires - time.Now().Unix()
+	return remaining, remaining > 0
+}
+
+// NewTwoLevelsBackendCache creates new two levels backend cache
+func NewTwoLevelsBackendCache(options config.Config) (bi Interface, err error) {
+	cfg := &TwoLevelsConfig{}
+	cfg.Defaults()
+	if err := cfg.Populate(options); err != nil {
+		return nil, errors.Wrap(err, "Failed to create two levels backend cache object")
+	}
+
+	b := &TwoLevels{
+		Options: cfg,
+	}
+
+	if b.Fast, err = NewBackendCache(cfg.Fast.GetString("type"), cfg.Fast); err != nil {
+		return nil, errors.Wrap(err, "Failed to create fast backend")
+	}
+
+	if b.Slow, err = NewBackendCache(cfg.Slow.GetString("type"), cfg.Slow); err != nil {
+		return nil, errors.Wrap(err, "Failed to create slow backend")
+	}
+
+	return b, nil
+}
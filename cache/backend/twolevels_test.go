@@ -0,0 +1,37 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+var _ ExtendedInterface = (*TwoLevels)(nil)
+
+func TestTwoLevelsBackfillLifetime(t *testing.T) {
+	fast := newTestMemory(t, nil)
+	slow := newTestMemory(t, nil)
+	b := &TwoLevels{
+		Options: &TwoLevelsConfig{FastLifetime: 60},
+		Fast:    fast,
+		Slow:    slow,
+	}
+
+	slow.Save([]byte("short"), "short", nil, 5)
+	slow.Save([]byte("long"), "long", nil, 600)
+	slow.Save([]byte("forever"), "forever", nil, 0)
+
+	for id, want := range map[string]int64{"short": 5, "long": 60, "forever": 60} {
+		if data, err := b.Load(id, true); err != nil || len(data) == 0 {
+			t.Fatalf("Load(%s) = %q, %v", id, data, err)
+		}
+
+		meta, err := fast.Metadata(id)
+		if err != nil {
+			t.Fatalf("item %s was not copied into fast backend: %v", id, err)
+		}
+
+		if left := meta.Expires - time.Now().Unix(); left <= 0 || left > want {
+			t.Fatalf("item %s lives %d seconds in fast backend, want at most %d", id, left, want)
+		}
+	}
+}
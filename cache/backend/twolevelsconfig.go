@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// TwoLevelsConfig represents two levels backend cache configuration
+type TwoLevelsConfig struct {
+	Type         string
+	FastLifetime int64
+	Fast         config.Config
+	Slow         config.Config
+}
+
+// Populate populates Config values using given Config source
+func (c *TwoLevelsConfig) Populate(cfg config.Config) error {
+	if fcfg := cfg.Get("fast"); fcfg != nil {
+		c.Fast = fcfg
+	}
+
+	if scfg := cfg.Get("slow"); scfg != nil {
+		c.Slow = scfg
+	}
+
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *TwoLevelsConfig) Defaults() error {
+	c.Type = "twolevels"
+	c.FastLifetime = 60
+
+	if c.Fast == nil {
+		c.Fast = config.NewBridge()
+		c.Fast.Set("type", TYPEMemory)
+	}
+
+	if c.Slow == nil {
+		c.Slow = config.NewBridge()
+	}
+
+	return nil
+}
+
+// Valid validates the configuration
+func (c *TwoLevelsConfig) Valid() error {
+	if c.Fast.GetString("type") == "" {
+		return errors.New("Fast backend type is not set")
+	}
+
+	if c.Slow.GetString("type") == "" {
+		return errors.New("Slow backend type is not set")
+	}
+
+	return nil
+}
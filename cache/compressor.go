@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Compressor types
+const (
+	CompressorGzip    = "gzip"
+	CompressorDeflate = "deflate"
+	CompressorFast    = "fast"
+)
+
+var (
+	compressorHandlers = map[string]func() (Compressor, error){}
+)
+
+func init() {
+	RegisterCompressor(CompressorGzip, NewGzipCompressor)
+	RegisterCompressor(CompressorDeflate, NewDeflateCompressor)
+	RegisterCompressor(CompressorFast, NewFastCompressor)
+}
+
+// Compressor compresses data stored in cache
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// NewCompressor creates a new compressor specified by type
+func NewCompressor(compressorType string) (Compressor, error) {
+	if f, ok := compressorHandlers[compressorType]; ok {
+		return f()
+	}
+
+	return nil, errors.Errorf("Unrecognized compressor type \"%v\"", compressorType)
+}
+
+// RegisterCompressor registers a handler for compressor creation
+func RegisterCompressor(compressorType string, handler func() (Compressor, error)) {
+	compressorHandlers[compressorType] = handler
+}
+
+// GzipCompressor compresses data with gzip
+type GzipCompressor struct{}
+
+// Compress compresses data
+func (c *GzipCompressor) Compress(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses data
+func (c *GzipCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+// NewGzipCompressor creates a new gzip compressor
+func NewGzipCompressor() (Compressor, error) {
+	return &GzipCompressor{}, nil
+}
+
+// DeflateCompressor compresses data with raw deflate
+type DeflateCompressor struct{}
+
+// Compress compresses data
+func (c *DeflateCompressor) Compress(data []byte) ([]byte, error) {
+	return deflate(data, flate.DefaultCompression)
+}
+
+// Decompress decompresses data
+func (c *DeflateCompressor) Decompress(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+// NewDeflateCompressor creates a new deflate compressor
+func NewDeflateCompressor() (Compressor, error) {
+	return &DeflateCompressor{}, nil
+}
+
+// FastCompressor compresses data with raw deflate at its best speed
+// It is meant for large entries written often, where zstd at low levels would be used.
+// zstd itself is not provided as the module has no zstd dependency,
+// a zstd compressor can be added with RegisterCompressor
+type FastCompressor struct {
+	DeflateCompressor
+}
+
+// Compress compresses data
+func (c *FastCompressor) Compress(data []byte) ([]byte, error) {
+	return deflate(data, flate.BestSpeed)
+}
+
+// NewFastCompressor creates a new fast compressor
+func NewFastCompressor() (Compressor, error) {
+	return &FastCompressor{}, nil
+}
+
+// Compresses data with raw deflate at level
+func deflate(data []byte, level int) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w, err := flate.NewWriter(buf, level)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
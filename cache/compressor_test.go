@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompressorsRoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat("<div class=\"row\">cached view fragment</div>", 200))
+
+	for _, name := range []string{CompressorGzip, CompressorDeflate, CompressorFast} {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewCompressor(name)
+			if err != nil {
+				t.Fatalf("NewCompressor: %v", err)
+			}
+
+			compressed, err := c.Compress(data)
+			if err != nil {
+				t.Fatalf("Compress: %v", err)
+			}
+
+			if len(compressed) >= len(data) {
+				t.Fatalf("Compress did not reduce %d bytes, got %d", len(data), len(compressed))
+			}
+
+			decompressed, err := c.Decompress(compressed)
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+
+			if !bytes.Equal(decompressed, data) {
+				t.Fatal("Decompress returned different data")
+			}
+		})
+	}
+}
@@ -13,6 +13,11 @@ type Config struct {
 	ExtendedBackend         bool
 	WriteControl            bool
 	CacheIDPrefix           string
+	EarlyRefreshBeta        float64
+	Serializer              string
+	Compressor              string
+	CompressionThreshold    int
+	StatsLogInterval        int64
 	Backend                 config.Config
 	Logger                  config.Config
 }
@@ -42,6 +47,10 @@ func (c *Config) Defaults() error {
 	c.Enable = true
 	c.AutomaticCleaningFactor = 900
 	c.CacheIDPrefix = ""
+	c.EarlyRefreshBeta = 1
+	c.Serializer = SerializerJSON
+	c.Compressor = ""
+	c.CompressionThreshold = 1024
 
 	if c.Backend == nil {
 		c.Backend = config.NewBridge()
@@ -1,9 +1,9 @@
 package cache
 
 import (
-	"encoding/json"
 	"math/rand"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 	"github.com/noxyicm/wsf/cache/backend"
@@ -16,11 +16,11 @@ import (
 
 // Public constants
 const (
-	CleaningModeAll = iota
-	CleaningModeOld
-	CleaningModeMatchingTag
-	CleaningModeNotMatchingTag
-	CleaningModeMatchingAnyTag
+	CleaningModeAll            = backend.CleaningModeAll
+	CleaningModeOld            = backend.CleaningModeOld
+	CleaningModeMatchingTag    = backend.CleaningModeMatchingTag
+	CleaningModeNotMatchingTag = backend.CleaningModeNotMatchingTag
+	CleaningModeMatchingAnyTag = backend.CleaningModeMatchingAnyTag
 )
 
 var (
@@ -35,8 +35,11 @@ type Interface interface {
 	Read(id string, object interface{}, testCacheValidity bool) bool
 	Test(id string) bool
 	Save(data []byte, id string, tags []string, specificLifetime int64) bool
+	Write(object interface{}, id string, tags []string, specificLifetime int64) bool
 	Remove(id string) bool
 	Clear(mode int64, tags []string) bool
+	Remember(id string, tags []string, specificLifetime int64, fn func() ([]byte, error)) ([]byte, error)
+	RememberObject(id string, object interface{}, tags []string, specificLifetime int64, fn func() (interface{}, error)) error
 	Error() error
 }
 
@@ -45,9 +48,14 @@ type Core struct {
 	Options         *Config
 	Logger          *log.Log
 	Backend         backend.Interface
+	Serializer      Serializer
+	Compressor      Compressor
 	ExtendedBackend bool
 	lastError       error
 	lastID          string
+	flight          flightGroup
+	counters        counters
+	statsLogStop    chan bool
 	mur             sync.RWMutex
 }
 
@@ -68,6 +76,11 @@ func (c *Core) Init(options *Config) (bool, error) {
 		c.Logger = lg.(*log.Log)
 	}
 
+	if err := c.setupCodecs(); err != nil {
+		return false, err
+	}
+
+	c.startStatsLog()
 	return c.Backend.Init(c.Options.Backend)
 }
 
@@ -101,10 +114,18 @@ func (c *Core) Load(id string, testCacheValidity bool) ([]byte, bool) {
 	}
 
 	if len(data) == 0 {
+		c.counters.miss()
 		return nil, false
 	}
 
-	return data, true
+	c.counters.hit(len(data))
+	e, err := c.decode(data)
+	if err != nil {
+		c.lastError = err
+		return nil, false
+	}
+
+	return e.Data, true
 }
 
 // Read loads data from cache and unmarshals it into object
@@ -132,10 +153,18 @@ func (c *Core) Read(id string, object interface{}, testCacheValidity bool) bool
 	}
 
 	if len(data) == 0 {
+		c.counters.miss()
 		return false
 	}
 
-	if err := json.Unmarshal(data, object); err != nil {
+	c.counters.hit(len(data))
+	e, err := c.decode(data)
+	if err != nil {
+		c.lastError = err
+		return false
+	}
+
+	if err := c.unmarshal(e, object); err != nil {
 		c.lastError = errors.Wrap(err, "Unable to deserialize data")
 		return false
 	}
@@ -166,6 +195,26 @@ func (c *Core) Test(id string) bool {
 
 // Save saves data into cache
 func (c *Core) Save(data []byte, id string, tags []string, specificLifetime int64) bool {
+	return c.saveEntry(&Entry{Data: data}, id, tags, specificLifetime)
+}
+
+// Write serializes object and saves it into cache
+func (c *Core) Write(object interface{}, id string, tags []string, specificLifetime int64) bool {
+	if !c.Options.Enable {
+		return false
+	}
+
+	data, err := c.Serializer.Marshal(object)
+	if err != nil {
+		c.lastError = errors.Wrap(err, "Unable to serialize data")
+		return false
+	}
+
+	return c.saveEntry(&Entry{Serializer: c.Options.Serializer, Data: data}, id, tags, specificLifetime)
+}
+
+// Saves entry into cache
+func (c *Core) saveEntry(e *Entry, id string, tags []string, specificLifetime int64) bool {
 	if !c.Options.Enable {
 		return false
 	}
@@ -202,8 +251,15 @@ func (c *Core) Save(data []byte, id string, tags []string, specificLifetime int6
 		}
 	}
 
-	if specificLifetime == 0 {
-		specificLifetime = int64(c.Options.Backend.GetInt("lifetime"))
+	specificLifetime = c.lifetime(specificLifetime)
+	if specificLifetime > 0 {
+		e.Expires = time.Now().Add(time.Duration(specificLifetime) * time.Second)
+	}
+
+	data, err := c.encode(e)
+	if err != nil {
+		c.lastError = err
+		return false
 	}
 
 	c.Logger.Debugf("[WSF Cache]: Save item '%s'", nil, id)
@@ -214,6 +270,7 @@ func (c *Core) Save(data []byte, id string, tags []string, specificLifetime int6
 		return false
 	}
 
+	c.counters.saved(len(data))
 	if c.Options.WriteControl {
 		dataCheck, err := c.Backend.Load(id, true)
 		if err != nil {
@@ -251,6 +308,7 @@ func (c *Core) Remove(id string) bool {
 		return false
 	}
 
+	c.counters.removed()
 	return true
 }
 
@@ -291,8 +349,86 @@ func (c *Core) prepareID(id string) string {
 	return id
 }
 
+// Compresses entry data if it exceeds compression threshold and adds entry header
+func (c *Core) encode(e *Entry) ([]byte, error) {
+	if c.Compressor != nil && len(e.Data) >= c.Options.CompressionThreshold {
+		data, err := c.Compressor.Compress(e.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to compress data")
+		}
+
+		e.Data = data
+		e.Compressor = c.Options.Compressor
+	}
+
+	return encodeEntry(e), nil
+}
+
+// Strips entry header and decompresses entry data
+func (c *Core) decode(data []byte) (*Entry, error) {
+	e, _ := decodeEntry(data)
+	if e.Compressor == "" {
+		return e, nil
+	}
+
+	cmp := c.Compressor
+	if e.Compressor != c.Options.Compressor || cmp == nil {
+		var err error
+		if cmp, err = NewCompressor(e.Compressor); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := cmp.Decompress(e.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to decompress data")
+	}
+
+	e.Data = data
+	e.Compressor = ""
+	return e, nil
+}
+
+// Unmarshals entry data into object using the serializer it was saved with
+// Raw data is unmarshaled using configured serializer
+func (c *Core) unmarshal(e *Entry, object interface{}) error {
+	srl := c.Serializer
+	if e.Serializer != "" && e.Serializer != c.Options.Serializer {
+		var err error
+		if srl, err = NewSerializer(e.Serializer); err != nil {
+			return err
+		}
+	}
+
+	return srl.Unmarshal(e.Data, object)
+}
+
+// Creates configured serializer and compressor
+func (c *Core) setupCodecs() (err error) {
+	if c.Serializer, err = NewSerializer(c.Options.Serializer); err != nil {
+		return errors.Wrap(err, "[Core] Unable to create serializer")
+	}
+
+	c.Compressor = nil
+	if c.Options.Compressor != "" {
+		if c.Compressor, err = NewCompressor(c.Options.Compressor); err != nil {
+			return errors.Wrap(err, "[Core] Unable to create compressor")
+		}
+	}
+
+	return nil
+}
+
+func (c *Core) lifetime(specificLifetime int64) int64 {
+	if specificLifetime == 0 {
+		return int64(c.Options.Backend.GetInt("lifetime"))
+	}
+
+	return specificLifetime
+}
+
 func (c *Core) validateIDOrTag(s string) error {
-	if string(s[0:9]) == "internal-" {
+	if strings.HasPrefix(s, "internal-") {
 		return errors.New("'internal-*' ids or tags are reserved")
 	}
 
@@ -323,11 +459,17 @@ func NewCore(cacheType string, options config.Config) (*Core, error) {
 		Options: cfg,
 	}
 
+	if err := cc.setupCodecs(); err != nil {
+		return nil, err
+	}
+
 	adp, err := backend.NewBackendCache(cfg.Backend.GetString("type"), cfg.Backend)
 	if err != nil {
 		return nil, errors.Wrap(err, "[Core] Unable to create underliyng backend")
 	}
 	cc.Backend = adp
+	_, cc.ExtendedBackend = adp.(backend.ExtendedInterface)
+	cc.counters.reset()
 
 	return cc, nil
 }
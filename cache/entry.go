@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+// Entry header is written in front of data saved through Core
+// and is stripped transparently by Load and Read
+var entryMagic = []byte{0, 'w', 's', 'f'}
+
+// Entry header versions
+const (
+	// expires and delta
+	entryVersion1 = 1
+
+	// expires, delta, serializer and compressor
+	entryVersion2 = 2
+)
+
+// Entry holds metadata of a stored cache item
+type Entry struct {
+	// Expires is a time of item expiration, zero if item never expires
+	Expires time.Time
+
+	// Delta is a time it took to compute the item
+	Delta time.Duration
+
+	// Serializer used to marshal the object, empty for raw data
+	Serializer string
+
+	// Compressor used to compress data, empty if data is not compressed
+	Compressor string
+
+	Data []byte
+}
+
+// encodeEntry prepends entry header to data
+func encodeEntry(e *Entry) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, 4+1+8+8+2+len(e.Serializer)+len(e.Compressor)+len(e.Data)))
+	buf.Write(entryMagic)
+	buf.WriteByte(entryVersion2)
+
+	var expires int64
+	if !e.Expires.IsZero() {
+		expires = e.Expires.UnixNano() / int64(time.Millisecond)
+	}
+
+	binary.Write(buf, binary.BigEndian, expires)
+	binary.Write(buf, binary.BigEndian, int64(e.Delta/time.Millisecond))
+	buf.WriteByte(byte(len(e.Serializer)))
+	buf.WriteString(e.Serializer)
+	buf.WriteByte(byte(len(e.Compressor)))
+	buf.WriteString(e.Compressor)
+	buf.Write(e.Data)
+	return buf.Bytes()
+}
+
+// decodeEntry parses entry header
+// Data saved without header is returned as is with a false flag
+func decodeEntry(data []byte) (*Entry, bool) {
+	if len(data) < 4+1+8+8 || !bytes.Equal(data[:4], entryMagic) {
+		return &Entry{Data: data}, false
+	}
+
+	version := data[4]
+	if version != entryVersion1 && version != entryVersion2 {
+		return &Entry{Data: data}, false
+	}
+
+	e := &Entry{
+		Delta: time.Duration(binary.BigEndian.Uint64(data[13:21])) * time.Millisecond,
+	}
+
+	if expires := int64(binary.BigEndian.Uint64(data[5:13])); expires > 0 {
+		e.Expires = time.Unix(0, expires*int64(time.Millisecond))
+	}
+
+	payload := data[21:]
+	if version == entryVersion2 {
+		var ok bool
+		if e.Serializer, payload, ok = entryString(payload); !ok {
+			return &Entry{Data: data}, false
+		}
+
+		if e.Compressor, payload, ok = entryString(payload); !ok {
+			return &Entry{Data: data}, false
+		}
+	}
+
+	e.Data = payload
+	return e, true
+}
+
+// Reads length prefixed string of entry header
+func entryString(data []byte) (string, []byte, bool) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return "", data, false
+	}
+
+	n := int(data[0])
+	return string(data[1 : 1+n]), data[1+n:], true
+}
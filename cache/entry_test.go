@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/log"
+)
+
+func TestEntryRoundTrip(t *testing.T) {
+	e := &Entry{
+		Expires:    time.Unix(1700000000, 0),
+		Delta:      150 * time.Millisecond,
+		Serializer: SerializerMsgpack,
+		Compressor: CompressorFast,
+		Data:       []byte("payload"),
+	}
+
+	decoded, ok := decodeEntry(encodeEntry(e))
+	if !ok {
+		t.Fatal("decodeEntry did not recognize entry header")
+	}
+
+	if !reflect.DeepEqual(decoded, e) {
+		t.Fatalf("decodeEntry = %+v, want %+v", decoded, e)
+	}
+
+	// Data saved without header is returned as is
+	raw := []byte("raw data saved by backend")
+	if decoded, ok := decodeEntry(raw); ok || !bytes.Equal(decoded.Data, raw) {
+		t.Fatalf("decodeEntry of raw data = %+v, %v", decoded, ok)
+	}
+}
+
+// Creates core on memory backend
+func newTestCore(t *testing.T, options map[string]interface{}) *Core {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{"backend": map[string]interface{}{"type": "memory"}})
+	cfg.Merge(options)
+
+	cc, err := NewCore("core", cfg)
+	if err != nil {
+		t.Fatalf("NewCore: %v", err)
+	}
+
+	// Disabled logger
+	cc.Logger = &log.Log{}
+	return cc
+}
+
+func TestCoreCompressedWriteRead(t *testing.T) {
+	item := serializerItem{Name: strings.Repeat("fragment ", 100), Tags: []string{"view"}}
+
+	for _, serializer := range []string{SerializerJSON, SerializerGob, SerializerMsgpack} {
+		t.Run(serializer, func(t *testing.T) {
+			cc := newTestCore(t, map[string]interface{}{
+				"serializer":           serializer,
+				"compressor":           CompressorFast,
+				"compressionThreshold": 64,
+			})
+
+			if !cc.Write(item, "item", nil, 0) {
+				t.Fatalf("Write: %v", cc.Error())
+			}
+
+			stored, err := cc.Backend.Load("item", true)
+			if err != nil {
+				t.Fatalf("backend Load: %v", err)
+			}
+
+			e, ok := decodeEntry(stored)
+			if !ok || e.Serializer != serializer || e.Compressor != CompressorFast {
+				t.Fatalf("stored entry = %+v", e)
+			}
+
+			// Entry is read back by its recorded codecs whatever core is configured with
+			reader := newTestCore(t, nil)
+			reader.Backend = cc.Backend
+
+			var decoded serializerItem
+			if !reader.Read("item", &decoded, true) {
+				t.Fatalf("Read: %v", reader.Error())
+			}
+
+			if decoded.Name != item.Name || !reflect.DeepEqual(decoded.Tags, item.Tags) {
+				t.Fatalf("Read = %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestCoreSmallEntryNotCompressed(t *testing.T) {
+	cc := newTestCore(t, map[string]interface{}{"compressor": CompressorGzip, "compressionThreshold": 1024})
+
+	if !cc.Save([]byte("small"), "small", nil, 0) {
+		t.Fatalf("Save: %v", cc.Error())
+	}
+
+	stored, _ := cc.Backend.Load("small", true)
+	if e, ok := decodeEntry(stored); !ok || e.Compressor != "" {
+		t.Fatalf("stored entry = %+v", e)
+	}
+
+	if data, ok := cc.Load("small", true); !ok || string(data) != "small" {
+		t.Fatalf("Load = %q, %v", data, ok)
+	}
+}
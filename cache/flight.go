@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// flightCall is an in-progress or completed computation of a cache item
+type flightCall struct {
+	wg    sync.WaitGroup
+	entry *Entry
+	err   error
+}
+
+// flightGroup coalesces concurrent computations of the same cache item
+type flightGroup struct {
+	calls map[string]*flightCall
+	mu    sync.Mutex
+}
+
+// do executes fn once for concurrent callers of the same key
+// Callers arriving while fn runs wait for and share its result
+func (g *flightGroup) do(key string, fn func() (*Entry, error)) (*Entry, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.entry, call.err
+	}
+
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		call.wg.Done()
+	}()
+
+	// Waiters of a panicked computation receive this error
+	call.err = errors.Errorf("Computation of cache item '%s' panicked", key)
+	call.entry, call.err = fn()
+	return call.entry, call.err
+}
+
+// running returns true if computation of key is in progress
+func (g *flightGroup) running(key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.calls[key]
+	return ok
+}
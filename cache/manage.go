@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	"github.com/noxyicm/wsf/cache/backend"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Metadata holds metadata of a cache item
+type Metadata struct {
+	ID         string
+	Expires    time.Time
+	Tags       []string
+	Size       int64
+	Serializer string
+	Compressor string
+	Delta      time.Duration
+}
+
+// IDs returns ids of stored items
+func (c *Core) IDs() ([]string, error) {
+	ext, err := c.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := ext.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.stripIDs(ids), nil
+}
+
+// Tags returns tags of stored items
+func (c *Core) Tags() ([]string, error) {
+	ext, err := c.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.Tags()
+}
+
+// IDsMatchingTags returns ids of items tagged with all of the tags
+func (c *Core) IDsMatchingTags(tags []string) ([]string, error) {
+	if err := c.validateTags(tags); err != nil {
+		return nil, err
+	}
+
+	ext, err := c.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := ext.IDsMatchingTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.stripIDs(ids), nil
+}
+
+// Metadata returns metadata of stored item or nil if item does not exist
+func (c *Core) Metadata(id string) (*Metadata, error) {
+	id = c.prepareID(id)
+	if err := c.validateIDOrTag(id); err != nil {
+		return nil, err
+	}
+
+	ext, err := c.extended()
+	if err != nil {
+		return nil, err
+	}
+
+	bm, err := ext.Metadata(id)
+	if err != nil || bm == nil {
+		return nil, err
+	}
+
+	meta := &Metadata{
+		ID:   strings.TrimPrefix(id, c.Options.CacheIDPrefix),
+		Tags: bm.Tags,
+		Size: bm.Size,
+	}
+
+	if bm.Expires > 0 {
+		meta.Expires = time.Unix(bm.Expires, 0)
+	}
+
+	data, err := c.Backend.Load(id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if e, ok := decodeEntry(data); ok {
+		meta.Serializer = e.Serializer
+		meta.Compressor = e.Compressor
+		meta.Delta = e.Delta
+	}
+
+	return meta, nil
+}
+
+// Returns backend as extended backend
+func (c *Core) extended() (backend.ExtendedInterface, error) {
+	if ext, ok := c.Backend.(backend.ExtendedInterface); ok {
+		return ext, nil
+	}
+
+	return nil, errors.New("Cache backend does not support listing of items")
+}
+
+// Removes cache id prefix from ids skipping ids of other prefixes
+func (c *Core) stripIDs(ids []string) []string {
+	if c.Options.CacheIDPrefix == "" {
+		return ids
+	}
+
+	stripped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if strings.HasPrefix(id, c.Options.CacheIDPrefix) {
+			stripped = append(stripped, strings.TrimPrefix(id, c.Options.CacheIDPrefix))
+		}
+	}
+
+	return stripped
+}
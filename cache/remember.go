@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Remember returns data stored by id or computes it with fn and saves it
+// Concurrent misses of the same id are computed once and entries close to expiration
+// are recomputed early with probability growing towards expiration
+func (c *Core) Remember(id string, tags []string, specificLifetime int64, fn func() ([]byte, error)) ([]byte, error) {
+	e, err := c.remember(id, tags, specificLifetime, func() (*Entry, error) {
+		data, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Entry{Data: data}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Data, nil
+}
+
+// RememberObject is like Remember but serializes value returned by fn
+// and unmarshals stored or computed data into object
+func (c *Core) RememberObject(id string, object interface{}, tags []string, specificLifetime int64, fn func() (interface{}, error)) error {
+	e, err := c.remember(id, tags, specificLifetime, func() (*Entry, error) {
+		v, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := c.Serializer.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to serialize data")
+		}
+
+		return &Entry{Serializer: c.Options.Serializer, Data: data}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(c.unmarshal(e, object), "Unable to deserialize data")
+}
+
+func (c *Core) remember(id string, tags []string, specificLifetime int64, fn func() (*Entry, error)) (*Entry, error) {
+	if !c.Options.Enable {
+		return fn()
+	}
+
+	pid := c.prepareID(id)
+	if err := c.validateIDOrTag(pid); err != nil {
+		c.lastError = err
+		return nil, err
+	}
+
+	if err := c.validateTags(tags); err != nil {
+		c.lastError = err
+		return nil, err
+	}
+
+	c.Logger.Debugf("[WSF Cache]: Remember item '%s'", nil, pid)
+	var cached *Entry
+	data, err := c.Backend.Load(pid, true)
+	if err != nil {
+		c.lastError = err
+	} else if len(data) == 0 {
+		c.counters.miss()
+	} else {
+		c.counters.hit(len(data))
+		if cached, err = c.decode(data); err != nil {
+			c.lastError = err
+		} else if !c.refreshEarly(cached) || c.flight.running(pid) {
+			return cached, nil
+		} else {
+			c.Logger.Debugf("[WSF Cache]: Early refresh of item '%s'", nil, pid)
+		}
+	}
+
+	return c.flight.do(pid, func() (*Entry, error) {
+		start := time.Now()
+		e, err := fn()
+		if err != nil {
+			if cached != nil {
+				c.Logger.Warningf("[WSF Cache]::remember(): Early refresh of item '%s' failed: %s", nil, pid, err.Error())
+				return cached, nil
+			}
+
+			return nil, err
+		}
+
+		e.Delta = time.Since(start)
+		computed := &Entry{Serializer: e.Serializer, Data: e.Data}
+		if !c.saveEntry(e, id, tags, specificLifetime) {
+			c.Logger.Warningf("[WSF Cache]::remember(): Failed to save item '%s'", nil, pid)
+		}
+
+		return computed, nil
+	})
+}
+
+// refreshEarly decides if entry should be recomputed before its expiration
+// The closer the expiration and the longer the computation, the higher the probability
+func (c *Core) refreshEarly(e *Entry) bool {
+	if c.Options.EarlyRefreshBeta <= 0 || e.Expires.IsZero() || e.Delta <= 0 {
+		return false
+	}
+
+	gap := -float64(e.Delta) * c.Options.EarlyRefreshBeta * math.Log(1-rand.Float64())
+	return time.Now().Add(time.Duration(gap)).After(e.Expires)
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Serializer types
+const (
+	SerializerJSON    = "json"
+	SerializerGob     = "gob"
+	SerializerMsgpack = "msgpack"
+)
+
+var (
+	serializerHandlers = map[string]func() (Serializer, error){}
+)
+
+func init() {
+	RegisterSerializer(SerializerJSON, NewJSONSerializer)
+	RegisterSerializer(SerializerGob, NewGobSerializer)
+	RegisterSerializer(SerializerMsgpack, NewMsgpackSerializer)
+}
+
+// Serializer converts objects to bytes stored in cache and back
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// NewSerializer creates a new serializer specified by type
+func NewSerializer(serializerType string) (Serializer, error) {
+	if f, ok := serializerHandlers[serializerType]; ok {
+		return f()
+	}
+
+	return nil, errors.Errorf("Unrecognized serializer type \"%v\"", serializerType)
+}
+
+// RegisterSerializer registers a handler for serializer creation
+func RegisterSerializer(serializerType string, handler func() (Serializer, error)) {
+	serializerHandlers[serializerType] = handler
+}
+
+// JSONSerializer serializes objects as json
+type JSONSerializer struct{}
+
+// Marshal encodes v as json
+func (s *JSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes json data into v
+func (s *JSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewJSONSerializer creates a new json serializer
+func NewJSONSerializer() (Serializer, error) {
+	return &JSONSerializer{}, nil
+}
+
+// GobSerializer serializes objects using encoding/gob
+// Concrete types stored in interface values must be registered with gob.Register
+type GobSerializer struct{}
+
+// Marshal encodes v as gob
+func (s *GobSerializer) Marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data into v
+func (s *GobSerializer) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// NewGobSerializer creates a new gob serializer
+func NewGobSerializer() (Serializer, error) {
+	return &GobSerializer{}, nil
+}
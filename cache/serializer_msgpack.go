@@ -0,0 +1,462 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// MsgpackSerializer serializes objects using MessagePack format
+// Struct fields are encoded as map keys named by "msgpack" tag or field name,
+// time values are encoded as RFC3339 strings
+type MsgpackSerializer struct{}
+
+// Marshal encodes v as msgpack
+func (s *MsgpackSerializer) Marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := msgpackEncode(buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes msgpack data into v
+func (s *MsgpackSerializer) Unmarshal(data []byte, v interface{}) error {
+	rd := bytes.NewReader(data)
+	value, err := msgpackDecode(rd)
+	if err != nil {
+		return err
+	}
+
+	if rd.Len() > 0 {
+		return errors.Errorf("Unexpected %d bytes after msgpack value", rd.Len())
+	}
+
+	if ptr, ok := v.(*interface{}); ok {
+		*ptr = value
+		return nil
+	}
+
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+		WeaklyTypedInput: true,
+		TagName:          "msgpack",
+		Result:           v,
+	})
+	if err != nil {
+		return err
+	}
+
+	return dec.Decode(value)
+}
+
+// NewMsgpackSerializer creates a new msgpack serializer
+func NewMsgpackSerializer() (Serializer, error) {
+	return &MsgpackSerializer{}, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func msgpackEncode(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteByte(0xc0)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		msgpackWriteString(buf, v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+
+		return msgpackEncode(buf, v.Elem())
+
+	case reflect.Bool:
+		if v.Bool() {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		msgpackWriteInt(buf, v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := v.Uint()
+		if n <= math.MaxInt64 {
+			msgpackWriteInt(buf, int64(n))
+		} else {
+			buf.WriteByte(0xcf)
+			binary.Write(buf, binary.BigEndian, n)
+		}
+
+	case reflect.Float32:
+		buf.WriteByte(0xca)
+		binary.Write(buf, binary.BigEndian, math.Float32bits(float32(v.Float())))
+
+	case reflect.Float64:
+		buf.WriteByte(0xcb)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(v.Float()))
+
+	case reflect.String:
+		msgpackWriteString(buf, v.String())
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			msgpackWriteHeader(buf, len(b), 0, 0xc4, 0xc5, 0xc6, -1)
+			buf.Write(b)
+			return nil
+		}
+
+		msgpackWriteHeader(buf, v.Len(), 0x90, 0, 0xdc, 0xdd, 16)
+		for i := 0; i < v.Len(); i++ {
+			if err := msgpackEncode(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+
+		msgpackWriteHeader(buf, v.Len(), 0x80, 0, 0xde, 0xdf, 16)
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := msgpackEncode(buf, iter.Key()); err != nil {
+				return err
+			}
+
+			if err := msgpackEncode(buf, iter.Value()); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		names := make([]string, 0, t.NumField())
+		fields := make([]reflect.Value, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			name := field.Name
+			if tag := field.Tag.Get("msgpack"); tag != "" {
+				if tag = strings.Split(tag, ",")[0]; tag == "-" {
+					continue
+				} else if tag != "" {
+					name = tag
+				}
+			}
+
+			names = append(names, name)
+			fields = append(fields, v.Field(i))
+		}
+
+		msgpackWriteHeader(buf, len(names), 0x80, 0, 0xde, 0xdf, 16)
+		for i := range names {
+			msgpackWriteString(buf, names[i])
+			if err := msgpackEncode(buf, fields[i]); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return errors.Errorf("Unsupported msgpack type '%s'", v.Type())
+	}
+
+	return nil
+}
+
+func msgpackWriteInt(buf *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0 && n <= 127:
+		buf.WriteByte(byte(n))
+
+	case n < 0 && n >= -32:
+		buf.WriteByte(byte(int8(n)))
+
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(int8(n)))
+
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(n))
+
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(n))
+
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
+
+func msgpackWriteString(buf *bytes.Buffer, s string) {
+	msgpackWriteHeader(buf, len(s), 0xa0, 0xd9, 0xda, 0xdb, 32)
+	buf.WriteString(s)
+}
+
+// Writes a length header using fix format for lengths below fixLimit
+// and 8, 16 or 32 bit formats otherwise, zero code skips the format
+func msgpackWriteHeader(buf *bytes.Buffer, n int, fix byte, code8 byte, code16 byte, code32 byte, fixLimit int) {
+	switch {
+	case n < fixLimit:
+		buf.WriteByte(fix | byte(n))
+
+	case code8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(code8)
+		buf.WriteByte(byte(n))
+
+	case n <= math.MaxUint16:
+		buf.WriteByte(code16)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+
+	default:
+		buf.WriteByte(code32)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+// Decodes a single msgpack value into nil, bool, int64, uint64, float32, float64,
+// string, []byte, []interface{} or map[string]interface{}
+func msgpackDecode(rd *bytes.Reader) (interface{}, error) {
+	code, err := rd.ReadByte()
+	if err != nil {
+		return nil, errors.Wrap(err, "Unexpected end of msgpack data")
+	}
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+
+	case code&0xf0 == 0x80:
+		return msgpackDecodeMap(rd, int(code&0x0f))
+
+	case code&0xf0 == 0x90:
+		return msgpackDecodeArray(rd, int(code&0x0f))
+
+	case code&0xe0 == 0xa0:
+		return msgpackReadString(rd, int(code&0x1f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+
+	case 0xc2:
+		return false, nil
+
+	case 0xc3:
+		return true, nil
+
+	case 0xc4, 0xc5, 0xc6:
+		n, err := msgpackReadLength(rd, code-0xc4)
+		if err != nil {
+			return nil, err
+		}
+
+		return msgpackReadBytes(rd, n)
+
+	case 0xca:
+		var n uint32
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return math.Float32frombits(n), err
+
+	case 0xcb:
+		var n uint64
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return math.Float64frombits(n), err
+
+	case 0xcc:
+		var n uint8
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return int64(n), err
+
+	case 0xcd:
+		var n uint16
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return int64(n), err
+
+	case 0xce:
+		var n uint32
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return int64(n), err
+
+	case 0xcf:
+		var n uint64
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return n, err
+
+	case 0xd0:
+		var n int8
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return int64(n), err
+
+	case 0xd1:
+		var n int16
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return int64(n), err
+
+	case 0xd2:
+		var n int32
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return int64(n), err
+
+	case 0xd3:
+		var n int64
+		err := binary.Read(rd, binary.BigEndian, &n)
+		return n, err
+
+	case 0xd9, 0xda, 0xdb:
+		n, err := msgpackReadLength(rd, code-0xd9)
+		if err != nil {
+			return nil, err
+		}
+
+		return msgpackReadString(rd, n)
+
+	case 0xdc, 0xdd:
+		n, err := msgpackReadLength(rd, code-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+
+		return msgpackDecodeArray(rd, n)
+
+	case 0xde, 0xdf:
+		n, err := msgpackReadLength(rd, code-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+
+		return msgpackDecodeMap(rd, n)
+	}
+
+	return nil, errors.Errorf("Unsupported msgpack format 0x%x", code)
+}
+
+// Reads 8, 16 or 32 bit length for size 0, 1 or 2
+func msgpackReadLength(rd *bytes.Reader, size byte) (int, error) {
+	var err error
+	var n int
+	switch size {
+	case 0:
+		var l uint8
+		err = binary.Read(rd, binary.BigEndian, &l)
+		n = int(l)
+
+	case 1:
+		var l uint16
+		err = binary.Read(rd, binary.BigEndian, &l)
+		n = int(l)
+
+	default:
+		var l uint32
+		err = binary.Read(rd, binary.BigEndian, &l)
+		n = int(l)
+	}
+
+	if err != nil {
+		return 0, errors.Wrap(err, "Unexpected end of msgpack data")
+	}
+
+	return n, nil
+}
+
+func msgpackReadBytes(rd *bytes.Reader, n int) ([]byte, error) {
+	if n > rd.Len() {
+		return nil, errors.New("Unexpected end of msgpack data")
+	}
+
+	b := make([]byte, n)
+	rd.Read(b)
+	return b, nil
+}
+
+func msgpackReadString(rd *bytes.Reader, n int) (string, error) {
+	b, err := msgpackReadBytes(rd, n)
+	return string(b), err
+}
+
+// Every element takes at least one byte, so length is checked against remaining data
+// before anything is allocated
+func msgpackDecodeArray(rd *bytes.Reader, n int) ([]interface{}, error) {
+	if n > rd.Len() {
+		return nil, errors.New("Unexpected end of msgpack data")
+	}
+
+	values := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		value, err := msgpackDecode(rd)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// Every key and value takes at least one byte
+func msgpackDecodeMap(rd *bytes.Reader, n int) (map[string]interface{}, error) {
+	if n > rd.Len()/2 {
+		return nil, errors.New("Unexpected end of msgpack data")
+	}
+
+	values := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := msgpackDecode(rd)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := msgpackDecode(rd)
+		if err != nil {
+			return nil, err
+		}
+
+		switch k := key.(type) {
+		case string:
+			values[k] = value
+
+		case []byte:
+			values[string(k)] = value
+
+		default:
+			values[fmt.Sprint(k)] = value
+		}
+	}
+
+	return values, nil
+}
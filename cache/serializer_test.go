@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type serializerItem struct {
+	Name    string    `msgpack:"name"`
+	Count   int       `msgpack:"count"`
+	Ratio   float64   `msgpack:"ratio"`
+	Active  bool      `msgpack:"active"`
+	Tags    []string  `msgpack:"tags"`
+	Created time.Time `msgpack:"created"`
+	Blob    []byte    `msgpack:"blob"`
+}
+
+func TestSerializersRoundTrip(t *testing.T) {
+	item := serializerItem{
+		Name:    "fragment",
+		Count:   -42,
+		Ratio:   0.25,
+		Active:  true,
+		Tags:    []string{"a", "b"},
+		Created: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		Blob:    []byte{0, 1, 2, 255},
+	}
+
+	for _, name := range []string{SerializerJSON, SerializerGob, SerializerMsgpack} {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewSerializer(name)
+			if err != nil {
+				t.Fatalf("NewSerializer: %v", err)
+			}
+
+			data, err := s.Marshal(item)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			var decoded serializerItem
+			if err := s.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, item) {
+				t.Fatalf("Unmarshal = %+v, want %+v", decoded, item)
+			}
+		})
+	}
+}
+
+func TestMsgpackGenericValues(t *testing.T) {
+	s, _ := NewSerializer(SerializerMsgpack)
+
+	value := map[string]interface{}{
+		"nil":    nil,
+		"int":    int64(1 << 40),
+		"string": "value",
+		"list":   []interface{}{int64(1), "two", 3.5},
+		"nested": map[string]interface{}{"ok": true},
+	}
+
+	data, err := s.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded interface{}
+	if err := s.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, value) {
+		t.Fatalf("Unmarshal = %#v, want %#v", decoded, value)
+	}
+}
+
+func TestMsgpackMalformedData(t *testing.T) {
+	s, _ := NewSerializer(SerializerMsgpack)
+
+	cases := map[string][]byte{
+		"array length beyond data": {0xdd, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"map length beyond data":   {0xdf, 0xff, 0xff, 0xff, 0xff, 0xa1, 'a'},
+		"string beyond data":       {0xdb, 0x7f, 0xff, 0xff, 0xff, 'a'},
+		"truncated":                {0x92, 0x01},
+		"trailing bytes":           {0x01, 0x02},
+	}
+
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			var decoded interface{}
+			if err := s.Unmarshal(data, &decoded); err == nil {
+				t.Fatalf("Unmarshal = %#v, want error", decoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/noxyicm/wsf/cache/backend"
+)
+
+// Stats holds usage counters of a cache
+type Stats struct {
+	Hits         int64
+	Misses       int64
+	Saves        int64
+	Removes      int64
+	BytesRead    int64
+	BytesWritten int64
+	Since        time.Time
+
+	// Backend holds usage reported by backend, nil if backend does not report it
+	Backend *backend.Stats
+}
+
+// HitRatio returns ratio of hits to all reads
+func (s *Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// String returns stats formatted for logging
+func (s *Stats) String() string {
+	str := fmt.Sprintf(
+		"hits=%d misses=%d ratio=%.2f saves=%d removes=%d read=%dB written=%dB",
+		s.Hits, s.Misses, s.HitRatio(), s.Saves, s.Removes, s.BytesRead, s.BytesWritten,
+	)
+
+	if s.Backend != nil {
+		str += fmt.Sprintf(" entries=%d size=%dB evictions=%d", s.Backend.Entries, s.Backend.Size, s.Backend.Evictions)
+	}
+
+	return str
+}
+
+// counters of Core usage updated atomically
+type counters struct {
+	hits         int64
+	misses       int64
+	saves        int64
+	removes      int64
+	bytesRead    int64
+	bytesWritten int64
+	since        int64
+}
+
+func (c *counters) hit(size int) {
+	atomic.AddInt64(&c.hits, 1)
+	atomic.AddInt64(&c.bytesRead, int64(size))
+}
+
+func (c *counters) miss() {
+	atomic.AddInt64(&c.misses, 1)
+}
+
+func (c *counters) saved(size int) {
+	atomic.AddInt64(&c.saves, 1)
+	atomic.AddInt64(&c.bytesWritten, int64(size))
+}
+
+func (c *counters) removed() {
+	atomic.AddInt64(&c.removes, 1)
+}
+
+func (c *counters) reset() {
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+	atomic.StoreInt64(&c.saves, 0)
+	atomic.StoreInt64(&c.removes, 0)
+	atomic.StoreInt64(&c.bytesRead, 0)
+	atomic.StoreInt64(&c.bytesWritten, 0)
+	atomic.StoreInt64(&c.since, time.Now().UnixNano())
+}
+
+// Stats returns usage counters of the cache and its backend
+func (c *Core) Stats() *Stats {
+	s := &Stats{
+		Hits:         atomic.LoadInt64(&c.counters.hits),
+		Misses:       atomic.LoadInt64(&c.counters.misses),
+		Saves:        atomic.LoadInt64(&c.counters.saves),
+		Removes:      atomic.LoadInt64(&c.counters.removes),
+		BytesRead:    atomic.LoadInt64(&c.counters.bytesRead),
+		BytesWritten: atomic.LoadInt64(&c.counters.bytesWritten),
+		Since:        time.Unix(0, atomic.LoadInt64(&c.counters.since)),
+	}
+
+	if sb, ok := c.Backend.(backend.StatsInterface); ok {
+		s.Backend = sb.Stats()
+	}
+
+	return s
+}
+
+// ResetStats resets usage counters of the cache
+func (c *Core) ResetStats() {
+	c.counters.reset()
+}
+
+// LogStats writes usage counters into the log
+func (c *Core) LogStats() {
+	c.Logger.Infof("[WSF Cache]: Stats %s", nil, c.Stats().String())
+}
+
+// Writes usage counters into the log periodically
+func (c *Core) startStatsLog() {
+	c.mur.Lock()
+	defer c.mur.Unlock()
+
+	if c.Options.StatsLogInterval <= 0 || c.statsLogStop != nil {
+		return
+	}
+
+	c.statsLogStop = make(chan bool, 1)
+	go func(interval time.Duration, stop chan bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+
+			case <-ticker.C:
+				c.LogStats()
+			}
+		}
+	}(time.Duration(c.Options.StatsLogInterval)*time.Second, c.statsLogStop)
+}
+
+// StopStatsLog stops periodic logging of usage counters
+func (c *Core) StopStatsLog() {
+	c.mur.Lock()
+	defer c.mur.Unlock()
+
+	if c.statsLogStop != nil {
+		c.statsLogStop <- true
+		c.statsLogStop = nil
+	}
+}
@@ -245,7 +245,7 @@ func (c *ActionControllerBase) Invoke(ctx context.Context, ctrl ActionController
 		return err
 	}
 
-	values, err := c.resolveValues(m, ctrl, ctx)
+	values, err := c.resolveValues(m, ctx, ctrl, ctx)
 	if err != nil {
 		return err
 	}
@@ -337,11 +337,18 @@ func (c *ActionControllerBase) verifySignature(m reflect.Method) error {
 		return errors.Errorf("Action ( %s ) return value must be error type", m.Name)
 	}
 
+	for i := 2; i < m.Type.NumIn(); i++ {
+		if !isActionParamsType(m.Type.In(i)) {
+			return errors.Errorf("Action ( %s ) argument %d must be a struct or a pointer to struct", m.Name, i)
+		}
+	}
+
 	return nil
 }
 
-// resolveValues returns slice of call arguments for service Init method
-func (c *ActionControllerBase) resolveValues(m reflect.Method, args ...interface{}) (values []reflect.Value, err error) {
+// resolveValues returns slice of call arguments for action method
+// Arguments following provided args are action params bound from request
+func (c *ActionControllerBase) resolveValues(m reflect.Method, ctx context.Context, args ...interface{}) (values []reflect.Value, err error) {
 	for i := 0; i < m.Type.NumIn(); i++ {
 		v := m.Type.In(i)
 
@@ -359,6 +366,13 @@ func (c *ActionControllerBase) resolveValues(m reflect.Method, args ...interface
 				return nil, err
 			}
 
+			values = append(values, value)
+		} else if isActionParamsType(v) {
+			value, err := c.resolveParams(v, ctx)
+			if err != nil {
+				return nil, err
+			}
+
 			values = append(values, value)
 		} else {
 			values = append(values, reflect.Value{})
@@ -384,6 +398,25 @@ func (c *ActionControllerBase) resolveValue(v reflect.Type, arg interface{}) (re
 	return value, nil
 }
 
+// resolveParams returns action params of type v populated from request
+func (c *ActionControllerBase) resolveParams(v reflect.Type, ctx context.Context) (reflect.Value, error) {
+	ptr := v.Kind() == reflect.Ptr
+	if ptr {
+		v = v.Elem()
+	}
+
+	value := reflect.New(v)
+	if err := BindParams(ctx.Request(), value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if ptr {
+		return value, nil
+	}
+
+	return value.Elem(), nil
+}
+
 /*func (c *ActionControllerBase) initView() (vi view.Interface, err error) {
 	if !c.ParamBool("noViewRenderer") && c.Hlpr.HasHelper("viewRenderer") {
 		return nil, nil
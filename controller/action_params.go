@@ -0,0 +1,448 @@
+package controller
+
+import (
+	"encoding"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+// Struct tags of action params fields
+// Value is taken from the first source tagged on a field which holds it:
+//
+//	type PostParams struct {
+//	  ID    int      `param:"id,required"`
+//	  Page  int      `query:"page" default:"1"`
+//	  Title string   `form:"title" json:"title"`
+//	  Tags  []string `form:"tags" json:"tags"`
+//	}
+const (
+	ParamsTagParam   = "param"
+	ParamsTagQuery   = "query"
+	ParamsTagForm    = "form"
+	ParamsTagJSON    = "json"
+	ParamsTagDefault = "default"
+
+	// ParamsOptionRequired marks a field which must be present in request
+	ParamsOptionRequired = "required"
+)
+
+var (
+	paramsSources   = []string{ParamsTagParam, ParamsTagQuery, ParamsTagForm, ParamsTagJSON}
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType    = reflect.TypeOf(time.Duration(0))
+)
+
+// ParamsValidatorInterface is implemented by action params validating themselves after binding
+type ParamsValidatorInterface interface {
+	Valid() error
+}
+
+// ParamError describes a failure of a single action params field
+type ParamError struct {
+	Field   string
+	Source  string
+	Message string
+}
+
+// Error returns error message
+func (e *ParamError) Error() string {
+	return "Parameter '" + e.Field + "' " + e.Message
+}
+
+// ParamsErrors is a list of action params fields failures
+type ParamsErrors []*ParamError
+
+// Error returns error message
+func (e ParamsErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, pe := range e {
+		messages = append(messages, pe.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Fields returns failure messages by field name
+func (e ParamsErrors) Fields() map[string]string {
+	fields := make(map[string]string)
+	for _, pe := range e {
+		fields[pe.Field] = pe.Message
+	}
+
+	return fields
+}
+
+// BindParams populates a struct pointed by target with values of request route params, query, form and JSON body
+// Malformed values result in 400 Bad Request error, missing required values
+// and values rejected by ParamsValidatorInterface result in 422 Unprocessable Entity error.
+// Field failures are available as ParamsErrors cause of returned error
+func BindParams(rqs request.Interface, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("Unable to bind params into %T: Target must be a pointer to struct", target)
+	}
+
+	malformed := make(ParamsErrors, 0)
+	missing := make(ParamsErrors, 0)
+	bindParamsStruct(rqs, v.Elem(), &malformed, &missing)
+
+	if len(malformed) > 0 {
+		return errors.WrapHTTP(malformed, "Malformed request parameters", http.StatusBadRequest)
+	}
+
+	if len(missing) > 0 {
+		return errors.WrapHTTP(missing, "Invalid request parameters", http.StatusUnprocessableEntity)
+	}
+
+	if vi, ok := target.(ParamsValidatorInterface); ok {
+		if err := vi.Valid(); err != nil {
+			if _, ok := err.(*errors.HTTPError); ok {
+				return err
+			}
+
+			return errors.WrapHTTP(err, "Invalid request parameters", http.StatusUnprocessableEntity)
+		}
+	}
+
+	return nil
+}
+
+// Binds request values into struct fields
+func bindParamsStruct(rqs request.Interface, v reflect.Value, malformed *ParamsErrors, missing *ParamsErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := v.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			bindParamsStruct(rqs, field, malformed, missing)
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		tagged := false
+		found := false
+		required := false
+		for _, source := range paramsSources {
+			tag, ok := sf.Tag.Lookup(source)
+			if !ok {
+				continue
+			}
+
+			name, options := parseParamsTag(tag)
+			if name == "-" {
+				continue
+			}
+
+			if name == "" {
+				name = sf.Name
+			}
+
+			tagged = true
+			required = required || utils.InSSlice(ParamsOptionRequired, options)
+			value, ok := paramsValue(rqs, source, name)
+			if !ok {
+				continue
+			}
+
+			found = true
+			if err := setParamsField(field, value); err != nil {
+				*malformed = append(*malformed, &ParamError{Field: name, Source: source, Message: err.Error()})
+			}
+
+			break
+		}
+
+		if !tagged || found {
+			continue
+		}
+
+		if def, ok := sf.Tag.Lookup(ParamsTagDefault); ok {
+			if err := setParamsField(field, def); err != nil {
+				*malformed = append(*malformed, &ParamError{Field: paramsFieldName(sf), Source: ParamsTagDefault, Message: err.Error()})
+			}
+		} else if required {
+			*missing = append(*missing, &ParamError{Field: paramsFieldName(sf), Message: "is required"})
+		}
+	}
+}
+
+// Returns value of request source by name
+func paramsValue(rqs request.Interface, source string, name string) (interface{}, bool) {
+	var value interface{}
+	switch source {
+	case ParamsTagParam:
+		if !rqs.HasParam(name) {
+			return nil, false
+		}
+
+		value = rqs.Param(name)
+
+	case ParamsTagQuery:
+		if rqs.GetRequest() == nil || rqs.GetRequest().URL == nil {
+			return nil, false
+		}
+
+		values, ok := rqs.GetRequest().URL.Query()[name]
+		if !ok {
+			return nil, false
+		}
+
+		value = values
+
+	case ParamsTagForm, ParamsTagJSON:
+		pr, ok := rqs.(interface{ PostParam(name string) interface{} })
+		if !ok || isJSONRequest(rqs) != (source == ParamsTagJSON) {
+			return nil, false
+		}
+
+		value = pr.PostParam(name)
+	}
+
+	return value, value != nil
+}
+
+// Converts value into type of field and sets it
+func setParamsField(field reflect.Value, value interface{}) error {
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := setParamsField(elem.Elem(), value); err != nil {
+			return err
+		}
+
+		field.Set(elem)
+		return nil
+	}
+
+	if value != nil && reflect.TypeOf(value).AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return setParamsString(field, v)
+
+	case []string:
+		if field.Kind() != reflect.Slice {
+			if len(v) == 0 {
+				return nil
+			}
+
+			return setParamsString(field, v[0])
+		}
+
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+
+		return setParamsSlice(field, items)
+
+	case []interface{}:
+		if field.Kind() == reflect.Slice {
+			return setParamsSlice(field, v)
+		}
+
+	case utils.DataTree:
+		// Form values like tags[] or tags[0] are mounted as a tree of indexes
+		if field.Kind() == reflect.Slice {
+			return setParamsSlice(field, dataTreeItems(v))
+		}
+
+		value = utils.MapFromDataTree(v)
+	}
+
+	// JSON values are decoded into the field as is
+	b, err := json.Marshal(value)
+	if err != nil {
+		return errors.New("has invalid value")
+	}
+
+	if err := json.Unmarshal(b, field.Addr().Interface()); err != nil {
+		return errors.Errorf("must be of type %s", field.Type().String())
+	}
+
+	return nil
+}
+
+// Converts string into type of field and sets it
+func setParamsString(field reflect.Value, value string) error {
+	if field.Addr().Type().Implements(textUnmarshaler) {
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return errors.Errorf("must be a valid %s", field.Type().String())
+		}
+
+		return nil
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("must be a duration")
+		}
+
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		if value == "" {
+			field.SetBool(false)
+			return nil
+		}
+
+		b, err := parseParamBool(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be a positive integer")
+		}
+
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+
+		field.SetFloat(f)
+
+	case reflect.Slice:
+		return setParamsSlice(field, []interface{}{value})
+
+	case reflect.Interface:
+		field.Set(reflect.ValueOf(value))
+
+	default:
+		return errors.Errorf("must be of type %s", field.Type().String())
+	}
+
+	return nil
+}
+
+// Converts items into slice of field type and sets it
+func setParamsSlice(field reflect.Value, items []interface{}) error {
+	slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setParamsField(slice.Index(i), item); err != nil {
+			return err
+		}
+	}
+
+	field.Set(slice)
+	return nil
+}
+
+// Returns values of data tree ordered by index
+func dataTreeItems(tree utils.DataTree) []interface{} {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, erra := strconv.Atoi(keys[i])
+		b, errb := strconv.Atoi(keys[j])
+		if erra != nil || errb != nil {
+			return keys[i] < keys[j]
+		}
+
+		return a < b
+	})
+
+	items := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, tree[key])
+	}
+
+	return items
+}
+
+// Returns true if request payload is JSON encoded
+func isJSONRequest(rqs request.Interface) bool {
+	ct, _, err := mime.ParseMediaType(rqs.Header("Content-Type"))
+	return err == nil && strings.HasSuffix(ct, "json")
+}
+
+// Returns name of a field as it is tagged by the first source
+func paramsFieldName(sf reflect.StructField) string {
+	for _, source := range paramsSources {
+		if tag, ok := sf.Tag.Lookup(source); ok {
+			if name, _ := parseParamsTag(tag); name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+
+	return sf.Name
+}
+
+// Splits tag into name and options
+func parseParamsTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	options := make([]string, 0, len(parts)-1)
+	for _, option := range parts[1:] {
+		options = append(options, strings.TrimSpace(option))
+	}
+
+	return strings.TrimSpace(parts[0]), options
+}
+
+// Parses boolean param, besides of strconv.ParseBool values accepts on/off and yes/no
+func parseParamBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true, nil
+
+	case "off", "no":
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("must be a boolean")
+	}
+
+	return b, nil
+}
+
+// Returns true if type can hold action params
+func isActionParamsType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
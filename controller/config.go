@@ -37,6 +37,10 @@ func (c *Config) Populate(cfg config.Config) error {
 		return err
 	}
 
+	if rcfg := cfg.Get("router"); rcfg != nil {
+		c.Router.Merge(rcfg.GetAll())
+	}
+
 	return c.Valid()
 }
 
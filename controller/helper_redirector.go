@@ -138,6 +138,11 @@ func (h *Redirector) GotoRoute(ctx context.Context, params map[string]interface{
 		return errors.Wrapf(err, "Unable to set redirect to route '%s'", name)
 	}
 
+	// Hostname routes assemble network-path references like "//tenant.example.com"
+	if strings.HasPrefix(url, "//") {
+		url = requestScheme(ctx) + ":" + url
+	}
+
 	if err := h.redirect(ctx, url, h.Code); err != nil {
 		return errors.Wrapf(err, "Unable to set redirect to route '%s'", name)
 	}
@@ -165,6 +170,15 @@ func (h *Redirector) redirect(ctx context.Context, url string, code int) error {
 	return ctx.Response().SetRedirect(url, code)
 }
 
+// Returns scheme of the current request
+func requestScheme(ctx context.Context) string {
+	if rqst := ctx.Request(); rqst != nil && rqst.GetRequest() != nil && rqst.GetRequest().TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
 func (h *Redirector) checkCode(code int) bool {
 	if 300 > code || 307 < code || 304 == code || 306 == code {
 		return false
@@ -38,6 +38,11 @@ func (b *PluginBroker) Has(pluginName string) bool {
 func (b *PluginBroker) Get(pluginName string) PluginInterface {
 	for _, p := range b.Plugins() {
 		if p.Name() == pluginName {
+			// Plugins registered within route groups are returned unwrapped
+			if gp, ok := p.(*routeGroupPlugin); ok {
+				return gp.plugin
+			}
+
 			return p
 		}
 	}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/controller/response"
+	"github.com/noxyicm/wsf/db"
+)
+
+const (
+	// TYPEControllerPluginTypeDbProfiler name of the type of the plugin
+	TYPEControllerPluginTypeDbProfiler = "DbProfiler"
+
+	// Environment the profile headers are sent in by default
+	dbProfilerHeadersEnv = "development"
+)
+
+func init() {
+	RegisterPluginType(TYPEControllerPluginTypeDbProfiler, NewDbProfilerPlugin)
+}
+
+// DbProfiler is a plugin exposing database statements profile of the request
+// Totals are sent in response headers, statements are optionally appended to response body segment.
+// Headers are sent only in development environment unless enabled with SetHeaders
+type DbProfiler struct {
+	PluginAbstract
+	headers bool
+	segment string
+}
+
+// SetHeaders sets if profile totals should be sent in response headers
+func (p *DbProfiler) SetHeaders(flag bool) {
+	p.headers = flag
+}
+
+// SetSegment sets response body segment to render statements into
+// Empty segment disables rendering
+func (p *DbProfiler) SetSegment(segment string) {
+	p.segment = segment
+}
+
+// RouteStartup routine
+// Creates profile of the request before any statement is executed
+func (p *DbProfiler) RouteStartup(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	db.StartContextProfile(ctx)
+	return true, nil
+}
+
+// DispatchLoopShutdown routine
+func (p *DbProfiler) DispatchLoopShutdown(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	rp := db.ContextProfile(ctx)
+	if rp == nil {
+		return true, nil
+	}
+
+	rsp.SetData("dbProfile", rp)
+	if p.headers {
+		count, slowCount, _, total := rp.Totals()
+		rsp.SetHeader("X-Db-Query-Count", strconv.Itoa(count))
+		rsp.SetHeader("X-Db-Query-Time", fmt.Sprintf("%.3fms", float64(total.Microseconds())/1000))
+		rsp.SetHeader("X-Db-Slow-Query-Count", strconv.Itoa(slowCount))
+	}
+
+	if p.segment != "" {
+		if err := rsp.AppendBody(p.render(rp), p.segment); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Renders statements as html table
+func (p *DbProfiler) render(rp *db.RequestProfile) []byte {
+	count, slowCount, dropped, total := rp.Totals()
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<table class="db-profile"><caption>%d queries in %s, %d slow</caption>`, count, total, slowCount)
+	buf.WriteString(`<tr><th>Duration</th><th>Rows</th><th>Table</th><th>Query</th><th>Caller</th></tr>`)
+	for _, qp := range rp.Snapshot() {
+		class := ""
+		if qp.Slow {
+			class = ` class="slow"`
+		}
+
+		fmt.Fprintf(buf, `<tr%s><td>%s</td><td>%d</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			class, qp.Duration, qp.RowsAffected, html.EscapeString(qp.Table), html.EscapeString(qp.Query), html.EscapeString(qp.Caller))
+	}
+
+	if dropped > 0 {
+		fmt.Fprintf(buf, `<tr><td colspan="5">%d more queries not recorded</td></tr>`, dropped)
+	}
+
+	buf.WriteString(`</table>`)
+	return buf.Bytes()
+}
+
+// NewDbProfilerPlugin creates a new database profiler plugin
+func NewDbProfilerPlugin(name string) (PluginInterface, error) {
+	return &DbProfiler{
+		PluginAbstract: PluginAbstract{name: name},
+		headers:        config.AppEnv == dbProfilerHeadersEnv,
+	}, nil
+}
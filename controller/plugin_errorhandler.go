@@ -154,6 +154,11 @@ func (p *ErrorHandler) handleError(ctx context.Context, rqs request.Interface, r
 		err.Request = orqs
 		err.Encountered = len(exceptions)
 
+		// HTTP errors, e.g. of action params binding, define response code
+		if httpErr, ok := causeHTTPError(exception); ok {
+			rsp.SetResponseCode(httpErr.Code())
+		}
+
 		// Forward to the error handler
 		rqs.SetParam(p.name, err)
 		rqs.SetModuleName(p.ErrorHandlerModule())
@@ -165,6 +170,24 @@ func (p *ErrorHandler) handleError(ctx context.Context, rqs request.Interface, r
 	return true, nil
 }
 
+// Returns first HTTP error in the chain of error causes
+func causeHTTPError(err error) (*errors.HTTPError, bool) {
+	for err != nil {
+		if httpErr, ok := err.(*errors.HTTPError); ok {
+			return httpErr, true
+		}
+
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			return nil, false
+		}
+
+		err = c.Cause()
+	}
+
+	return nil, false
+}
+
 // NewErrorHandlerPlugin creates a new error handling plugin
 func NewErrorHandlerPlugin(name string) (PluginInterface, error) {
 	return &ErrorHandler{
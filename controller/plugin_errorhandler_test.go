@@ -0,0 +1,91 @@
+package controller
+
+import (
+	goctx "context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/controller/response"
+)
+
+type testItemParams struct {
+	ID   int `param:"id"`
+	Page int `query:"page,required"`
+}
+
+type testItemsController struct {
+	ActionControllerBase
+}
+
+func (c *testItemsController) Show(ctx context.Context, p *testItemParams) error {
+	return nil
+}
+
+type testErrorController struct {
+	ActionControllerBase
+	handled *bool
+}
+
+func (c *testErrorController) Error(ctx context.Context) error {
+	*c.handled = true
+	return nil
+}
+
+func TestErrorHandlerParamsResponseCode(t *testing.T) {
+	ci, err := NewDefaultController(&Config{ErrorHandling: true})
+	if err != nil {
+		t.Fatalf("NewDefaultController: %v", err)
+	}
+
+	SetInstance(ci)
+
+	dcfg := &DispatcherConfig{}
+	dcfg.Defaults()
+	ci.SetDispatcher(&DefaultDispatcher{
+		options:      dcfg,
+		actions:      make(map[string]map[string]func() (ActionControllerInterface, error)),
+		invokeParams: map[string]interface{}{"noViewRenderer": true},
+	})
+
+	ci.SetRouter(newTestRouter(t, map[string]interface{}{
+		"item": map[string]interface{}{
+			"route":    "items/:id",
+			"defaults": map[string]interface{}{"controller": "items", "action": "show"},
+		},
+	}))
+
+	handled := false
+	ci.AddActionController(dcfg.defaultModule, "items", func() (ActionControllerInterface, error) {
+		return &testItemsController{}, nil
+	})
+	ci.AddActionController(dcfg.defaultModule, "error", func() (ActionControllerInterface, error) {
+		return &testErrorController{handled: &handled}, nil
+	})
+
+	for url, want := range map[string]int{
+		"http://example.com/items/abc?page=1": http.StatusBadRequest,
+		"http://example.com/items/1":          http.StatusUnprocessableEntity,
+	} {
+		handled = false
+		req, _ := request.NewHTTPRequest(httptest.NewRequest("GET", url, nil), nil, false, 0, 0)
+		rsp, _ := response.NewHTTPResponse(httptest.NewRecorder())
+		ctx, _ := context.NewContext(goctx.Background())
+		ctx.SetRequest(req)
+		ctx.SetResponse(rsp)
+
+		if err := ci.Dispatch(ctx, req, rsp); err != nil {
+			t.Fatalf("Dispatch(%s): %v", url, err)
+		}
+
+		if !handled {
+			t.Fatalf("Dispatch(%s) was not forwarded to error controller, exceptions %v", url, rsp.Exceptions())
+		}
+
+		if code := rsp.ResponseCode(); code != want {
+			t.Fatalf("Dispatch(%s) response code = %d, want %d", url, code, want)
+		}
+	}
+}
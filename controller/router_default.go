@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/noxyicm/wsf/config"
@@ -31,23 +32,65 @@ type DefaultRouter struct {
 	DefaultLanguage          *locale.Language
 	Language                 *locale.Language
 	Languages                map[string]*locale.Language
+	chainOnly                map[string]bool
 }
 
 // AddRoute creates and adds route to stack from params
 func (r *DefaultRouter) AddRoute(routeType string, route string, defaults map[string]string, reqs map[string]string, name string) (err error) {
-	options := &RouteConfig{
-		Type:              routeType,
-		URIDelimiter:      r.Options.URIDelimiter,
-		URIVariable:       r.Options.URIVariable,
-		URIRegexDelimiter: r.Options.URIRegexDelimiter,
+	return r.AddRouteWithConfig(name, &RouteConfig{Type: routeType}, route, defaults, reqs)
+}
+
+// AddMethodRoute creates and adds route accepting only requests of methods
+func (r *DefaultRouter) AddMethodRoute(methods []string, routeType string, route string, defaults map[string]string, reqs map[string]string, name string) (err error) {
+	return r.AddRouteWithConfig(name, &RouteConfig{Type: routeType, Methods: methods}, route, defaults, reqs)
+}
+
+// AddRouteWithConfig creates and adds route of options.Type to stack
+// Options allow to specify a reverse spec of regex route or a scheme of hostname route
+func (r *DefaultRouter) AddRouteWithConfig(name string, options *RouteConfig, route string, defaults map[string]string, reqs map[string]string) (err error) {
+	if options.URIDelimiter == "" {
+		options.URIDelimiter = r.Options.URIDelimiter
+	}
+
+	if options.URIVariable == "" {
+		options.URIVariable = r.Options.URIVariable
 	}
 
-	rt, err := NewRoute(routeType, options, name, route, defaults, reqs)
+	if options.URIRegexDelimiter == "" {
+		options.URIRegexDelimiter = r.Options.URIRegexDelimiter
+	}
+
+	rt, err := NewRoute(options.Type, options, name, route, defaults, reqs)
 	if err != nil {
 		return err
 	}
 
-	return r.Routes.Append(name, rt)
+	// Routes compiling their definition keep the error to report it here
+	if re, ok := rt.(interface{ Error() error }); ok && re.Error() != nil {
+		return errors.Wrapf(re.Error(), "Unable to add route '%s'", name)
+	}
+
+	if chain, ok := rt.(*ChainRoute); ok {
+		for _, n := range chain.Names {
+			chained, ok := r.RouteByName(n)
+			if !ok {
+				return errors.Errorf("Unable to add chain route '%s': Route by name '%s' does not exists", name, n)
+			}
+
+			chain.Chain(chained)
+		}
+	}
+
+	if err := r.Routes.Append(name, rt); err != nil {
+		return err
+	}
+
+	// Chain only routes are matched only as a part of a chain
+	if options.ChainOnly {
+		r.chainOnly[name] = true
+	}
+
+	return nil
 }
 
 // RouteByName return route by its name
@@ -79,8 +122,22 @@ func (r *DefaultRouter) Match(ctx context.Context, req request.Interface) (bool,
 
 	// Find the matching route
 	routeMatched := false
+	allowed := make([]string, 0)
 	for _, route := range ReverseRoutesList(r.Routes).Stack() {
 		//match := req.PathInfo()
+		if r.chainOnly[route.Name()] {
+			continue
+		}
+
+		if !routeAllowsMethod(route, req.GetRequest().Method) {
+			// Remember methods of routes matching the path to report them if nothing else matches
+			if ok, _ := route.Match(req, false); ok {
+				allowed = appendMethods(allowed, route.(MethodsRouteInterface).Methods())
+			}
+
+			continue
+		}
+
 		if ok, params := route.Match(req, false); ok {
 			r.SetRequestParams(req, params)
 			ctx.SetCurrentRoute(params)
@@ -89,6 +146,15 @@ func (r *DefaultRouter) Match(ctx context.Context, req request.Interface) (bool,
 		}
 	}
 
+	if !routeMatched && len(allowed) > 0 {
+		if rsp := ctx.Response(); rsp != nil {
+			rsp.SetHeader("Allow", strings.Join(allowed, ", "))
+			rsp.SetResponseCode(http.StatusMethodNotAllowed)
+		}
+
+		return true, errors.NewHTTP("Method "+req.GetRequest().Method+" is not allowed", http.StatusMethodNotAllowed)
+	}
+
 	if !routeMatched {
 		return true, errors.New("No route matched the request")
 	}
@@ -228,11 +294,36 @@ func NewDefaultRouter(options config.Config) (ri RouterInterface, err error) {
 		Routes:       NewRoutesList(),
 		GlobalParams: make(map[string]interface{}),
 		Languages:    make(map[string]*locale.Language),
+		chainOnly:    make(map[string]bool),
 	}
 
 	cfg := &RouterConfig{}
 	cfg.Defaults()
 	cfg.Populate(options)
 	r.Options = cfg
+
+	routes := config.NewBridge()
+	if rcfg := options.Get("routes"); rcfg != nil {
+		routes.Merge(rcfg.GetAll())
+	}
+
+	// Default routes file is optional, explicitly configured one must exist
+	if cfg.File != "" && options.GetString("file") != "" && !routesFileExists(cfg.File) {
+		return nil, errors.Errorf("Routes file '%s' does not exists", cfg.File)
+	}
+
+	if cfg.File != "" && routesFileExists(cfg.File) {
+		rcfg, err := routesFromFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+
+		routes.Merge(rcfg.GetAll())
+	}
+
+	if err := r.AddRoutesFromConfig(routes, ""); err != nil {
+		return nil, errors.Wrap(err, "Unable to add configured routes")
+	}
+
 	return r, nil
 }
@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/controller/response"
+	"github.com/noxyicm/wsf/utils"
+)
+
+const (
+	// RouteGroupSeparator separates group name and route name
+	RouteGroupSeparator = "."
+)
+
+// RouteGroup is a set of routes sharing a path prefix, defaults, module, methods and plugins
+// Routes added to a group are named "<group>.<route>"
+type RouteGroup struct {
+	Router    *DefaultRouter
+	Parent    *RouteGroup
+	GroupName string
+	Prefix    string
+	Defs      map[string]string
+	Module    string
+	Methods   []string
+	routes    map[string]bool
+}
+
+// Name returns group name
+func (g *RouteGroup) Name() string {
+	return g.GroupName
+}
+
+// SetModule sets default module of group routes
+func (g *RouteGroup) SetModule(module string) *RouteGroup {
+	g.Module = module
+	return g
+}
+
+// SetMethods constrains group routes to HTTP methods
+func (g *RouteGroup) SetMethods(methods ...string) *RouteGroup {
+	g.Methods = methods
+	return g
+}
+
+// AddRoute creates and adds route to group
+func (g *RouteGroup) AddRoute(routeType string, route string, defaults map[string]string, reqs map[string]string, name string) error {
+	return g.AddRouteWithConfig(name, &RouteConfig{Type: routeType}, route, defaults, reqs)
+}
+
+// AddMethodRoute creates and adds route to group accepting only requests of methods
+func (g *RouteGroup) AddMethodRoute(methods []string, routeType string, route string, defaults map[string]string, reqs map[string]string, name string) error {
+	return g.AddRouteWithConfig(name, &RouteConfig{Type: routeType, Methods: methods}, route, defaults, reqs)
+}
+
+// AddRouteWithConfig creates and adds route of options.Type to group
+func (g *RouteGroup) AddRouteWithConfig(name string, options *RouteConfig, route string, defaults map[string]string, reqs map[string]string) error {
+	if options.Type == "" {
+		options.Type = TYPERouteRoute
+	}
+
+	if len(options.Methods) == 0 {
+		options.Methods = g.Methods
+	}
+
+	defs := utils.MapSSMerge(make(map[string]string), g.Defs)
+	if g.Module != "" {
+		defs["module"] = g.Module
+	}
+
+	defs = utils.MapSSMerge(defs, defaults)
+
+	fullName := g.routeName(name)
+	if err := g.Router.AddRouteWithConfig(fullName, options, g.prefixed(options, route), defs, reqs); err != nil {
+		return err
+	}
+
+	for group := g; group != nil; group = group.Parent {
+		group.routes[fullName] = true
+	}
+
+	return nil
+}
+
+// Group creates a nested group
+// Nested group inherits prefix, defaults, module and methods of this group
+func (g *RouteGroup) Group(name string, prefix string, defaults map[string]string) *RouteGroup {
+	group := newRouteGroup(g.Router, g.routeName(name), g.joinPrefix(prefix), utils.MapSSMerge(utils.MapSSMerge(make(map[string]string), g.Defs), defaults))
+	group.Parent = g
+	group.Module = g.Module
+	group.Methods = g.Methods
+	return group
+}
+
+// Has returns true if route by name belongs to group or its nested groups
+func (g *RouteGroup) Has(name string) bool {
+	return g.routes[name]
+}
+
+// RegisterPlugin registers a controller plugin invoked only for requests routed to the group
+// Route is not known before routing, so RouteStartup of the plugin is never invoked.
+// Plugin registered this way is still returned by Plugin(name) as is
+func (g *RouteGroup) RegisterPlugin(plugin PluginInterface, priority int) error {
+	return RegisterPlugin(&routeGroupPlugin{group: g, plugin: plugin}, priority)
+}
+
+// Returns route name qualified by group name
+func (g *RouteGroup) routeName(name string) string {
+	return g.GroupName + RouteGroupSeparator + name
+}
+
+// Returns prefix joined with group prefix
+func (g *RouteGroup) joinPrefix(prefix string) string {
+	delimiter := g.Router.Options.URIDelimiter
+	prefix = strings.Trim(prefix, delimiter)
+	if g.Prefix == "" {
+		return prefix
+	}
+
+	if prefix == "" {
+		return g.Prefix
+	}
+
+	return g.Prefix + delimiter + prefix
+}
+
+// Returns route definition prefixed with group prefix according to route type
+func (g *RouteGroup) prefixed(options *RouteConfig, route string) string {
+	switch options.Type {
+	case TYPERouteRoute:
+		return g.joinPrefix(route)
+
+	case TYPERouteRegex:
+		if g.Prefix == "" {
+			return route
+		}
+
+		delimiter := g.Router.Options.URIDelimiter
+		if options.Reverse != "" {
+			options.Reverse = g.Prefix + delimiter + strings.Trim(options.Reverse, delimiter)
+		}
+
+		route = strings.Trim(route, delimiter)
+		if route == "" {
+			return regexp.QuoteMeta(g.Prefix)
+		}
+
+		return regexp.QuoteMeta(g.Prefix+delimiter) + route
+
+	case TYPERouteChain:
+		names := strings.Split(route, ChainRouteSeparator)
+		for i, n := range names {
+			n = strings.TrimSpace(n)
+			if _, ok := g.Router.RouteByName(g.routeName(n)); ok {
+				n = g.routeName(n)
+			}
+
+			names[i] = n
+		}
+
+		return strings.Join(names, ChainRouteSeparator)
+	}
+
+	return route
+}
+
+// Group creates a group of routes sharing a path prefix and defaults
+func (r *DefaultRouter) Group(name string, prefix string, defaults map[string]string) *RouteGroup {
+	return newRouteGroup(r, name, strings.Trim(prefix, r.Options.URIDelimiter), defaults)
+}
+
+func newRouteGroup(router *DefaultRouter, name string, prefix string, defaults map[string]string) *RouteGroup {
+	if defaults == nil {
+		defaults = make(map[string]string)
+	}
+
+	return &RouteGroup{
+		Router:    router,
+		GroupName: name,
+		Prefix:    prefix,
+		Defs:      defaults,
+		Methods:   make([]string, 0),
+		routes:    make(map[string]bool),
+	}
+}
+
+// routeGroupPlugin invokes a plugin only if request is routed to a route of the group
+type routeGroupPlugin struct {
+	group  *RouteGroup
+	plugin PluginInterface
+}
+
+// Name returns plugin name
+func (p *routeGroupPlugin) Name() string {
+	return p.plugin.Name()
+}
+
+// RouteStartup routine
+// Route is not known before routing, so the plugin is not invoked
+func (p *routeGroupPlugin) RouteStartup(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	return true, nil
+}
+
+// RouteShutdown routine
+func (p *routeGroupPlugin) RouteShutdown(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	if !p.routed(ctx) {
+		return true, nil
+	}
+
+	return p.plugin.RouteShutdown(ctx, rqs, rsp)
+}
+
+// DispatchLoopStartup routine
+func (p *routeGroupPlugin) DispatchLoopStartup(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	if !p.routed(ctx) {
+		return true, nil
+	}
+
+	return p.plugin.DispatchLoopStartup(ctx, rqs, rsp)
+}
+
+// PreDispatch routine
+func (p *routeGroupPlugin) PreDispatch(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	if !p.routed(ctx) {
+		return true, nil
+	}
+
+	return p.plugin.PreDispatch(ctx, rqs, rsp)
+}
+
+// PostDispatch routine
+func (p *routeGroupPlugin) PostDispatch(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	if !p.routed(ctx) {
+		return true, nil
+	}
+
+	return p.plugin.PostDispatch(ctx, rqs, rsp)
+}
+
+// DispatchLoopShutdown routine
+func (p *routeGroupPlugin) DispatchLoopShutdown(ctx context.Context, rqs request.Interface, rsp response.Interface) (bool, error) {
+	if !p.routed(ctx) {
+		return true, nil
+	}
+
+	return p.plugin.DispatchLoopShutdown(ctx, rqs, rsp)
+}
+
+// Returns true if request is routed to a route of the group
+func (p *routeGroupPlugin) routed(ctx context.Context) bool {
+	return ctx.CurrentRoute() != nil && p.group.Has(ctx.CurrentRouteName())
+}
@@ -304,6 +304,21 @@ func (r *Route) Variables() map[int]string {
 	return r.Vars
 }
 
+// Type returns route type
+func (r *Route) Type() string {
+	return r.Options.Type
+}
+
+// Pattern returns route definition
+func (r *Route) Pattern() string {
+	return r.Path
+}
+
+// Methods returns HTTP methods route is constrained to
+func (r *Route) Methods() []string {
+	return normalizeMethods(r.Options)
+}
+
 // Locale returns route locale
 func (r *Route) Locale() string {
 	if r.Loc != "" {
@@ -318,6 +333,7 @@ func NewRouteRoute(options *RouteConfig, name string, route string, defaults map
 	r := &Route{
 		Options:      options,
 		RouteName:    name,
+		Path:         route,
 		Vars:         make(map[int]string),
 		Parts:        make([]string, 0),
 		Translatable: make([]string, 0),
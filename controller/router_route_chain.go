@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+const (
+	// TYPERouteChain represents chain route
+	TYPERouteChain = "chain"
+
+	// ChainRouteSeparator separates names of chained routes in a chain route definition
+	ChainRouteSeparator = ","
+)
+
+func init() {
+	RegisterRoute(TYPERouteChain, NewChainRoute)
+}
+
+// ChainRoute is a route combining several routes, e.g. a hostname route and a path route
+// Request matches the chain if it matches all of the chained routes,
+// URL is assembled by concatenating URLs of the chained routes.
+// Chained routes stay in the router, those not meant to match alone (e.g. a hostname route
+// matching every path of the host) should be added with RouteConfig.ChainOnly
+type ChainRoute struct {
+	Options   *RouteConfig
+	RouteName string
+	Names     []string
+	Routes    []RouteInterface
+	Defs      map[string]string
+}
+
+// Name return route name
+func (r *ChainRoute) Name() string {
+	return r.RouteName
+}
+
+// Chain appends routes to the chain
+func (r *ChainRoute) Chain(routes ...RouteInterface) *ChainRoute {
+	r.Routes = append(r.Routes, routes...)
+	return r
+}
+
+// Match matches provided request against all of the chained routes
+func (r *ChainRoute) Match(req request.Interface, partial bool) (bool, *context.RouteMatch) {
+	if len(r.Routes) == 0 {
+		return false, nil
+	}
+
+	defaults := make(map[string]string)
+	values := make(map[string]string)
+	wildcardData := make(map[string]string)
+	for _, route := range r.Routes {
+		if !routeAllowsMethod(route, req.GetRequest().Method) {
+			return false, nil
+		}
+
+		ok, match := route.Match(req, partial)
+		if !ok || match == nil {
+			return false, nil
+		}
+
+		defaults = utils.MapSSMerge(defaults, match.Defaults)
+		values = utils.MapSSMerge(values, match.Values)
+		wildcardData = utils.MapSSMerge(wildcardData, match.WildcardData)
+	}
+
+	return true, &context.RouteMatch{Defaults: utils.MapSSMerge(defaults, r.Defs), Values: values, WildcardData: wildcardData, Name: r.Name(), Match: true}
+}
+
+// Assemble assembles user submitted parameters forming a URL defined by the chained routes
+func (r *ChainRoute) Assemble(data map[string]interface{}, reset bool, encode bool) (string, error) {
+	if len(r.Routes) == 0 {
+		return "", errors.Errorf("Unable to assemble route '%s': Chain is empty", r.RouteName)
+	}
+
+	for name, value := range r.Defs {
+		if _, ok := data[name]; !ok {
+			data[name] = value
+		}
+	}
+
+	url := ""
+	for _, route := range r.Routes {
+		part, err := route.Assemble(data, reset, encode)
+		if err != nil {
+			return "", errors.Wrapf(err, "Unable to assemble chained route '%s'", route.Name())
+		}
+
+		url = url + part
+	}
+
+	return url, nil
+}
+
+// Defaults returns map of default values
+func (r *ChainRoute) Defaults() map[string]string {
+	return r.Defs
+}
+
+// Type returns route type
+func (r *ChainRoute) Type() string {
+	return r.Options.Type
+}
+
+// Pattern returns definitions of the chained routes
+func (r *ChainRoute) Pattern() string {
+	patterns := make([]string, 0, len(r.Routes))
+	for _, route := range r.Routes {
+		if d, ok := route.(DescriptiveRouteInterface); ok {
+			patterns = append(patterns, d.Pattern())
+		} else {
+			patterns = append(patterns, route.Name())
+		}
+	}
+
+	return strings.Join(patterns, " + ")
+}
+
+// Methods returns HTTP methods route is constrained to
+func (r *ChainRoute) Methods() []string {
+	return normalizeMethods(r.Options)
+}
+
+// NewChainRoute creates a new chain route structure
+// Route is a list of chained route names separated by ChainRouteSeparator,
+// the router resolves them when the chain is added
+func NewChainRoute(options *RouteConfig, name string, route string, defaults map[string]string, reqs map[string]string) RouteInterface {
+	if defaults == nil {
+		defaults = make(map[string]string)
+	}
+
+	r := &ChainRoute{
+		Options:   options,
+		RouteName: name,
+		Names:     make([]string, 0),
+		Routes:    make([]RouteInterface, 0),
+		Defs:      defaults,
+	}
+
+	for _, n := range strings.Split(route, ChainRouteSeparator) {
+		if n = strings.TrimSpace(n); n != "" {
+			r.Names = append(r.Names, n)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	goctx "context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+)
+
+// Creates default router holding routes of config
+func newTestRouter(t *testing.T, routes map[string]interface{}) *DefaultRouter {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{"file": "", "routes": routes})
+	ri, err := NewDefaultRouter(cfg)
+	if err != nil {
+		t.Fatalf("NewDefaultRouter: %v", err)
+	}
+
+	return ri.(*DefaultRouter)
+}
+
+// Matches request of method to url and returns name of matched route
+func matchTestRoute(t *testing.T, r *DefaultRouter, method string, url string) (string, request.Interface, error) {
+	t.Helper()
+
+	req, err := request.NewHTTPRequest(httptest.NewRequest(method, url, nil), nil, false, 0, 0)
+	if err != nil {
+		t.Fatalf("NewHTTPRequest: %v", err)
+	}
+
+	ctx, _ := context.NewContext(goctx.Background())
+	if _, err := r.Match(ctx, req); err != nil {
+		return "", req, err
+	}
+
+	return ctx.CurrentRouteName(), req, nil
+}
+
+func TestChainOnlyRoutes(t *testing.T) {
+	r := newTestRouter(t, map[string]interface{}{
+		"tenant": map[string]interface{}{
+			"type":      TYPERouteHostname,
+			"route":     ":tenant.example.com",
+			"chainonly": true,
+		},
+		"archive": map[string]interface{}{
+			"route":    "archive/:year",
+			"defaults": map[string]interface{}{"controller": "archive", "action": "show"},
+		},
+		"tenant_archive": map[string]interface{}{
+			"type":   TYPERouteChain,
+			"chains": []string{"tenant", "archive"},
+		},
+	})
+
+	name, req, err := matchTestRoute(t, r, "GET", "http://acme.example.com/archive/2020")
+	if err != nil || name != "tenant_archive" {
+		t.Fatalf("matched route %q, %v, want tenant_archive", name, err)
+	}
+
+	if req.Param("tenant") != "acme" || req.Param("year") != "2020" {
+		t.Fatalf("params = %v, want tenant acme and year 2020", req.Params())
+	}
+
+	if name, _, err := matchTestRoute(t, r, "GET", "http://acme.example.com/unknown"); err == nil {
+		t.Fatalf("chain only hostname route matched alone as %q", name)
+	}
+
+	if _, ok := r.RouteByName("tenant"); !ok {
+		t.Fatalf("chain only route is not available by name")
+	}
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+const (
+	// TYPERouteHostname represents hostname route
+	TYPERouteHostname = "hostname"
+
+	// HostnameDelimiter separates parts of hostname
+	HostnameDelimiter = "."
+)
+
+func init() {
+	RegisterRoute(TYPERouteHostname, NewHostnameRoute)
+}
+
+// HostnameRoute is a route matching request host like ":tenant.example.com"
+// Variable parts of the host become request params, path is not matched
+type HostnameRoute struct {
+	Options      *RouteConfig
+	RouteName    string
+	Hostname     string
+	Parts        []string
+	Vars         map[int]string
+	Defs         map[string]string
+	Requirements map[string]string
+	partRegexes  map[int]*regexp.Regexp
+	err          error
+}
+
+// Name return route name
+func (r *HostnameRoute) Name() string {
+	return r.RouteName
+}
+
+// Match matches provided host against this route
+func (r *HostnameRoute) Match(req request.Interface, partial bool) (bool, *context.RouteMatch) {
+	if r.err != nil {
+		return false, nil
+	}
+
+	host := req.GetRequest().Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	hostParts := strings.Split(strings.ToLower(strings.TrimSuffix(host, HostnameDelimiter)), HostnameDelimiter)
+	if len(hostParts) != len(r.Parts) {
+		return false, nil
+	}
+
+	values := make(map[string]string)
+	for pos, part := range hostParts {
+		name, ok := r.Vars[pos]
+		if !ok {
+			if r.Parts[pos] != part {
+				return false, nil
+			}
+
+			continue
+		}
+
+		if rx, ok := r.partRegexes[pos]; ok && !rx.MatchString(part) {
+			return false, nil
+		}
+
+		values[name] = part
+	}
+
+	return true, &context.RouteMatch{Defaults: r.Defs, Values: values, WildcardData: make(map[string]string), Name: r.Name(), Match: true}
+}
+
+// Assemble assembles user submitted parameters forming a host defined by this route
+// Result is a network-path reference like "//tenant.example.com" unless route has a scheme
+func (r *HostnameRoute) Assemble(data map[string]interface{}, reset bool, encode bool) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+
+	var err error
+	hostParts := make([]string, len(r.Parts))
+	for pos, part := range r.Parts {
+		name, ok := r.Vars[pos]
+		if !ok {
+			hostParts[pos] = part
+			continue
+		}
+
+		if v, ok := data[name]; ok && v != nil && v != "" {
+			hostParts[pos], err = utils.InterfaceToString(v)
+			if err != nil {
+				return "", errors.Wrapf(err, "Unable to assemble route '%s': Value '%s' can not be converted to string", r.RouteName, name)
+			}
+
+			delete(data, name)
+		} else if v, ok := r.Defs[name]; ok && v != "" {
+			hostParts[pos] = v
+		} else {
+			return "", errors.Errorf("Value %s is not specified", name)
+		}
+	}
+
+	host := "//" + strings.Join(hostParts, HostnameDelimiter)
+	if r.Options.Scheme != "" {
+		host = r.Options.Scheme + ":" + host
+	}
+
+	return host, nil
+}
+
+// Error returns error occurred while creating the route
+func (r *HostnameRoute) Error() error {
+	return r.err
+}
+
+// Defaults returns map of default values
+func (r *HostnameRoute) Defaults() map[string]string {
+	return r.Defs
+}
+
+// Type returns route type
+func (r *HostnameRoute) Type() string {
+	return r.Options.Type
+}
+
+// Pattern returns route definition
+func (r *HostnameRoute) Pattern() string {
+	return r.Hostname
+}
+
+// Methods returns HTTP methods route is constrained to
+func (r *HostnameRoute) Methods() []string {
+	return normalizeMethods(r.Options)
+}
+
+// NewHostnameRoute creates a new hostname route structure
+func NewHostnameRoute(options *RouteConfig, name string, route string, defaults map[string]string, reqs map[string]string) RouteInterface {
+	if defaults == nil {
+		defaults = make(map[string]string)
+	}
+
+	r := &HostnameRoute{
+		Options:      options,
+		RouteName:    name,
+		Hostname:     strings.Trim(route, HostnameDelimiter),
+		Parts:        make([]string, 0),
+		Vars:         make(map[int]string),
+		Defs:         defaults,
+		Requirements: reqs,
+		partRegexes:  make(map[int]*regexp.Regexp),
+	}
+
+	if r.Hostname == "" {
+		return r
+	}
+
+	for pos, part := range strings.Split(r.Hostname, HostnameDelimiter) {
+		if strings.HasPrefix(part, r.Options.URIVariable) && len(part) > len(r.Options.URIVariable) {
+			name := part[len(r.Options.URIVariable):]
+			r.Vars[pos] = name
+			r.Parts = append(r.Parts, reqs[name])
+			if reqs[name] != "" {
+				rx, err := regexp.Compile(`^` + reqs[name] + `$`)
+				if err != nil {
+					r.err = errors.Wrapf(err, "Invalid requirement of parameter '%s' of route '%s'", name, r.RouteName)
+					return r
+				}
+
+				r.partRegexes[pos] = rx
+			}
+
+			continue
+		}
+
+		r.Parts = append(r.Parts, strings.ToLower(part))
+	}
+
+	return r
+}
@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+const (
+	// TYPERouteRegex represents regex route
+	TYPERouteRegex = "regex"
+)
+
+func init() {
+	RegisterRoute(TYPERouteRegex, NewRegexRoute)
+}
+
+// RegexRoute is a route matching path against a regular expression
+// Named captures of the expression become request params.
+// URL is assembled by substituting params into a reverse spec like "archive/:year/:slug.html",
+// if no spec is configured it is derived from the expression when possible
+type RegexRoute struct {
+	Options      *RouteConfig
+	RouteName    string
+	Expression   string
+	Regex        *regexp.Regexp
+	Reverse      string
+	Defs         map[string]string
+	Requirements map[string]string
+	reqRegexes   map[string]*regexp.Regexp
+	err          error
+}
+
+// Name return route name
+func (r *RegexRoute) Name() string {
+	return r.RouteName
+}
+
+// Match matches provided path against this route
+func (r *RegexRoute) Match(req request.Interface, partial bool) (bool, *context.RouteMatch) {
+	if r.err != nil {
+		return false, nil
+	}
+
+	path := req.PathInfo()
+	if r.Options.ModulePrefix != "" {
+		if !strings.HasPrefix(path, r.Options.ModulePrefix) {
+			return false, nil
+		}
+
+		path = strings.TrimPrefix(path, r.Options.ModulePrefix)
+	}
+
+	path, err := url.PathUnescape(strings.Trim(path, r.Options.URIDelimiter))
+	if err != nil {
+		return false, nil
+	}
+
+	matches := r.Regex.FindStringSubmatch(path)
+	if matches == nil {
+		return false, nil
+	}
+
+	values := make(map[string]string)
+	for i, name := range r.Regex.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		if matches[i] != "" {
+			values[name] = matches[i]
+		} else if v, ok := r.Defs[name]; ok {
+			values[name] = v
+		}
+	}
+
+	for name, rx := range r.reqRegexes {
+		if v, ok := values[name]; ok && !rx.MatchString(v) {
+			return false, nil
+		}
+	}
+
+	return true, &context.RouteMatch{Defaults: r.Defs, Values: values, WildcardData: make(map[string]string), Name: r.Name(), Match: true}
+}
+
+// Assemble assembles user submitted parameters forming a URL path defined by this route
+func (r *RegexRoute) Assemble(data map[string]interface{}, reset bool, encode bool) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+
+	if r.Reverse == "" {
+		return "", errors.Errorf("Unable to assemble route '%s': Reverse spec is not defined", r.RouteName)
+	}
+
+	var err error
+	path := ""
+	spec := r.Reverse
+	for spec != "" {
+		pos := strings.Index(spec, r.Options.URIVariable)
+		if pos < 0 {
+			path = path + spec
+			break
+		}
+
+		path = path + spec[:pos]
+		spec = spec[pos+len(r.Options.URIVariable):]
+
+		// Doubled variable sign is a literal
+		if strings.HasPrefix(spec, r.Options.URIVariable) {
+			path = path + r.Options.URIVariable
+			spec = spec[len(r.Options.URIVariable):]
+			continue
+		}
+
+		end := 0
+		for end < len(spec) && isRouteVariableChar(spec[end]) {
+			end++
+		}
+
+		name := spec[:end]
+		spec = spec[end:]
+
+		value := ""
+		if v, ok := data[name]; ok && v != nil && v != "" {
+			value, err = utils.InterfaceToString(v)
+			if err != nil {
+				return "", errors.Wrapf(err, "Unable to assemble route '%s': Value '%s' can not be converted to string", r.RouteName, name)
+			}
+
+			delete(data, name)
+		} else if v, ok := r.Defs[name]; ok {
+			value = v
+		} else {
+			return "", errors.Errorf("Value %s is not specified", name)
+		}
+
+		if encode {
+			value = url.QueryEscape(value)
+		}
+
+		path = path + value
+	}
+
+	return r.Options.URIDelimiter + strings.TrimLeft(path, r.Options.URIDelimiter), nil
+}
+
+// Error returns error occurred while creating the route
+func (r *RegexRoute) Error() error {
+	return r.err
+}
+
+// Defaults returns map of default values
+func (r *RegexRoute) Defaults() map[string]string {
+	return r.Defs
+}
+
+// Type returns route type
+func (r *RegexRoute) Type() string {
+	return r.Options.Type
+}
+
+// Pattern returns route definition
+func (r *RegexRoute) Pattern() string {
+	return r.Expression
+}
+
+// Methods returns HTTP methods route is constrained to
+func (r *RegexRoute) Methods() []string {
+	return normalizeMethods(r.Options)
+}
+
+// Derives reverse spec from a regular expression consisting of literals and named captures
+func (r *RegexRoute) deriveReverse() string {
+	re, err := syntax.Parse(r.Expression, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+
+	spec, ok := r.reverseOf(re.Simplify())
+	if !ok {
+		return ""
+	}
+
+	return spec
+}
+
+func (r *RegexRoute) reverseOf(re *syntax.Regexp) (string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return "", true
+
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return "", false
+		}
+
+		return strings.ReplaceAll(string(re.Rune), r.Options.URIVariable, r.Options.URIVariable+r.Options.URIVariable), true
+
+	case syntax.OpCapture:
+		if re.Name == "" {
+			return "", false
+		}
+
+		return r.Options.URIVariable + re.Name, true
+
+	case syntax.OpConcat:
+		spec := ""
+		for _, sub := range re.Sub {
+			s, ok := r.reverseOf(sub)
+			if !ok {
+				return "", false
+			}
+
+			spec = spec + s
+		}
+
+		return spec, true
+	}
+
+	return "", false
+}
+
+func isRouteVariableChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// NewRegexRoute creates a new regex route structure
+func NewRegexRoute(options *RouteConfig, name string, route string, defaults map[string]string, reqs map[string]string) RouteInterface {
+	if defaults == nil {
+		defaults = make(map[string]string)
+	}
+
+	r := &RegexRoute{
+		Options:      options,
+		RouteName:    name,
+		Expression:   strings.Trim(route, options.URIDelimiter),
+		Reverse:      options.Reverse,
+		Defs:         defaults,
+		Requirements: reqs,
+		reqRegexes:   make(map[string]*regexp.Regexp),
+	}
+
+	for param, req := range reqs {
+		rx, err := regexp.Compile(`^` + req + `$`)
+		if err != nil {
+			r.err = errors.Wrapf(err, "Invalid requirement of parameter '%s' of route '%s'", param, name)
+			return r
+		}
+
+		r.reqRegexes[param] = rx
+	}
+
+	r.Regex, r.err = regexp.Compile(`^(?:` + r.Expression + `)$`)
+	if r.err != nil {
+		r.err = errors.Wrapf(r.err, "Invalid pattern of route '%s'", name)
+		r.Regex = nil
+		return r
+	}
+
+	if r.Reverse == "" {
+		r.Reverse = r.deriveReverse()
+	}
+
+	return r
+}
@@ -15,6 +15,14 @@ type RouteConfig struct {
 	Action            string
 	Default           map[string]interface{}
 	Locale            string
+	Reverse           string
+	Scheme            string
+	Route             string
+	Reqs              map[string]string
+	Chains            []string
+	ChainOnly         bool
+	Methods           []string
+	Priority          int
 }
 
 // Populate populates Config values using given Config source
@@ -23,17 +31,24 @@ func (c *RouteConfig) Populate(cfg config.Config) error {
 		return err
 	}
 
+	for key, value := range cfg.GetStringMap("defaults") {
+		c.Default[key] = value
+	}
+
 	return c.Valid()
 }
 
 // Defaults sets configuration default values
 func (c *RouteConfig) Defaults() error {
-	c.Type = "default"
+	c.Type = TYPERouteRoute
 	c.Default = make(map[string]interface{})
 	c.URIDelimiter = "/"
 	c.URIVariable = ":"
 	c.URIRegexDelimiter = ""
 	c.ModulePrefix = ""
+	c.Reqs = make(map[string]string)
+	c.Chains = make([]string, 0)
+	c.Methods = make([]string, 0)
 	return nil
 }
 
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+// AddRoutesFromConfig creates and adds routes defined in a routes config section
+//
+//	archive:
+//	  type: regex
+//	  route: archive/(?P<year>\d{4})
+//	  reverse: archive/:year
+//	  defaults: {controller: archive, action: show}
+//	  reqs: {year: \d+}
+//	  methods: [GET]
+//	  priority: 10
+//	tenant:
+//	  type: hostname
+//	  route: :tenant.example.com
+//	  chainonly: true
+//	tenant_archive:
+//	  type: chain
+//	  chains: [tenant, archive]
+//
+// Routes of higher priority are matched first, chain only routes are matched only as a part of a chain.
+// If module is not empty it becomes a default module of the routes
+func (r *DefaultRouter) AddRoutesFromConfig(cfg config.Config, module string) error {
+	type definition struct {
+		name    string
+		options *RouteConfig
+	}
+
+	definitions := make([]*definition, 0)
+	for _, name := range cfg.GetKeys() {
+		rcfg := cfg.Get(name)
+		if rcfg == nil {
+			return errors.Errorf("Invalid definition of route '%s'", name)
+		}
+
+		options := &RouteConfig{}
+		options.Defaults()
+		options.URIDelimiter = r.Options.URIDelimiter
+		options.URIVariable = r.Options.URIVariable
+		options.URIRegexDelimiter = r.Options.URIRegexDelimiter
+		if err := options.Populate(rcfg); err != nil {
+			return errors.Wrapf(err, "Invalid definition of route '%s'", name)
+		}
+
+		if options.Module == "" {
+			options.Module = module
+		}
+
+		definitions = append(definitions, &definition{name: name, options: options})
+	}
+
+	sort.SliceStable(definitions, func(i, j int) bool {
+		if definitions[i].options.Priority != definitions[j].options.Priority {
+			return definitions[i].options.Priority < definitions[j].options.Priority
+		}
+
+		return definitions[i].name < definitions[j].name
+	})
+
+	order := make([]string, 0, len(definitions))
+	pending := make(map[string]bool)
+	for _, d := range definitions {
+		order = append(order, d.name)
+		pending[d.name] = true
+	}
+
+	for len(definitions) > 0 {
+		deferred := make([]*definition, 0)
+		for _, d := range definitions {
+			if r.waitsForChained(d.options, pending) {
+				deferred = append(deferred, d)
+				continue
+			}
+
+			if err := r.addRouteFromConfig(d.name, d.options); err != nil {
+				return err
+			}
+
+			delete(pending, d.name)
+		}
+
+		if len(deferred) == len(definitions) {
+			return errors.Errorf("Unable to add chain route '%s': Chained routes form a cycle", deferred[0].name)
+		}
+
+		definitions = deferred
+	}
+
+	r.orderRoutes(order)
+	return nil
+}
+
+// LoadRoutesFile adds routes defined in "routes" section of a config file
+// Routes of section named after current application environment override them
+func (r *DefaultRouter) LoadRoutesFile(file string) error {
+	routes, err := routesFromFile(file)
+	if err != nil {
+		return err
+	}
+
+	return r.AddRoutesFromConfig(routes, "")
+}
+
+// Returns routes defined in a config file merged with routes of current environment
+func routesFromFile(file string) (config.Config, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(config.AppPath, file)
+	}
+
+	cfg, err := config.LoadConfig(file, []string{filepath.Dir(file)}, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to load routes file '%s'", file)
+	}
+
+	routes := config.NewBridge()
+	if rcfg := cfg.Get("routes"); rcfg != nil {
+		routes.Merge(rcfg.GetAll())
+	}
+
+	if config.AppEnv != "" {
+		if ecfg := cfg.Get(config.AppEnv); ecfg != nil {
+			if rcfg := ecfg.Get("routes"); rcfg != nil {
+				routes.Merge(rcfg.GetAll())
+			}
+		}
+	}
+
+	return routes, nil
+}
+
+// Creates and adds a route defined in config
+func (r *DefaultRouter) addRouteFromConfig(name string, options *RouteConfig) error {
+	defaults := make(map[string]string)
+	for key, value := range options.Default {
+		v, err := utils.InterfaceToString(value)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid default value '%s' of route '%s'", key, name)
+		}
+
+		defaults[key] = v
+	}
+
+	if options.Module != "" {
+		defaults["module"] = options.Module
+	}
+
+	if options.Controller != "" {
+		defaults["controller"] = options.Controller
+	}
+
+	if options.Action != "" {
+		defaults["action"] = options.Action
+	}
+
+	route := options.Route
+	if route == "" {
+		route = options.Path
+	}
+
+	if route == "" && len(options.Chains) > 0 {
+		route = strings.Join(options.Chains, ChainRouteSeparator)
+	}
+
+	if err := r.AddRouteWithConfig(name, options, route, defaults, options.Reqs); err != nil {
+		return errors.Wrapf(err, "Unable to add route '%s'", name)
+	}
+
+	return nil
+}
+
+// Returns true if a chain route references routes which are not added yet
+func (r *DefaultRouter) waitsForChained(options *RouteConfig, pending map[string]bool) bool {
+	if options.Type != TYPERouteChain {
+		return false
+	}
+
+	names := options.Chains
+	if len(names) == 0 {
+		names = strings.Split(options.Route, ChainRouteSeparator)
+	}
+
+	for _, n := range names {
+		if pending[strings.TrimSpace(n)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Moves routes to the end of the stack in provided order
+// Chain routes are added after the routes they chain which could break order of priorities
+func (r *DefaultRouter) orderRoutes(names []string) {
+	moved := make(map[string]bool)
+	for _, name := range names {
+		moved[name] = true
+	}
+
+	routes := NewRoutesList()
+	for i, route := range r.Routes.Stack() {
+		if name, ok := r.Routes.IKey(i); ok && !moved[name] {
+			routes.Append(name, route)
+		}
+	}
+
+	for _, name := range names {
+		routes.Append(name, r.Routes.Value(name))
+	}
+
+	r.Routes = routes
+}
+
+// Returns true if routes file exists
+func routesFileExists(file string) bool {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(config.AppPath, file)
+	}
+
+	_, err := os.Stat(file)
+	return err == nil
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoutesFromConfigPriority(t *testing.T) {
+	r := newTestRouter(t, map[string]interface{}{
+		"tenant": map[string]interface{}{
+			"type":      TYPERouteHostname,
+			"route":     ":tenant.example.com",
+			"chainonly": true,
+		},
+		"archive": map[string]interface{}{
+			"route":    "archive/:year",
+			"priority": 10,
+		},
+		"tenant_archive": map[string]interface{}{
+			"type":     TYPERouteChain,
+			"chains":   []string{"tenant", "archive"},
+			"priority": 5,
+		},
+		"tenant_any": map[string]interface{}{
+			"type":     TYPERouteChain,
+			"chains":   []string{"tenant", "any"},
+			"priority": 20,
+		},
+		"any": map[string]interface{}{
+			"route":    ":controller/:action",
+			"priority": 1,
+		},
+	})
+
+	names := make([]string, 0)
+	for _, ri := range r.RoutesInfo() {
+		names = append(names, ri.Name)
+	}
+
+	want := []string{"tenant_any", "archive", "tenant_archive", "any", "tenant"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("routes are matched in order %v, want %v", names, want)
+	}
+
+	if name, _, err := matchTestRoute(t, r, "GET", "http://acme.example.com/archive/2020"); err != nil || name != "tenant_any" {
+		t.Fatalf("matched route %q, %v, want tenant_any", name, err)
+	}
+
+	if name, _, err := matchTestRoute(t, r, "GET", "http://example.org/archive/2020"); err != nil || name != "archive" {
+		t.Fatalf("matched route %q, %v, want archive", name, err)
+	}
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/noxyicm/wsf/utils"
+)
+
+// DescriptiveRouteInterface is implemented by routes able to describe their definition
+type DescriptiveRouteInterface interface {
+	Type() string
+	Pattern() string
+	Defaults() map[string]string
+}
+
+// MethodsRouteInterface is implemented by routes constrained to HTTP methods
+type MethodsRouteInterface interface {
+	Methods() []string
+}
+
+// RouteInfo describes a route of router
+type RouteInfo struct {
+	Order      int
+	Name       string
+	Type       string
+	Pattern    string
+	Methods    []string
+	Module     string
+	Controller string
+	Action     string
+}
+
+// RoutesInfo returns descriptions of routes in order they are matched
+func (r *DefaultRouter) RoutesInfo() []*RouteInfo {
+	routes := ReverseRoutesList(r.Routes)
+	info := make([]*RouteInfo, 0, len(routes.Stack()))
+	for i, route := range routes.Stack() {
+		ri := &RouteInfo{
+			Order: i + 1,
+			Name:  route.Name(),
+			Type:  "-",
+		}
+
+		if d, ok := route.(DescriptiveRouteInterface); ok {
+			ri.Type = d.Type()
+			ri.Pattern = d.Pattern()
+			defaults := d.Defaults()
+			ri.Module = defaults["module"]
+			ri.Controller = defaults["controller"]
+			ri.Action = defaults["action"]
+		}
+
+		if m, ok := route.(MethodsRouteInterface); ok {
+			ri.Methods = m.Methods()
+		}
+
+		info = append(info, ri)
+	}
+
+	return info
+}
+
+// DumpRoutes returns a table of routes in order they are matched
+func (r *DefaultRouter) DumpRoutes() string {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tNAME\tTYPE\tMETHODS\tPATTERN\tMODULE\tCONTROLLER\tACTION")
+	for _, ri := range r.RoutesInfo() {
+		methods := "ANY"
+		if len(ri.Methods) > 0 {
+			methods = strings.Join(ri.Methods, ",")
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", ri.Order, ri.Name, ri.Type, methods, dumpValue(ri.Pattern), dumpValue(ri.Module), dumpValue(ri.Controller), dumpValue(ri.Action))
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// Returns true if route accepts requests of method
+func routeAllowsMethod(route RouteInterface, method string) bool {
+	m, ok := route.(MethodsRouteInterface)
+	if !ok || len(m.Methods()) == 0 {
+		return true
+	}
+
+	for _, allowed := range m.Methods() {
+		// GET routes answer HEAD requests as well
+		if allowed == method || (allowed == http.MethodGet && method == http.MethodHead) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Appends methods missing in list keeping it sorted
+func appendMethods(list []string, methods []string) []string {
+	for _, method := range methods {
+		if !utils.InSSlice(method, list) {
+			list = append(list, method)
+		}
+
+		if method == http.MethodGet && !utils.InSSlice(http.MethodHead, list) {
+			list = append(list, http.MethodHead)
+		}
+	}
+
+	sort.Strings(list)
+	return list
+}
+
+// Returns methods of route config in upper case
+func normalizeMethods(options *RouteConfig) []string {
+	if options == nil {
+		return nil
+	}
+
+	methods := make([]string, 0, len(options.Methods))
+	for _, method := range options.Methods {
+		methods = append(methods, strings.ToUpper(method))
+	}
+
+	return methods
+}
+
+func dumpValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
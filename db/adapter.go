@@ -4,7 +4,6 @@ import (
 	goctx "context"
 	"database/sql"
 	"fmt"
-	"os"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -47,13 +46,16 @@ type Adapter interface {
 	//QueryRow(ctx context.Context, sql Select) (Row, error)
 	QueryRow(ctx context.Context, sql Select) (map[string]interface{}, error)
 	Fetch(ctx context.Context, sql Select) (*sql.Rows, error)
+	Exec(ctx context.Context, query string, binds ...interface{}) (sql.Result, error)
+	Execute(ctx context.Context, stmt Statement) (sql.Result, error)
 	//PrepareRowset(rows *sql.Rows) ([]map[string]interface{}, error)
 	PrepareRowset(rows *sql.Rows) ([]map[string]interface{}, error)
 	//PrepareRow(row []sql.RawBytes, columns []*sql.ColumnType) (data map[string]interface{}, err error)
 	PrepareRow(rows *sql.Rows) (map[string]interface{}, error)
 	//PrepareRow(row *sql.Row) (*RowData, error)
 	//PrepareRow(row []*ColumnData) (map[string]interface{}, error)
-	//Profiler()
+	Profiler() *Profiler
+	SetProfiler(prf *Profiler) error
 	Insert(ctx context.Context, table string, data map[string]interface{}) (int, error)
 	Update(ctx context.Context, table string, data map[string]interface{}, cond map[string]interface{}) (bool, error)
 	Delete(ctx context.Context, table string, cond map[string]interface{}) (bool, error)
@@ -65,6 +67,7 @@ type Adapter interface {
 	//FetchOne
 	NextSequenceID(sequence string) int
 	BeginTransaction(ctx context.Context) (Transaction, error)
+	IsRetryable(err error) bool
 	DescribeTable(table string, schema string) (map[string]*TableColumn, error)
 	Quote(interface{}) string
 	QuoteIdentifier(interface{}, bool) string
@@ -78,6 +81,9 @@ type Adapter interface {
 	GetOptions() *AdapterConfig
 	FormatDSN() string
 	Limit(sql string, count int, offset int) string
+	Placeholder(position int) string
+	InsertedValue(column string) string
+	UpsertClause(target []string, assignments []string) (string, error)
 	FoldCase(s string) string
 	WhereExpresion(cond interface{}) string
 	Reference(tp reflect.Type) interface{}
@@ -92,7 +98,13 @@ func NewAdapter(adapterType string, options config.Config) (ai Adapter, err erro
 	cfg.Populate(options)
 
 	if f, ok := buildAdapterHandlers[adapterType]; ok {
-		return f(cfg)
+		ai, err = f(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		ai.SetProfiler(NewProfilerFromConfig(cfg.Profiler))
+		return ai, nil
 	}
 
 	return nil, errors.Errorf("Unrecognized database adapter type \"%v\"", adapterType)
@@ -118,11 +130,14 @@ type DefaultAdapter struct {
 	MaxOpenConnections         int
 	InTransaction              bool
 	IdentifierSymbol           string
+	StringLiteralPrefix        string
+	EscapeQuotesByDoubling     bool
 	AutoQuoteIdentifiers       bool
 	AllowSerialization         bool
 	AutoReconnectOnUnserialize bool
 	LastInsertID               int
 	LastInsertUUID             string
+	profiler                   *Profiler
 
 	Unquoteable          []string
 	Spliters             []string
@@ -151,6 +166,28 @@ func (a *DefaultAdapter) GetOptions() *AdapterConfig {
 	return a.Options
 }
 
+// Profiler returns adapter profiler or nil if profiling is not configured
+func (a *DefaultAdapter) Profiler() *Profiler {
+	return a.profiler
+}
+
+// SetProfiler sets adapter profiler
+func (a *DefaultAdapter) SetProfiler(prf *Profiler) error {
+	a.profiler = prf
+	return nil
+}
+
+// IsRetryable returns true if transaction failed with serialization failure or deadlock
+// and can be retried
+func (a *DefaultAdapter) IsRetryable(err error) bool {
+	switch errorSQLState(err) {
+	case "40001", "40P01":
+		return true
+	}
+
+	return false
+}
+
 // Connection returns a connection to database
 func (a *DefaultAdapter) Connection(ctx context.Context) (conn Connection, err error) {
 	if a.Db == nil {
@@ -179,16 +216,23 @@ func (a *DefaultAdapter) Query(ctx context.Context, dbs Select) ([]map[string]in
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := a.Db.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
 	if err != nil {
+		qp.End(0, err)
 		return nil, errors.Wrap(err, "Database query Error")
 	}
 	defer rows.Close()
 
-	return a.PrepareRowset(rows)
+	data, err := a.PrepareRowset(rows)
+	qp.End(int64(len(data)), err)
+	return data, err
 }
 
-// QueryInto runs a query and returns sql.Rows
+// QueryInto runs a query and maps rows into structs using db tags
+// o is either a struct prototype or a pointer to slice which is filled with results,
+// returned slice holds pointers to mapped structs
 func (a *DefaultAdapter) QueryInto(ctx context.Context, dbs Select, o interface{}) ([]interface{}, error) {
 	if a.Db == nil {
 		return nil, errors.New("Database is not initialized")
@@ -198,65 +242,77 @@ func (a *DefaultAdapter) QueryInto(ctx context.Context, dbs Select, o interface{
 		return nil, errors.Wrap(err, "Database query Error")
 	}
 
-	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
-	defer cancel()
+	var slice reflect.Value
+	t := reflect.TypeOf(o)
+	if t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice {
+		slice = reflect.ValueOf(o).Elem()
+		t = t.Elem().Elem()
+	}
 
-	rows, err := a.Db.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	m, err := MappingOf(t)
 	if err != nil {
 		return nil, errors.Wrap(err, "Database query Error")
 	}
 
-	t := reflect.TypeOf(o)
-	var v reflect.Value
-	if t.Kind() == reflect.Ptr {
-		v = reflect.ValueOf(t.Elem()).Elem()
-	} else if t.Kind() == reflect.Struct {
-		v = reflect.New(t)
-	} else {
-		return nil, errors.New("Nope")
+	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
+	defer cancel()
+
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
+	if err != nil {
+		qp.End(0, err)
+		return nil, errors.Wrap(err, "Database query Error")
 	}
+	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(2)
-		return nil, err
+		qp.End(0, err)
+		return nil, errors.Wrap(err, "Database query Error")
 	}
 
 	rt := make([]interface{}, 0)
 	for rows.Next() {
-		values, err := a.resolveValues(columns, v.Elem())
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(2)
-			return nil, err
+		item := reflect.New(m.Type)
+		if err := rows.Scan(a.resolveValues(m, columns, item.Elem())...); err != nil {
+			qp.End(int64(len(rt)), err)
+			return nil, errors.Wrap(err, "Database query Error")
 		}
 
-		if err := rows.Scan(values...); err != nil {
-			fmt.Println(err)
-			os.Exit(2)
+		rt = append(rt, item.Interface())
+		if slice.IsValid() {
+			if slice.Type().Elem().Kind() == reflect.Ptr {
+				slice.Set(reflect.Append(slice, item))
+			} else {
+				slice.Set(reflect.Append(slice, item.Elem()))
+			}
 		}
+	}
 
-		ptr := v.Interface()
-		rt = append(rt, ptr)
+	err = rows.Err()
+	qp.End(int64(len(rt)), err)
+	if err != nil {
+		return nil, errors.Wrap(err, "Database query Error")
 	}
+
 	return rt, nil
 }
 
-// resolveValues returns slice of call arguments for service Init method
-func (a *DefaultAdapter) resolveValues(columns []string, o reflect.Value) (values []interface{}, err error) {
-	values = make([]interface{}, len(columns))
-	var valueField reflect.Value
+// resolveValues returns scan destinations of columns mapped to struct fields
+// Columns without a matching field are discarded
+func (a *DefaultAdapter) resolveValues(m *StructMapping, columns []string, o reflect.Value) []interface{} {
+	values := make([]interface{}, len(columns))
 	for i := range columns {
-		if columns[i] == "id" {
-			valueField = o.FieldByName("ID")
+		if f, ok := m.Field(columns[i]); ok {
+			fv, _ := fieldByIndex(o, f.Index, true)
+			values[i] = fv.Addr().Interface()
 		} else {
-			valueField = o.FieldByName(strings.ToTitle(columns[i][:1]) + columns[i][1:])
+			values[i] = new(sql.RawBytes)
 		}
-		//valueField := o.FieldByName(columns[i])
-		values[i] = valueField.Addr().Interface()
 	}
-	return
+
+	return values
 }
 
 // QueryRow runs a query
@@ -272,13 +328,23 @@ func (a *DefaultAdapter) QueryRow(ctx context.Context, dbs Select) (map[string]i
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := a.Db.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
 	if err != nil {
+		qp.End(0, err)
 		return nil, errors.Wrap(err, "Database query Error")
 	}
 	defer rows.Close()
 
-	return a.PrepareRow(rows)
+	row, err := a.PrepareRow(rows)
+	var count int64
+	if row != nil {
+		count = 1
+	}
+
+	qp.End(count, err)
+	return row, err
 }
 
 // Fetch rows from database
@@ -294,7 +360,10 @@ func (a *DefaultAdapter) Fetch(ctx context.Context, dbs Select) (*sql.Rows, erro
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := a.Db.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
+	qp.End(0, err)
 	if err != nil {
 		return nil, errors.Wrap(err, "Database query Error")
 	}
@@ -303,6 +372,49 @@ func (a *DefaultAdapter) Fetch(ctx context.Context, dbs Select) (*sql.Rows, erro
 	return rows, nil
 }
 
+// Exec executes a raw query without returning any rows
+func (a *DefaultAdapter) Exec(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, binds, "")
+	result, err := a.Db.ExecContext(qctx, query, binds...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "Database exec Error")
+	}
+
+	return result, nil
+}
+
+// Execute executes an insert, update or delete statement
+func (a *DefaultAdapter) Execute(ctx context.Context, stmt Statement) (sql.Result, error) {
+	query := stmt.Assemble()
+	if err := stmt.Err(); err != nil {
+		return nil, err
+	}
+
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, stmt.Binds(), StatementTable(stmt))
+	result, err := a.Db.ExecContext(qctx, query, stmt.Binds()...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "Database exec Error")
+	}
+
+	return result, nil
+}
+
 // Insert inserts new row into table
 func (a *DefaultAdapter) Insert(ctx context.Context, table string, data map[string]interface{}) (int, error) {
 	cols := []string{}
@@ -343,7 +455,9 @@ func (a *DefaultAdapter) Insert(ctx context.Context, table string, data map[stri
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := a.Profiler().Start(ctx, query, binds, table)
 	result, err := stmt.ExecContext(qctx, binds...)
+	qp.EndResult(result, err)
 	if err != nil {
 		stmt.Close()
 		return 0, errors.Wrap(err, "Database insert Error")
@@ -405,7 +519,9 @@ func (a *DefaultAdapter) Update(ctx context.Context, table string, data map[stri
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := a.Profiler().Start(ctx, query, binds, table)
 	rows, err := stmt.QueryContext(qctx, binds...)
+	qp.End(0, err)
 	if err != nil {
 		stmt.Close()
 		return false, err
@@ -443,7 +559,9 @@ func (a *DefaultAdapter) Delete(ctx context.Context, table string, cond map[stri
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := a.Profiler().Start(ctx, sql, nil, table)
 	rows, err := stmt.QueryContext(qctx)
+	qp.End(0, err)
 	if err != nil {
 		stmt.Close()
 		return false, err
@@ -625,6 +743,11 @@ func (a *DefaultAdapter) QuoteIdentifierAs(ident interface{}, alias string, auto
 					segments = append(segments, v.ToString())
 
 				case string:
+					if v == SQLWildcard {
+						segments = append(segments, v)
+						continue
+					}
+
 					split := []string{}
 					spliters := []string{}
 					for _, spliter := range a.Spliters {
@@ -720,6 +843,40 @@ func (a *DefaultAdapter) SupportsParameters(param string) bool {
 	return false
 }
 
+// Placeholder returns bind placeholder for given 1-based position
+func (a *DefaultAdapter) Placeholder(position int) string {
+	return "?"
+}
+
+// InsertedValue returns reference to the value proposed for insertion in upsert statement
+func (a *DefaultAdapter) InsertedValue(column string) string {
+	return "EXCLUDED." + a.QuoteIdentifier(column, true)
+}
+
+// UpsertClause returns conflict resolution clause of insert statement
+// Updating conflicting rows requires a conflict target
+func (a *DefaultAdapter) UpsertClause(target []string, assignments []string) (string, error) {
+	if len(target) == 0 && len(assignments) > 0 {
+		return "", errors.New("Conflict target is required to update conflicting rows")
+	}
+
+	clause := "ON CONFLICT"
+	if len(target) > 0 {
+		cols := make([]string, len(target))
+		for i, col := range target {
+			cols[i] = a.QuoteIdentifier(col, true)
+		}
+
+		clause = clause + " (" + strings.Join(cols, ", ") + ")"
+	}
+
+	if len(assignments) == 0 {
+		return clause + " DO NOTHING", nil
+	}
+
+	return clause + " DO UPDATE " + SQLSet + " " + strings.Join(assignments, ", "), nil
+}
+
 // FoldCase folds a case
 func (a *DefaultAdapter) FoldCase(s string) string {
 	return s
@@ -992,7 +1149,11 @@ func (a *DefaultAdapter) quoteIdentifierSpec(idents []string, auto bool) string
 
 // quoteString qoutes string value
 func (a *DefaultAdapter) quoteString(value string) string {
-	return `'` + utils.Addslashes(value) + `'`
+	if a.EscapeQuotesByDoubling {
+		return a.StringLiteralPrefix + `'` + strings.ReplaceAll(value, `'`, `''`) + `'`
+	}
+
+	return a.StringLiteralPrefix + `'` + utils.Addslashes(value) + `'`
 }
 
 // Convert an array, string, or Expr object into a string to put in a WHERE clause
@@ -136,8 +136,10 @@ func (a *Cockroach) Setup() {
 	}
 
 	a.Params = map[string]interface{}{
-		"positional": true,
-		"named":      false,
+		"positional":       true,
+		"named":            false,
+		"transactionalDDL": true,
+		"forUpdate":        true,
 	}
 }
 
@@ -229,7 +231,9 @@ func (a *Cockroach) Insert(ctx context.Context, table string, data map[string]in
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := a.Profiler().Start(ctx, sql, binds, table)
 	err = stmt.QueryRowContext(qctx, binds...).Scan(&a.LastInsertID)
+	qp.End(1, err)
 	if err != nil {
 		return 0, errors.Wrap(err, "CockroachDB insert Error")
 	}
@@ -286,7 +290,9 @@ func (a *Cockroach) Update(ctx context.Context, table string, data map[string]in
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := a.Profiler().Start(ctx, sql, binds, table)
 	rows, err := stmt.QueryContext(qctx, binds...)
+	qp.End(0, err)
 	if err != nil {
 		return false, errors.Wrap(err, "CockroachDB update Error")
 	}
@@ -324,7 +330,9 @@ func (a *Cockroach) Delete(ctx context.Context, table string, cond map[string]in
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := a.Profiler().Start(ctx, sql, nil, table)
 	rows, err := stmt.QueryContext(qctx)
+	qp.End(0, err)
 	if err != nil {
 		return false, errors.Wrap(err, "CockroachDB Error")
 	}
@@ -503,6 +511,11 @@ func (a *Cockroach) Limit(sql string, count int, offset int) string {
 	return sql
 }
 
+// Placeholder returns bind placeholder for given 1-based position
+func (a *Cockroach) Placeholder(position int) string {
+	return "$" + strconv.Itoa(position)
+}
+
 // NextSequenceID returns nex value from sequence
 func (a *Cockroach) NextSequenceID(sequence string) int {
 	return 0
@@ -22,9 +22,10 @@ type AdapterConfig struct {
 	MaxIdleConnections    int
 	MaxOpenConnections    int
 	AutoQuoteIdentifiers  bool
+	StickyWindow          int
 	Transaction           *TransactionConfig
 	Connection            *ConnectionConfig
-	Profiler              config.Config
+	Profiler              *ProfilerConfig
 	Logger                config.Config
 }
 
@@ -54,6 +55,15 @@ func (c *AdapterConfig) Populate(cfg config.Config) error {
 		c.Connection.Populate(ccfg)
 	}
 
+	if c.Profiler == nil {
+		c.Profiler = &ProfilerConfig{}
+	}
+
+	c.Profiler.Defaults()
+	if pcfg := cfg.Get("profiler"); pcfg != nil {
+		c.Profiler.Populate(pcfg)
+	}
+
 	return c.Valid()
 }
 
@@ -66,6 +76,7 @@ func (c *AdapterConfig) Defaults() error {
 	c.MaxIdleConnections = 100
 	c.MaxOpenConnections = 100
 	c.AutoQuoteIdentifiers = true
+	c.StickyWindow = 5
 
 	c.Transaction = &TransactionConfig{}
 	c.Transaction.Defaults()
@@ -75,6 +86,9 @@ func (c *AdapterConfig) Defaults() error {
 	c.Connection.PingTimeout = c.PingTimeout
 	c.Connection.QueryTimeout = c.QueryTimeout
 
+	c.Profiler = &ProfilerConfig{}
+	c.Profiler.Defaults()
+
 	return nil
 }
 
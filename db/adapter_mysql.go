@@ -110,8 +110,10 @@ func (a *MySQL) Setup() {
 	}
 
 	a.Params = map[string]interface{}{
-		"positional": true,
-		"named":      false,
+		"positional":       true,
+		"named":            false,
+		"transactionalDDL": false,
+		"forUpdate":        true,
 	}
 }
 
@@ -174,13 +176,18 @@ func (a *MySQL) Query(ctx context.Context, dbs Select) ([]map[string]interface{}
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := a.Db.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
 	if err != nil {
+		qp.End(0, err)
 		return nil, errors.Wrap(err, "MySQL query error")
 	}
 	defer rows.Close()
 
-	return a.PrepareRowset(rows)
+	data, err := a.PrepareRowset(rows)
+	qp.End(int64(len(data)), err)
+	return data, err
 }
 
 // QueryRow runs a query
@@ -196,13 +203,23 @@ func (a *MySQL) QueryRow(ctx context.Context, dbs Select) (map[string]interface{
 	qctx, cancel := goctx.WithTimeout(ctx, time.Duration(a.QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := a.Db.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
 	if err != nil {
+		qp.End(0, err)
 		return nil, errors.Wrap(err, "MySQL query Error")
 	}
 	defer rows.Close()
 
-	return a.PrepareRow(rows)
+	row, err := a.PrepareRow(rows)
+	var count int64
+	if row != nil {
+		count = 1
+	}
+
+	qp.End(count, err)
+	return row, err
 }
 
 // PrepareRowset parses sql.Rows into mapstructure slice
@@ -379,11 +396,49 @@ func (a *MySQL) Limit(sql string, count int, offset int) string {
 	return sql
 }
 
+// InsertedValue returns reference to the value proposed for insertion in upsert statement
+func (a *MySQL) InsertedValue(column string) string {
+	return "VALUES(" + a.QuoteIdentifier(column, true) + ")"
+}
+
+// UpsertClause returns conflict resolution clause of insert statement
+// MySQL resolves conflicts on any unique key so target is used only to build a no-op update
+func (a *MySQL) UpsertClause(target []string, assignments []string) (string, error) {
+	if len(assignments) == 0 {
+		if len(target) == 0 {
+			return "", errors.New("Conflict target is required to ignore conflicting rows")
+		}
+
+		col := a.QuoteIdentifier(target[0], true)
+		assignments = []string{col + " = " + col}
+	}
+
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", "), nil
+}
+
 // NextSequenceID returns nex value from sequence
 func (a *MySQL) NextSequenceID(sequence string) int {
 	return 0
 }
 
+// IsRetryable returns true if transaction failed with deadlock or lock wait timeout
+func (a *MySQL) IsRetryable(err error) bool {
+	for err != nil {
+		if merr, ok := err.(*mysql.MySQLError); ok {
+			return merr.Number == 1213 || merr.Number == 1205
+		}
+
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return false
+}
+
 // BeginTransaction creates a new database transaction
 func (a *MySQL) BeginTransaction(ctx context.Context) (Transaction, error) {
 	if a.Db == nil {
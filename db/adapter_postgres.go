@@ -0,0 +1,664 @@
+package db
+
+import (
+	"bytes"
+	goctx "context"
+	"database/sql"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/errors"
+)
+
+const (
+	// TYPEAdapterPostgres represents postgres db adapter
+	TYPEAdapterPostgres = "PostgreSQL"
+)
+
+func init() {
+	RegisterAdapter(TYPEAdapterPostgres, NewPostgresAdapter)
+}
+
+// Postgres adapter for PostgreSQL databases
+type Postgres struct {
+	DefaultAdapter
+	driverConfig *postgresConfig
+	identities   map[string]string
+	mu           sync.RWMutex
+}
+
+// Setup the adapter
+func (a *Postgres) Setup() {
+	a.IdentifierSymbol = `"`
+	a.StringLiteralPrefix = "E"
+	a.AutoQuoteIdentifiers = true
+	a.PingTimeout = time.Duration(a.Options.PingTimeout) * time.Second
+	a.QueryTimeout = time.Duration(a.Options.QueryTimeout) * time.Second
+	a.identities = make(map[string]string)
+
+	a.driverConfig = &postgresConfig{
+		User:     a.Options.Username,
+		Passwd:   a.Options.Password,
+		Addr:     a.Options.Host,
+		DBName:   a.Options.DBname,
+		Charset:  a.Options.Charset,
+		Loc:      a.Options.TimeFormat,
+		Params:   make(map[string]string),
+		SSLMode:  "disable",
+		Protocol: a.Options.Protocol,
+	}
+
+	if a.Options.Port > 0 {
+		a.driverConfig.Addr = a.driverConfig.Addr + ":" + strconv.Itoa(a.Options.Port)
+	}
+
+	a.Unquoteable = []string{
+		"BETWEEN",
+		"LIKE",
+		"ILIKE",
+		"AND",
+		"OR",
+		"DESC",
+		"ASC",
+		"=",
+		"!=",
+		">",
+		">=",
+		"<",
+		"<=",
+		"<>",
+		"/",
+		"+",
+		"-",
+		"?",
+		"*",
+		"(",
+		")",
+		"IS",
+		"NOT",
+		"NULL",
+		"IN",
+		"IN(",
+		" ",
+		".",
+		"::",
+		"SOME",
+		"ANY",
+		"ALL",
+		"SIMILAR",
+	}
+
+	a.Spliters = []string{
+		"=",
+		"!=",
+		">",
+		">=",
+		"<",
+		"<=",
+		"<>",
+		"/",
+		"+",
+		"-",
+		".",
+		" ",
+	}
+
+	a.UnquoteableFunctions = []string{
+		"concat",
+		"concat_ws",
+		"lower",
+		"upper",
+		"md5",
+		"btrim",
+		"max",
+		"min",
+		"avg",
+		"sum",
+		"abs",
+		"round",
+		"ceil",
+		"floor",
+		"div",
+		"count",
+		"random",
+		"now",
+		"current_timestamp",
+		"greatest",
+		"least",
+		"coalesce",
+		"nullif",
+	}
+
+	a.Params = map[string]interface{}{
+		"positional":       true,
+		"named":            false,
+		"transactionalDDL": true,
+		"forUpdate":        true,
+	}
+}
+
+// Init a connection to database
+func (a *Postgres) Init() (err error) {
+	db, err := sql.Open("pgx", a.driverConfig.FormatDSN())
+	if err != nil {
+		return errors.Wrap(err, "PostgreSQL Error")
+	}
+
+	db.SetConnMaxLifetime(time.Duration(a.Options.ConnectionMaxLifeTime) * time.Second)
+	db.SetMaxIdleConns(a.Options.MaxIdleConnections)
+	db.SetMaxOpenConns(a.Options.MaxOpenConnections)
+
+	if a.PingTimeout > 0 {
+		tctx, cancel := goctx.WithTimeout(goctx.Background(), a.PingTimeout)
+		defer cancel()
+
+		if err = db.PingContext(tctx); err != nil {
+			return errors.Wrap(err, "PostgreSQL Error")
+		}
+	} else {
+		if err = db.PingContext(goctx.Background()); err != nil {
+			return errors.Wrap(err, "PostgreSQL Error")
+		}
+	}
+
+	a.Db = db
+	return nil
+}
+
+// SetOptions sets new options for PostgreSQL adapter
+func (a *Postgres) SetOptions(options *AdapterConfig) error {
+	a.Options = options
+	return nil
+}
+
+// GetOptions returns PostgreSQL adapter options
+func (a *Postgres) GetOptions() *AdapterConfig {
+	return a.Options
+}
+
+// Select creates a new adapter specific select object
+func (a *Postgres) Select() Select {
+	sel := NewSelectFromConfig(Options().Select)
+	sel.SetAdapter(a)
+	return sel
+}
+
+// Insert inserts new row into table
+func (a *Postgres) Insert(ctx context.Context, table string, data map[string]interface{}) (int, error) {
+	if a.Db == nil {
+		return 0, errors.New("Database is not initialized")
+	}
+
+	query, binds, identity, err := a.insertSQL(ctx, table, data)
+	if err != nil {
+		return 0, errors.Wrap(err, "PostgreSQL insert Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, binds, table)
+	if identity == "" {
+		result, err := a.Db.ExecContext(qctx, query, binds...)
+		qp.EndResult(result, err)
+		if err != nil {
+			return 0, errors.Wrap(err, "PostgreSQL insert Error")
+		}
+
+		return 0, nil
+	}
+
+	var id int64
+	err = a.Db.QueryRowContext(qctx, query, binds...).Scan(&id)
+	qp.End(1, err)
+	if err != nil {
+		return 0, errors.Wrap(err, "PostgreSQL insert Error")
+	}
+
+	a.LastInsertID = int(id)
+	return a.LastInsertID, nil
+}
+
+// Update updates rows into table be condition
+func (a *Postgres) Update(ctx context.Context, table string, data map[string]interface{}, cond map[string]interface{}) (bool, error) {
+	if a.Db == nil {
+		return false, errors.New("Database is not initialized")
+	}
+
+	query, binds, err := a.updateSQL(table, data, cond)
+	if err != nil {
+		return false, errors.Wrap(err, "PostgreSQL update Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, binds, table)
+	result, err := a.Db.ExecContext(qctx, query, binds...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return false, errors.Wrap(err, "PostgreSQL update Error")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return true, nil
+	}
+
+	return affected > 0, nil
+}
+
+// Delete removes rows from table
+func (a *Postgres) Delete(ctx context.Context, table string, cond map[string]interface{}) (bool, error) {
+	if a.Db == nil {
+		return false, errors.New("Database is not initialized")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	query := a.deleteSQL(table, cond)
+	qp := a.Profiler().Start(ctx, query, nil, table)
+	result, err := a.Db.ExecContext(qctx, query)
+	qp.EndResult(result, err)
+	if err != nil {
+		return false, errors.Wrap(err, "PostgreSQL delete Error")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return true, nil
+	}
+
+	return affected > 0, nil
+}
+
+// DescribeTable returns information about columns in table
+func (a *Postgres) DescribeTable(table string, schema string) (map[string]*TableColumn, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	schemaCond := "c.table_schema = current_schema()"
+	if schema != "" {
+		schemaCond = a.QuoteInto("c.table_schema = ?", schema, -1)
+	}
+
+	sqlstr := "SELECT c.table_schema, c.table_name, c.column_name, c.column_default, c.ordinal_position, c.data_type, c.udt_name," +
+		" c.character_maximum_length, c.numeric_precision, c.numeric_scale, c.character_set_name, c.collation_name, c.is_nullable, c.is_identity" +
+		" FROM information_schema.columns c WHERE " + a.QuoteInto("c.table_name = ?", table, -1) + " AND " + schemaCond +
+		" ORDER BY c.ordinal_position"
+
+	ctx, cancel := goctx.WithTimeout(a.context(), a.QueryTimeout)
+	defer cancel()
+
+	rows, err := a.Db.QueryContext(ctx, sqlstr)
+	if err != nil {
+		return nil, errors.Wrap(err, "PostgreSQL Error")
+	}
+	defer rows.Close()
+
+	desc := make(map[string]*TableColumn)
+	for rows.Next() {
+		var tableSchema, tableName, columnName, dataType, udtName, isNullable string
+		var columnDefault, characterSet, collation, isIdentity sql.NullString
+		var position int64
+		var length, precision, scale sql.NullInt64
+
+		if err := rows.Scan(&tableSchema, &tableName, &columnName, &columnDefault, &position, &dataType, &udtName, &length, &precision, &scale, &characterSet, &collation, &isNullable, &isIdentity); err != nil {
+			return nil, errors.Wrap(err, "PostgreSQL Error")
+		}
+
+		row := &TableColumn{
+			TableSchema:  tableSchema,
+			TableName:    tableName,
+			Name:         columnName,
+			Position:     position,
+			DataType:     dataType,
+			Length:       length.Int64,
+			Precision:    precision.Int64,
+			Scale:        scale.Int64,
+			CharacterSet: characterSet.String,
+			Collation:    collation.String,
+			ColumnType:   udtName,
+		}
+
+		if columnDefault.Valid {
+			row.Default = columnDefault.String
+			if strings.HasPrefix(columnDefault.String, "nextval(") {
+				row.Identity = true
+				row.Extra = columnDefault.String
+			}
+		}
+
+		if isIdentity.String == "YES" {
+			row.Identity = true
+		}
+
+		if isNullable == "YES" {
+			row.IsNullable = true
+		}
+
+		desc[row.Name] = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "PostgreSQL Error")
+	}
+
+	schemaCond = "n.nspname = current_schema()"
+	if schema != "" {
+		schemaCond = a.QuoteInto("n.nspname = ?", schema, -1)
+	}
+
+	sqlstr = "SELECT a.attname FROM pg_catalog.pg_index i" +
+		" JOIN pg_catalog.pg_class c ON c.oid = i.indrelid" +
+		" JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace" +
+		" JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, position) ON true" +
+		" JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum" +
+		" WHERE i.indisprimary AND " + a.QuoteInto("c.relname = ?", table, -1) + " AND " + schemaCond +
+		" ORDER BY k.position"
+
+	ctx2, cancel2 := goctx.WithTimeout(a.context(), a.QueryTimeout)
+	defer cancel2()
+
+	rows2, err := a.Db.QueryContext(ctx2, sqlstr)
+	if err != nil {
+		return nil, errors.Wrap(err, "PostgreSQL Error")
+	}
+	defer rows2.Close()
+
+	var i int64
+	for rows2.Next() {
+		var columnName string
+		if err := rows2.Scan(&columnName); err != nil {
+			return nil, errors.Wrap(err, "PostgreSQL Error")
+		}
+
+		if col, ok := desc[columnName]; ok {
+			col.Primary = true
+			col.PrimaryPosition = i
+			col.ColumnKey = "PRI"
+			i++
+		}
+	}
+
+	if err := rows2.Err(); err != nil {
+		return nil, errors.Wrap(err, "PostgreSQL Error")
+	}
+
+	return desc, nil
+}
+
+// Limit adds a limit clause to statement
+func (a *Postgres) Limit(sql string, count int, offset int) string {
+	if count > 0 {
+		sql = sql + " LIMIT " + strconv.Itoa(count)
+
+		if offset > 0 {
+			sql = sql + " OFFSET " + strconv.Itoa(offset)
+		}
+	}
+
+	return sql
+}
+
+// Placeholder returns bind placeholder for given 1-based position
+func (a *Postgres) Placeholder(position int) string {
+	return "$" + strconv.Itoa(position)
+}
+
+// NextSequenceID returns next value from sequence
+func (a *Postgres) NextSequenceID(sequence string) int {
+	if a.Db == nil {
+		return 0
+	}
+
+	ctx, cancel := goctx.WithTimeout(a.context(), a.QueryTimeout)
+	defer cancel()
+
+	var id int64
+	if err := a.Db.QueryRowContext(ctx, "SELECT nextval("+a.Quote(a.QuoteIdentifier(sequence, true))+")").Scan(&id); err != nil {
+		return 0
+	}
+
+	return int(id)
+}
+
+// BeginTransaction creates a new database transaction
+func (a *Postgres) BeginTransaction(ctx context.Context) (Transaction, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	tx, err := a.Db.BeginTx(ctx, &sql.TxOptions{Isolation: a.Options.Transaction.IsolationLevel, ReadOnly: a.Options.Transaction.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+
+	transactionType := a.Options.Transaction.Type
+	if transactionType == TYPEDefaultTransaction {
+		transactionType = TYPEPostgresTransaction
+	}
+
+	trns, err := NewTransaction(transactionType, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	trns.SetAdapter(a)
+	trns.SetContext(ctx)
+	return trns, err
+}
+
+// FormatDSN returns a formated dsn string
+func (a *Postgres) FormatDSN() string {
+	return a.driverConfig.FormatDSN()
+}
+
+// IdentityColumn returns the name of auto generated primary key column of the table
+// or empty string if table has none
+func (a *Postgres) IdentityColumn(ctx context.Context, table string) (string, error) {
+	a.mu.RLock()
+	identity, ok := a.identities[table]
+	a.mu.RUnlock()
+	if ok {
+		return identity, nil
+	}
+
+	schema := ""
+	name := table
+	if strings.Contains(table, ".") {
+		parts := strings.SplitN(table, ".", 2)
+		schema, name = parts[0], parts[1]
+	}
+
+	desc, err := a.DescribeTable(name, schema)
+	if err != nil {
+		return "", err
+	}
+
+	for _, col := range desc {
+		if col.Primary && col.Identity {
+			identity = col.Name
+			break
+		}
+	}
+
+	a.mu.Lock()
+	a.identities[table] = identity
+	a.mu.Unlock()
+	return identity, nil
+}
+
+// insertSQL builds an INSERT statement returning the identity column if table has one
+func (a *Postgres) insertSQL(ctx context.Context, table string, data map[string]interface{}) (string, []interface{}, string, error) {
+	cols := []string{}
+	vals := []string{}
+	binds := []interface{}{}
+	i := 1
+	for col, val := range data {
+		cols = append(cols, a.QuoteIdentifier(col, true))
+
+		switch v := val.(type) {
+		case *SQLExpr:
+			vals = append(vals, v.ToString())
+
+		default:
+			vals = append(vals, "$"+strconv.Itoa(i))
+			binds = append(binds, val)
+			i++
+		}
+	}
+
+	identity, err := a.IdentityColumn(ctx, table)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	query := "INSERT INTO " + a.QuoteIdentifier(table, true)
+	if len(cols) > 0 {
+		query = query + " (" + strings.Join(cols, ", ") + ") VALUES (" + strings.Join(vals, ", ") + ")"
+	} else {
+		query = query + " DEFAULT VALUES"
+	}
+
+	if identity != "" {
+		query = query + " RETURNING " + a.QuoteIdentifier(identity, true)
+	}
+
+	return query, binds, identity, nil
+}
+
+// updateSQL builds an UPDATE statement
+func (a *Postgres) updateSQL(table string, data map[string]interface{}, cond map[string]interface{}) (string, []interface{}, error) {
+	if len(data) == 0 {
+		return "", nil, errors.New("Nothing to update")
+	}
+
+	set := []string{}
+	binds := []interface{}{}
+	i := 1
+	for col, val := range data {
+		var value string
+
+		switch v := val.(type) {
+		case *SQLExpr:
+			value = v.ToString()
+
+		default:
+			value = "$" + strconv.Itoa(i)
+			binds = append(binds, val)
+			i++
+		}
+
+		set = append(set, a.QuoteIdentifier(col, true)+" = "+value)
+	}
+
+	query := "UPDATE " + a.QuoteIdentifier(table, true) + " SET " + strings.Join(set, ", ")
+	if where := a.whereExpr(cond); where != "" {
+		query = query + " WHERE " + where
+	}
+
+	return query, binds, nil
+}
+
+// deleteSQL builds a DELETE statement
+func (a *Postgres) deleteSQL(table string, cond map[string]interface{}) string {
+	query := "DELETE FROM " + a.QuoteIdentifier(table, true)
+	if where := a.whereExpr(cond); where != "" {
+		query = query + " WHERE " + where
+	}
+
+	return query
+}
+
+// context returns adapter context or background if none was set
+func (a *Postgres) context() goctx.Context {
+	if a.Ctx != nil {
+		return a.Ctx
+	}
+
+	return goctx.Background()
+}
+
+// NewPostgresAdapter creates a new PostgreSQL adapter
+func NewPostgresAdapter(options *AdapterConfig) (ai Adapter, err error) {
+	adp := &Postgres{}
+	adp.Options = options
+	adp.Setup()
+
+	return adp, nil
+}
+
+type postgresConfig struct {
+	User     string            // Username
+	Passwd   string            // Password (requires User)
+	Protocol string            // Network type, "unix" for socket connections
+	Addr     string            // Network address or socket directory
+	DBName   string            // Database name
+	Charset  string            // Client encoding
+	Loc      *time.Location    // Session time zone
+	SSLMode  string            // SSL mode
+	Params   map[string]string // Connection parameters
+}
+
+// FormatDSN formats the given Config into a DSN string which can be passed to the driver
+func (cfg *postgresConfig) FormatDSN() string {
+	var buf bytes.Buffer
+
+	buf.WriteString("postgres://")
+	// [username[:password]@]
+	if len(cfg.User) > 0 {
+		buf.WriteString(url.PathEscape(cfg.User))
+		if len(cfg.Passwd) > 0 {
+			buf.WriteByte(':')
+			buf.WriteString(url.PathEscape(cfg.Passwd))
+		}
+		buf.WriteByte('@')
+	}
+
+	params := url.Values{}
+	if cfg.Protocol == "unix" {
+		params.Set("host", cfg.Addr)
+	} else {
+		buf.WriteString(cfg.Addr)
+	}
+
+	// /dbname
+	buf.WriteByte('/')
+	buf.WriteString(url.PathEscape(cfg.DBName))
+
+	if cfg.SSLMode != "" {
+		params.Set("sslmode", cfg.SSLMode)
+	}
+
+	if cfg.Charset != "" {
+		params.Set("client_encoding", cfg.Charset)
+	}
+
+	if cfg.Loc != nil {
+		params.Set("timezone", cfg.Loc.String())
+	}
+
+	keys := make([]string, 0, len(cfg.Params))
+	for key := range cfg.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		params.Set(key, cfg.Params[key])
+	}
+
+	if len(params) > 0 {
+		buf.WriteByte('?')
+		buf.WriteString(params.Encode())
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Guards creation of write markers
+var contextWriteMu sync.Mutex
+
+// writeMarker holds time of the last write of a context
+// Statements of a request may run concurrently, so it is updated in place under a lock
+type writeMarker struct {
+	mu sync.Mutex
+	at time.Time
+}
+
+// Marks write at current time
+func (m *writeMarker) mark() {
+	m.mu.Lock()
+	m.at = time.Now()
+	m.mu.Unlock()
+}
+
+// Returns true if last write happened within window
+func (m *writeMarker) within(window time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return time.Since(m.at) < window
+}
+
+// Returns write marker of the context creating it if create is true
+func contextWriteMarker(ctx context.Context, create bool) *writeMarker {
+	contextWriteMu.Lock()
+	defer contextWriteMu.Unlock()
+
+	m, ok := ctx.Value(contextKeyLastWrite).(*writeMarker)
+	if !ok && create {
+		m = &writeMarker{}
+		ctx.SetValue(contextKeyLastWrite, m)
+	}
+
+	return m
+}
+
+// ReplicationAdapter routes read queries to replicas and everything else to the primary adapter
+// After a write the reads of the same context stick to the primary for StickyWindow
+type ReplicationAdapter struct {
+	Adapter
+	Replicas     []Adapter
+	StickyWindow time.Duration
+	next         uint32
+}
+
+// Init initializes primary and replica adapters
+func (a *ReplicationAdapter) Init() error {
+	if err := a.Adapter.Init(); err != nil {
+		return err
+	}
+
+	for i, replica := range a.Replicas {
+		if err := replica.Init(); err != nil {
+			return errors.Wrapf(err, "Unable to initialize database replica %d", i)
+		}
+	}
+
+	return nil
+}
+
+// SetProfiler sets profiler of primary and replica adapters
+func (a *ReplicationAdapter) SetProfiler(prf *Profiler) error {
+	for _, replica := range a.Replicas {
+		replica.SetProfiler(prf)
+	}
+
+	return a.Adapter.SetProfiler(prf)
+}
+
+// Primary returns the primary adapter
+func (a *ReplicationAdapter) Primary() Adapter {
+	return a.Adapter
+}
+
+// Reader returns an adapter for read queries in context
+func (a *ReplicationAdapter) Reader(ctx context.Context) Adapter {
+	if len(a.Replicas) == 0 || ctx == nil {
+		return a.Adapter
+	}
+
+	if usePrimary, ok := ctx.Value(contextKeyUsePrimary).(bool); ok && usePrimary {
+		return a.Adapter
+	}
+
+	if m := contextWriteMarker(ctx, false); m != nil && m.within(a.StickyWindow) {
+		return a.Adapter
+	}
+
+	n := atomic.AddUint32(&a.next, 1)
+	return a.Replicas[int(n%uint32(len(a.Replicas)))]
+}
+
+// Query runs a query on a replica
+func (a *ReplicationAdapter) Query(ctx context.Context, dbs Select) ([]map[string]interface{}, error) {
+	return a.Reader(ctx).Query(ctx, dbs)
+}
+
+// QueryInto runs a query on a replica and maps results into o
+func (a *ReplicationAdapter) QueryInto(ctx context.Context, dbs Select, o interface{}) ([]interface{}, error) {
+	return a.Reader(ctx).QueryInto(ctx, dbs, o)
+}
+
+// QueryRow runs a query on a replica and returns the first row
+func (a *ReplicationAdapter) QueryRow(ctx context.Context, dbs Select) (map[string]interface{}, error) {
+	return a.Reader(ctx).QueryRow(ctx, dbs)
+}
+
+// Fetch runs a query on a replica and returns raw rows
+func (a *ReplicationAdapter) Fetch(ctx context.Context, dbs Select) (*sql.Rows, error) {
+	return a.Reader(ctx).Fetch(ctx, dbs)
+}
+
+// Exec executes a query on the primary
+func (a *ReplicationAdapter) Exec(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	a.written(ctx)
+	return a.Adapter.Exec(ctx, query, binds...)
+}
+
+// Execute executes a statement on the primary
+func (a *ReplicationAdapter) Execute(ctx context.Context, stmt Statement) (sql.Result, error) {
+	a.written(ctx)
+	return a.Adapter.Execute(ctx, stmt)
+}
+
+// Insert inserts a row on the primary
+func (a *ReplicationAdapter) Insert(ctx context.Context, table string, data map[string]interface{}) (int, error) {
+	a.written(ctx)
+	return a.Adapter.Insert(ctx, table, data)
+}
+
+// Update updates rows on the primary
+func (a *ReplicationAdapter) Update(ctx context.Context, table string, data map[string]interface{}, cond map[string]interface{}) (bool, error) {
+	a.written(ctx)
+	return a.Adapter.Update(ctx, table, data, cond)
+}
+
+// Delete removes rows on the primary
+func (a *ReplicationAdapter) Delete(ctx context.Context, table string, cond map[string]interface{}) (bool, error) {
+	a.written(ctx)
+	return a.Adapter.Delete(ctx, table, cond)
+}
+
+// BeginTransaction starts a transaction on the primary
+func (a *ReplicationAdapter) BeginTransaction(ctx context.Context) (Transaction, error) {
+	a.written(ctx)
+	return a.Adapter.BeginTransaction(ctx)
+}
+
+// Marks context as written
+func (a *ReplicationAdapter) written(ctx context.Context) {
+	if ctx != nil && a.StickyWindow > 0 {
+		contextWriteMarker(ctx, true).mark()
+	}
+}
+
+// NewReplicationAdapter creates a new adapter routing reads to replicas
+func NewReplicationAdapter(primary Adapter, replicas []Adapter, stickyWindow time.Duration) *ReplicationAdapter {
+	return &ReplicationAdapter{
+		Adapter:      primary,
+		Replicas:     replicas,
+		StickyWindow: stickyWindow,
+	}
+}
+
+// UsePrimary makes all queries of the context run on the primary adapter
+func UsePrimary(ctx context.Context) {
+	ctx.SetValue(contextKeyUsePrimary, true)
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	goctx "context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/noxyicm/wsf/context"
+)
+
+// Adapter recording names of adapters queries ran on
+type routeAdapter struct {
+	Adapter
+	name  string
+	mu    *sync.Mutex
+	calls *[]string
+}
+
+func (a *routeAdapter) Query(ctx context.Context, dbs Select) ([]map[string]interface{}, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	*a.calls = append(*a.calls, a.name)
+	return nil, nil
+}
+
+func (a *routeAdapter) Exec(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func newRouteAdapters(window time.Duration) (*ReplicationAdapter, func() []string) {
+	mu := &sync.Mutex{}
+	calls := make([]string, 0)
+	adp := NewReplicationAdapter(
+		&routeAdapter{name: "primary", mu: mu, calls: &calls},
+		[]Adapter{&routeAdapter{name: "replica1", mu: mu, calls: &calls}, &routeAdapter{name: "replica2", mu: mu, calls: &calls}},
+		window,
+	)
+
+	return adp, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		last := calls
+		calls = make([]string, 0)
+		return last
+	}
+}
+
+func TestReplicationAdapterRouting(t *testing.T) {
+	adp, calls := newRouteAdapters(time.Minute)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	adp.Query(ctx, nil)
+	adp.Query(ctx, nil)
+	if c := calls(); len(c) != 2 || c[0] == "primary" || c[1] == "primary" || c[0] == c[1] {
+		t.Fatalf("reads ran on %v, want both replicas", c)
+	}
+
+	// Reads of other contexts are not affected by writes
+	other, _ := context.NewContext(goctx.Background())
+	adp.Exec(ctx, "UPDATE t SET a = 1")
+	adp.Query(ctx, nil)
+	adp.Query(other, nil)
+	if c := calls(); len(c) != 2 || c[0] != "primary" || c[1] == "primary" {
+		t.Fatalf("reads after write ran on %v, want primary and a replica", c)
+	}
+
+	UsePrimary(other)
+	adp.Query(other, nil)
+	if c := calls(); len(c) != 1 || c[0] != "primary" {
+		t.Fatalf("reads of primary context ran on %v, want primary", c)
+	}
+}
+
+func TestReplicationAdapterStickyWindow(t *testing.T) {
+	adp, calls := newRouteAdapters(20 * time.Millisecond)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	// Concurrent writes of a request share the marker
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adp.Exec(ctx, "UPDATE t SET a = 1")
+			adp.Query(ctx, nil)
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range calls() {
+		if c != "primary" {
+			t.Fatalf("read after write ran on %s, want primary", c)
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	adp.Query(ctx, nil)
+	if c := calls(); len(c) != 1 || c[0] == "primary" {
+		t.Fatalf("read after sticky window ran on %v, want a replica", c)
+	}
+}
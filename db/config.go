@@ -63,6 +63,7 @@ func (c *Config) Populate(cfg config.Config) error {
 // Defaults sets configuration default values
 func (c *Config) Defaults() error {
 	c.Priority = 3
+	c.DefaultAdapter = "default"
 
 	c.Select = &SelectConfig{}
 	c.Select.Defaults()
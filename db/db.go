@@ -2,6 +2,8 @@ package db
 
 import (
 	"database/sql"
+	"time"
+
 	"github.com/noxyicm/wsf/config"
 	"github.com/noxyicm/wsf/context"
 	"github.com/noxyicm/wsf/errors"
@@ -10,6 +12,14 @@ import (
 
 type contextKey int
 
+const (
+	contextKeyLastWrite contextKey = iota
+	contextKeyUsePrimary
+	contextKeyProfile
+	contextKeyTransaction
+	contextKeyCommitHooks
+)
+
 var (
 	ins *Db
 
@@ -20,6 +30,7 @@ var (
 type Db struct {
 	options        *Config
 	adapter        Adapter
+	adapters       map[string]Adapter
 	db             string
 	defaultAdapter string
 }
@@ -55,6 +66,20 @@ func (d *Db) Adapter() Adapter {
 	return d.adapter
 }
 
+// NamedAdapter returns a database adapter by its name
+func (d *Db) NamedAdapter(name string) (Adapter, error) {
+	if a, ok := d.adapters[name]; ok {
+		return a, nil
+	}
+
+	return nil, errors.Errorf("Database adapter '%s' is not configured", name)
+}
+
+// Adapters returns all configured database adapters by their names
+func (d *Db) Adapters() map[string]Adapter {
+	return d.adapters
+}
+
 // Select returns a new select object
 func (d *Db) Select() Select {
 	return d.adapter.Select()
@@ -62,22 +87,65 @@ func (d *Db) Select() Select {
 
 // Insert inserts new row into table
 func (d *Db) Insert(ctx context.Context, table string, data map[string]interface{}) (int, error) {
-	return d.adapter.Insert(ctx, table, data)
+	id, err := contextInsert(ctx, d.adapter, table, data)
+	if err == nil {
+		invalidateContextQueryCache(ctx, d.adapter, defaultQueryCache, table)
+	}
+
+	return id, err
 }
 
 // Update inserts new row into table
 func (d *Db) Update(ctx context.Context, table string, data map[string]interface{}, cond map[string]interface{}) (bool, error) {
-	return d.adapter.Update(ctx, table, data, cond)
+	ok, err := contextUpdate(ctx, d.adapter, table, data, cond)
+	if err == nil {
+		invalidateContextQueryCache(ctx, d.adapter, defaultQueryCache, table)
+	}
+
+	return ok, err
 }
 
 // Delete removes row from table
 func (d *Db) Delete(ctx context.Context, table string, cond map[string]interface{}) (bool, error) {
-	return d.adapter.Delete(ctx, table, cond)
+	ok, err := contextDelete(ctx, d.adapter, table, cond)
+	if err == nil {
+		invalidateContextQueryCache(ctx, d.adapter, defaultQueryCache, table)
+	}
+
+	return ok, err
+}
+
+// Execute executes an insert, update or delete statement
+func (d *Db) Execute(ctx context.Context, stmt Statement) (sql.Result, error) {
+	result, err := contextExecute(ctx, d.adapter, stmt)
+	if err == nil {
+		if table := StatementTable(stmt); table != "" {
+			invalidateContextQueryCache(ctx, d.adapter, defaultQueryCache, table)
+		}
+	}
+
+	return result, err
+}
+
+// CreateInsert returns an insert statement bound to db adapter
+func (d *Db) CreateInsert() InsertStatement {
+	return NewInsert(d.adapter)
+}
+
+// CreateUpdate returns an update statement bound to db adapter
+func (d *Db) CreateUpdate() UpdateStatement {
+	return NewUpdate(d.adapter)
+}
+
+// CreateDelete returns a delete statement bound to db adapter
+func (d *Db) CreateDelete() DeleteStatement {
+	return NewDelete(d.adapter)
 }
 
 // Query runs a query
+// Results of a select marked as cached are stored in the default query cache
 func (d *Db) Query(ctx context.Context, sql Select) ([]map[string]interface{}, error) { //(Rowset, error) {
-	return d.adapter.Query(ctx, sql)
+	return cachedQuery(ctx, defaultQueryCache, d.adapter, sql)
 }
 
 // QueryInto as
@@ -86,8 +154,9 @@ func (d *Db) QueryInto(ctx context.Context, dbs Select, o interface{}) ([]interf
 }
 
 // QueryRow runs a query
+// Result of a select marked as cached is stored in the default query cache
 func (d *Db) QueryRow(ctx context.Context, sql Select) (map[string]interface{}, error) { //(Row, error) {
-	return d.adapter.QueryRow(ctx, sql)
+	return cachedQueryRow(ctx, defaultQueryCache, d.adapter, sql)
 }
 
 // BeginTransaction creates a new database transaction
@@ -95,6 +164,11 @@ func (d *Db) BeginTransaction(ctx context.Context) (Transaction, error) {
 	return d.adapter.BeginTransaction(ctx)
 }
 
+// RunInTransaction runs fn inside a transaction bound to context
+func (d *Db) RunInTransaction(ctx context.Context, fn func(tx Transaction) error) error {
+	return RunInTransaction(ctx, d.adapter, fn)
+}
+
 // Quote a string
 func (d *Db) Quote(value interface{}) string {
 	return d.adapter.Quote(value)
@@ -131,29 +205,80 @@ func NewDB(options config.Config) (db *Db, err error) {
 	cfg.Defaults()
 	cfg.Populate(options)
 
-	var a Adapter
-	acfg := options.Get("adapter")
-	if acfg != nil {
-		adapterType := acfg.GetString("type")
-		a, err = NewAdapter(adapterType, acfg)
+	adapters := make(map[string]Adapter)
+	if acfg := options.Get("adapter"); acfg != nil {
+		a, err := newAdapterFromConfig(acfg)
 		if err != nil {
 			return nil, err
 		}
+
+		adapters[cfg.DefaultAdapter] = a
 	}
 
-	err = a.Init()
-	if err != nil {
-		return nil, err
+	if acfg := options.Get("adapters"); acfg != nil {
+		for _, name := range acfg.GetKeys() {
+			a, err := newAdapterFromConfig(acfg.Get(name))
+			if err != nil {
+				return nil, errors.Wrapf(err, "Unable to create database adapter '%s'", name)
+			}
+
+			adapters[name] = a
+		}
+	}
+
+	a, ok := adapters[cfg.DefaultAdapter]
+	if !ok {
+		return nil, errors.Errorf("Default database adapter '%s' is not configured", cfg.DefaultAdapter)
+	}
+
+	for name, adp := range adapters {
+		if err := adp.Init(); err != nil {
+			return nil, errors.Wrapf(err, "Unable to initialize database adapter '%s'", name)
+		}
 	}
 
 	db = &Db{
-		options: cfg,
-		adapter: a,
+		options:        cfg,
+		adapter:        a,
+		adapters:       adapters,
+		defaultAdapter: cfg.DefaultAdapter,
 	}
 
 	return db, nil
 }
 
+// Creates an adapter from configuration
+// If replicas section is present the adapter routes reads to replicas
+// Replica settings are merged over the primary ones
+func newAdapterFromConfig(acfg config.Config) (Adapter, error) {
+	adapterType := acfg.GetString("type")
+	a, err := NewAdapter(adapterType, acfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rcfg := acfg.Get("replicas")
+	if rcfg == nil {
+		return a, nil
+	}
+
+	replicas := make([]Adapter, 0)
+	for _, name := range rcfg.GetKeys() {
+		replicaCfg := config.NewBridge()
+		replicaCfg.Merge(acfg.GetAll())
+		replicaCfg.Merge(rcfg.Get(name).GetAll())
+
+		replica, err := NewAdapter(replicaCfg.GetStringDefault("type", adapterType), replicaCfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to create database replica '%s'", name)
+		}
+
+		replicas = append(replicas, replica)
+	}
+
+	return NewReplicationAdapter(a, replicas, time.Duration(a.GetOptions().StickyWindow)*time.Second), nil
+}
+
 // SetInstance sets a main db instance
 func SetInstance(d *Db) {
 	ins = d
@@ -164,6 +289,15 @@ func Instance() *Db {
 	return ins
 }
 
+// NamedAdapter returns a database adapter of db instance by its name
+func NamedAdapter(name string) (Adapter, error) {
+	if ins == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	return ins.NamedAdapter(name)
+}
+
 // CreateSelect returns a select configured by db instance
 func CreateSelect() Select {
 	return ins.Select()
@@ -184,6 +318,26 @@ func Delete(ctx context.Context, table string, cond map[string]interface{}) (boo
 	return ins.Delete(ctx, table, cond)
 }
 
+// CreateInsert returns an insert statement configured by db instance
+func CreateInsert() InsertStatement {
+	return ins.CreateInsert()
+}
+
+// CreateUpdate returns an update statement configured by db instance
+func CreateUpdate() UpdateStatement {
+	return ins.CreateUpdate()
+}
+
+// CreateDelete returns a delete statement configured by db instance
+func CreateDelete() DeleteStatement {
+	return ins.CreateDelete()
+}
+
+// Execute executes an insert, update or delete statement
+func Execute(ctx context.Context, stmt Statement) (sql.Result, error) {
+	return ins.Execute(ctx, stmt)
+}
+
 // Query runs a query
 func Query(ctx context.Context, sql Select) ([]map[string]interface{}, error) { //(Rowset, error) {
 	return ins.Query(ctx, sql)
@@ -217,6 +371,12 @@ func SetupAdapter(db interface{}) (Adapter, error) {
 
 	switch db.(type) {
 	case string:
+		if ins != nil {
+			if dba, err := ins.NamedAdapter(db.(string)); err == nil {
+				return dba, nil
+			}
+		}
+
 		if dba := registry.Get(db.(string)); dba != nil {
 			return dba.(Adapter), nil
 		}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"github.com/noxyicm/wsf/errors"
+)
+
+// DeleteStatement is a delete statement interface
+type DeleteStatement interface {
+	SetAdapter(adapter Adapter) error
+	From(table string) DeleteStatement
+	Where(cond string, value interface{}) DeleteStatement
+	OrWhere(cond string, value interface{}) DeleteStatement
+	Binds() []interface{}
+	Err() error
+	Assemble() string
+	ToString() string
+}
+
+// DefaultDelete is a delete statement builder
+type DefaultDelete struct {
+	Adapter Adapter
+	Name    string
+	Bind    []interface{}
+	Errors  []error
+	where   *statementWhere
+}
+
+// SetAdapter sets the adapter interface to delete object
+func (s *DefaultDelete) SetAdapter(adapter Adapter) error {
+	s.Adapter = adapter
+	s.where.adapter = adapter
+	return nil
+}
+
+// From sets the target table
+func (s *DefaultDelete) From(table string) DeleteStatement {
+	s.Name = table
+	return s
+}
+
+// Where adds a WHERE condition to the statement by AND
+func (s *DefaultDelete) Where(cond string, value interface{}) DeleteStatement {
+	s.where.add(cond, value, true)
+	return s
+}
+
+// OrWhere adds a WHERE condition to the statement by OR
+func (s *DefaultDelete) OrWhere(cond string, value interface{}) DeleteStatement {
+	s.where.add(cond, value, false)
+	return s
+}
+
+// Binds returns binds of the last assembled statement
+func (s *DefaultDelete) Binds() []interface{} {
+	return s.Bind
+}
+
+// Err pops last acuired error or nil if no errors
+func (s *DefaultDelete) Err() error {
+	if err := s.where.err(); err != nil {
+		return err
+	}
+
+	if len(s.Errors) > 0 {
+		err := s.Errors[0]
+		s.Errors = append([]error{}, s.Errors[1:]...)
+		return err
+	}
+
+	return nil
+}
+
+// ToString converts delete struct to string
+func (s *DefaultDelete) ToString() string {
+	return s.Assemble()
+}
+
+// Assemble converts this object to an SQL DELETE string
+func (s *DefaultDelete) Assemble() string {
+	s.Bind = make([]interface{}, 0)
+	if s.Adapter == nil {
+		s.Errors = append(s.Errors, errors.New("Adapter is not set"))
+		return ""
+	}
+
+	if s.Name == "" {
+		s.Errors = append(s.Errors, errors.New("No table has been specified for the DELETE statement"))
+		return ""
+	}
+
+	binds := &placeholders{}
+	sql := s.where.render(SQLDeleteFrom+" "+s.Adapter.QuoteIdentifier(s.Name, true), binds)
+	sql, s.Bind = binds.resolve(s.Adapter, sql, make([]interface{}, 0))
+	return sql
+}
+
+// NewDelete creates a new delete statement for adapter
+func NewDelete(adapter Adapter) DeleteStatement {
+	return &DefaultDelete{
+		Adapter: adapter,
+		Bind:    make([]interface{}, 0),
+		Errors:  make([]error, 0),
+		where:   &statementWhere{adapter: adapter, parts: make([]*statementCondition, 0), errors: make([]error, 0)},
+	}
+}
@@ -0,0 +1,243 @@
+package db
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// InsertStatement is an insert statement interface
+type InsertStatement interface {
+	SetAdapter(adapter Adapter) error
+	Into(table string) InsertStatement
+	Columns(cols ...string) InsertStatement
+	Values(row map[string]interface{}) InsertStatement
+	Rows(rows []map[string]interface{}) InsertStatement
+	OnConflict(target []string, update interface{}) InsertStatement
+	Binds() []interface{}
+	Err() error
+	Assemble() string
+	ToString() string
+}
+
+// DefaultInsert is an insert statement builder
+type DefaultInsert struct {
+	Adapter   Adapter
+	Table     string
+	Cols      []string
+	Data      []map[string]interface{}
+	Conflict  []string
+	Upsert    bool
+	UpdateSet []interface{}
+	Bind      []interface{}
+	Errors    []error
+}
+
+// SetAdapter sets the adapter interface to insert object
+func (s *DefaultInsert) SetAdapter(adapter Adapter) error {
+	s.Adapter = adapter
+	return nil
+}
+
+// Into sets the target table
+func (s *DefaultInsert) Into(table string) InsertStatement {
+	s.Table = table
+	return s
+}
+
+// Columns sets columns order of inserted rows
+func (s *DefaultInsert) Columns(cols ...string) InsertStatement {
+	s.Cols = cols
+	return s
+}
+
+// Values adds a row to insert
+func (s *DefaultInsert) Values(row map[string]interface{}) InsertStatement {
+	s.Data = append(s.Data, row)
+	return s
+}
+
+// Rows adds several rows to insert
+func (s *DefaultInsert) Rows(rows []map[string]interface{}) InsertStatement {
+	s.Data = append(s.Data, rows...)
+	return s
+}
+
+// OnConflict makes the statement an upsert
+// update may be a list of columns to take from the inserted row,
+// a map of column values or expressions, or nil to ignore conflicting rows
+func (s *DefaultInsert) OnConflict(target []string, update interface{}) InsertStatement {
+	s.Upsert = true
+	s.Conflict = target
+	s.UpdateSet = make([]interface{}, 0)
+
+	switch v := update.(type) {
+	case nil:
+
+	case string:
+		s.UpdateSet = append(s.UpdateSet, v)
+
+	case []string:
+		for _, col := range v {
+			s.UpdateSet = append(s.UpdateSet, col)
+		}
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for col := range v {
+			keys = append(keys, col)
+		}
+		sort.Strings(keys)
+
+		for _, col := range keys {
+			s.UpdateSet = append(s.UpdateSet, map[string]interface{}{col: v[col]})
+		}
+
+	default:
+		s.Errors = append(s.Errors, errors.Errorf("Invalid conflict update type '%T'", update))
+	}
+
+	return s
+}
+
+// Binds returns binds of the last assembled statement
+func (s *DefaultInsert) Binds() []interface{} {
+	return s.Bind
+}
+
+// Err pops last acuired error or nil if no errors
+func (s *DefaultInsert) Err() error {
+	if len(s.Errors) > 0 {
+		err := s.Errors[0]
+		s.Errors = append([]error{}, s.Errors[1:]...)
+		return err
+	}
+
+	return nil
+}
+
+// ToString converts insert struct to string
+func (s *DefaultInsert) ToString() string {
+	return s.Assemble()
+}
+
+// Assemble converts this object to an SQL INSERT string
+func (s *DefaultInsert) Assemble() string {
+	s.Bind = make([]interface{}, 0)
+	if s.Adapter == nil {
+		s.Errors = append(s.Errors, errors.New("Adapter is not set"))
+		return ""
+	}
+
+	if s.Table == "" {
+		s.Errors = append(s.Errors, errors.New("No table has been specified for the INSERT statement"))
+		return ""
+	}
+
+	if len(s.Data) == 0 {
+		s.Errors = append(s.Errors, errors.New("No values to insert"))
+		return ""
+	}
+
+	cols := s.columns()
+	quotedCols := make([]string, len(cols))
+	for i, col := range cols {
+		quotedCols[i] = s.Adapter.QuoteIdentifier(col, true)
+	}
+
+	binds := &placeholders{}
+	rows := make([]string, 0, len(s.Data))
+	for i, row := range s.Data {
+		vals := make([]string, 0, len(cols))
+		for _, col := range cols {
+			val, ok := row[col]
+			if !ok {
+				s.Errors = append(s.Errors, errors.Errorf("Row %d has no value for column '%s'", i, col))
+				return ""
+			}
+
+			vals = append(vals, s.value(val, binds))
+		}
+
+		rows = append(rows, "("+strings.Join(vals, ", ")+")")
+	}
+
+	sql := SQLInsertInto + " " + s.Adapter.QuoteIdentifier(s.Table, true) + " (" + strings.Join(quotedCols, ", ") + ") " + SQLValues + " " + strings.Join(rows, ", ")
+	sql, s.Bind = binds.resolve(s.Adapter, s.renderUpsert(sql, binds), make([]interface{}, 0))
+	return sql
+}
+
+// Returns the ordered list of inserted columns
+func (s *DefaultInsert) columns() []string {
+	if len(s.Cols) > 0 {
+		return s.Cols
+	}
+
+	cols := make([]string, 0, len(s.Data[0]))
+	for col := range s.Data[0] {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	return cols
+}
+
+// Renders a value as placeholder or expression
+func (s *DefaultInsert) value(val interface{}, binds *placeholders) string {
+	switch v := val.(type) {
+	case *SQLExpr:
+		return v.ToString()
+
+	case Select:
+		sql, err := binds.embed(s.Adapter, v)
+		if err != nil {
+			s.Errors = append(s.Errors, err)
+		}
+
+		return "(" + sql + ")"
+	}
+
+	return binds.bind(val)
+}
+
+// Render upsert clause
+func (s *DefaultInsert) renderUpsert(sql string, binds *placeholders) string {
+	if !s.Upsert {
+		return sql
+	}
+
+	assignments := make([]string, 0, len(s.UpdateSet))
+	for _, set := range s.UpdateSet {
+		switch v := set.(type) {
+		case string:
+			assignments = append(assignments, s.Adapter.QuoteIdentifier(v, true)+" = "+s.Adapter.InsertedValue(v))
+
+		case map[string]interface{}:
+			for col, val := range v {
+				assignments = append(assignments, s.Adapter.QuoteIdentifier(col, true)+" = "+s.value(val, binds))
+			}
+		}
+	}
+
+	clause, err := s.Adapter.UpsertClause(s.Conflict, assignments)
+	if err != nil {
+		s.Errors = append(s.Errors, err)
+		return sql
+	}
+
+	return sql + " " + clause
+}
+
+// NewInsert creates a new insert statement for adapter
+func NewInsert(adapter Adapter) InsertStatement {
+	return &DefaultInsert{
+		Adapter:   adapter,
+		Cols:      make([]string, 0),
+		Data:      make([]map[string]interface{}, 0),
+		Conflict:  make([]string, 0),
+		UpdateSet: make([]interface{}, 0),
+		Bind:      make([]interface{}, 0),
+		Errors:    make([]error, 0),
+	}
+}
@@ -0,0 +1,559 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// TagName is a struct tag used to map struct fields to table columns
+// Format is `db:"column,omitempty,readonly"`, `db:"-"` skips the field
+const TagName = "db"
+
+var (
+	structMappings = make(map[reflect.Type]*StructMapping)
+	structMu       sync.RWMutex
+
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// FieldMapping describes a mapping of struct field to table column
+type FieldMapping struct {
+	Column    string
+	Index     []int
+	Type      reflect.Type
+	OmitEmpty bool
+	ReadOnly  bool
+}
+
+// StructMapping describes a mapping of struct type to table columns
+type StructMapping struct {
+	Type    reflect.Type
+	Fields  []*FieldMapping
+	columns map[string]*FieldMapping
+	names   map[string]*FieldMapping
+}
+
+// Field returns field mapping by column name
+// Columns are matched case insensitive if there is no exact match.
+// Columns matching none are looked up by lower cased name of untagged field,
+// so column "userid" is still mapped to field UserID
+func (m *StructMapping) Field(column string) (*FieldMapping, bool) {
+	if f, ok := m.columns[column]; ok {
+		return f, true
+	}
+
+	column = strings.ToLower(column)
+	if f, ok := m.columns[column]; ok {
+		return f, true
+	}
+
+	f, ok := m.names[column]
+	return f, ok
+}
+
+// Columns returns mapped column names
+func (m *StructMapping) Columns() []string {
+	cols := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		cols[i] = f.Column
+	}
+
+	return cols
+}
+
+// MappingOf returns a column mapping of struct type
+// Value can be a struct, a pointer to struct or a struct type
+func MappingOf(v interface{}) (*StructMapping, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("Struct mapping requires a struct, got '%v'", t)
+	}
+
+	structMu.RLock()
+	m, ok := structMappings[t]
+	structMu.RUnlock()
+	if ok {
+		return m, nil
+	}
+
+	m = &StructMapping{
+		Type:    t,
+		Fields:  make([]*FieldMapping, 0),
+		columns: make(map[string]*FieldMapping),
+		names:   make(map[string]*FieldMapping),
+	}
+	mapStructFields(m, t, nil)
+
+	structMu.Lock()
+	structMappings[t] = m
+	structMu.Unlock()
+	return m, nil
+}
+
+// Collects mapped fields of struct type, fields of embedded structs are promoted
+func mapStructFields(m *StructMapping, t reflect.Type, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(TagName)
+		if tag == "-" {
+			continue
+		}
+
+		idx := make([]int, len(index), len(index)+1)
+		copy(idx, index)
+		idx = append(idx, i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && tag == "" && ft.Kind() == reflect.Struct && !isScalarStruct(ft) {
+			mapStructFields(m, ft, idx)
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		f := &FieldMapping{
+			Column: columnName(sf.Name),
+			Index:  idx,
+			Type:   sf.Type,
+		}
+
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			f.Column = parts[0]
+		}
+
+		for _, opt := range parts[1:] {
+			switch strings.TrimSpace(opt) {
+			case "omitempty":
+				f.OmitEmpty = true
+
+			case "readonly":
+				f.ReadOnly = true
+			}
+		}
+
+		if _, ok := m.columns[f.Column]; ok {
+			continue
+		}
+
+		m.Fields = append(m.Fields, f)
+		m.columns[f.Column] = f
+		if _, ok := m.columns[strings.ToLower(f.Column)]; !ok {
+			m.columns[strings.ToLower(f.Column)] = f
+		}
+
+		if parts[0] == "" {
+			if _, ok := m.names[strings.ToLower(sf.Name)]; !ok {
+				m.names[strings.ToLower(sf.Name)] = f
+			}
+		}
+	}
+}
+
+// Returns true if struct type represents a single column value
+func isScalarStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PtrTo(t).Implements(scannerType) || t.Implements(valuerType)
+}
+
+// Converts field name into snake case column name
+func columnName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// StructToMap converts struct into column data suitable for Insert and Update
+// Read only fields are skipped as well as empty fields tagged with omitempty
+func StructToMap(input interface{}) (map[string]interface{}, error) {
+	m, err := MappingOf(input)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(input))
+	data := make(map[string]interface{})
+	for _, f := range m.Fields {
+		if f.ReadOnly {
+			continue
+		}
+
+		fv, ok := fieldByIndex(v, f.Index, false)
+		if !ok || (f.OmitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+
+		value, err := columnValue(fv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to get value of column '%s'", f.Column)
+		}
+
+		data[f.Column] = value
+	}
+
+	return data, nil
+}
+
+// MapToStruct copies column data into struct pointed by output
+// Columns without a matching field are ignored
+func MapToStruct(data map[string]interface{}, output interface{}) error {
+	v := reflect.ValueOf(output)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.Errorf("Struct mapping requires a non nil pointer to struct, got '%T'", output)
+	}
+
+	m, err := MappingOf(output)
+	if err != nil {
+		return err
+	}
+
+	v = v.Elem()
+	for col, value := range data {
+		f, ok := m.Field(col)
+		if !ok {
+			continue
+		}
+
+		fv, _ := fieldByIndex(v, f.Index, true)
+		if err := assignValue(fv, value); err != nil {
+			return errors.Wrapf(err, "Unable to map column '%s' into field of type '%s'", col, f.Type)
+		}
+	}
+
+	return nil
+}
+
+// MapToSlice fills slice pointed by output with structs made from rows
+// Slice elements can be structs or pointers to structs
+func MapToSlice(rows []map[string]interface{}, output interface{}) error {
+	v := reflect.ValueOf(output)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return errors.Errorf("Struct mapping requires a pointer to slice, got '%T'", output)
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(rows))
+	for _, row := range rows {
+		item := reflect.New(elemType)
+		if err := MapToStruct(row, item.Interface()); err != nil {
+			return err
+		}
+
+		if isPtr {
+			result = reflect.Append(result, item)
+		} else {
+			result = reflect.Append(result, item.Elem())
+		}
+	}
+
+	slice.Set(result)
+	return nil
+}
+
+// Returns struct field by index path allocating nil embedded pointers if alloc is set
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// Returns true if value is a zero value of its type
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	if vl, ok := v.Interface().(driver.Valuer); ok {
+		value, err := vl.Value()
+		return err == nil && value == nil
+	}
+
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// Returns value of struct field to be passed to database
+func columnValue(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		if _, ok := v.Interface().(driver.Valuer); !ok {
+			return v.Elem().Interface(), nil
+		}
+	}
+
+	if vl, ok := v.Interface().(driver.Valuer); ok {
+		return vl.Value()
+	}
+
+	return v.Interface(), nil
+}
+
+// Assigns a database value to struct field converting it if necessary
+func assignValue(field reflect.Value, value interface{}) error {
+	if field.Kind() == reflect.Ptr {
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return assignValue(field.Elem(), value)
+	}
+
+	if field.CanAddr() {
+		if sc, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return sc.Scan(value)
+		}
+	}
+
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case []byte:
+			field.SetString(string(v))
+
+		case time.Time:
+			field.SetString(v.Format(time.RFC3339))
+
+		default:
+			s, ok := formatScalar(rv)
+			if !ok {
+				if rv.Kind() != reflect.String {
+					return errors.Errorf("Cannot assign value of type '%T'", value)
+				}
+
+				s = rv.String()
+			}
+
+			field.SetString(s)
+		}
+
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt64(rv)
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toInt64(rv)
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(uint64(n))
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(rv)
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+		return nil
+
+	case reflect.Bool:
+		switch {
+		case rv.Kind() == reflect.Bool:
+			field.SetBool(rv.Bool())
+
+		default:
+			n, err := toInt64(rv)
+			if err != nil {
+				b, berr := strconv.ParseBool(stringOf(rv))
+				if berr != nil {
+					return err
+				}
+
+				field.SetBool(b)
+				return nil
+			}
+
+			field.SetBool(n != 0)
+		}
+
+		return nil
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 && rv.Kind() == reflect.String {
+			field.SetBytes([]byte(rv.String()))
+			return nil
+		}
+	}
+
+	if field.Type() == timeType {
+		if s := stringOf(rv); s != "" {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05", "2006-01-02"} {
+				if t, err := time.Parse(layout, s); err == nil {
+					field.Set(reflect.ValueOf(t))
+					return nil
+				}
+			}
+		}
+	}
+
+	if rv.Type().ConvertibleTo(field.Type()) {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+
+	return errors.Errorf("Cannot assign value of type '%T'", value)
+}
+
+// Returns string representation of scalar value
+func formatScalar(rv reflect.Value) (string, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), true
+
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true
+	}
+
+	return "", false
+}
+
+// Returns string or bytes value as string
+func stringOf(rv reflect.Value) string {
+	if rv.Kind() == reflect.String {
+		return rv.String()
+	}
+
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		return string(rv.Bytes())
+	}
+
+	return ""
+}
+
+// Converts numeric, boolean or string value to int64
+func toInt64(rv reflect.Value) (int64, error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float()), nil
+
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1, nil
+		}
+
+		return 0, nil
+	}
+
+	if s := stringOf(rv); s != "" {
+		return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	}
+
+	return 0, errors.Errorf("Cannot convert value of type '%s' to integer", rv.Type())
+}
+
+// Converts numeric or string value to float64
+func toFloat64(rv reflect.Value) (float64, error) {
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Bool:
+		n, err := toInt64(rv)
+		return float64(n), err
+	}
+
+	if s := stringOf(rv); s != "" {
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+
+	return 0, errors.Errorf("Cannot convert value of type '%s' to float", rv.Type())
+}
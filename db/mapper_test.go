@@ -0,0 +1,40 @@
+package db
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Value failing to convert itself for database
+type brokenValue struct{}
+
+func (v brokenValue) Value() (driver.Value, error) {
+	return nil, errors.New("broken value")
+}
+
+func TestStructToMapValuerError(t *testing.T) {
+	type record struct {
+		ID    int         `db:"id"`
+		Value brokenValue `db:"value"`
+	}
+
+	if data, err := StructToMap(&record{ID: 1}); err == nil {
+		t.Fatalf("StructToMap = %v, want error", data)
+	}
+
+	type nullable struct {
+		ID   int     `db:"id"`
+		Name *string `db:"name"`
+	}
+
+	data, err := StructToMap(&nullable{ID: 1})
+	if err != nil {
+		t.Fatalf("StructToMap: %v", err)
+	}
+
+	if data["id"] != 1 || data["name"] != nil {
+		t.Fatalf("StructToMap = %v", data)
+	}
+}
@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"github.com/noxyicm/wsf/config"
+)
+
+// Config defines set of migrator variables
+type Config struct {
+	Adapter       string
+	Directory     string
+	Table         string
+	AutoMigrate   bool
+	Transactional bool
+}
+
+// Populate populates Config values using given Config source
+func (c *Config) Populate(cfg config.Config) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *Config) Defaults() error {
+	c.Adapter = ""
+	c.Directory = "migrations"
+	c.Table = "schema_migrations"
+	c.AutoMigrate = false
+	c.Transactional = true
+	return nil
+}
+
+// Valid validates the configuration
+func (c *Config) Valid() error {
+	return nil
+}
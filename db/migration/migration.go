@@ -0,0 +1,323 @@
+package migration
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Public constants
+const (
+	SourceGo  = "go"
+	SourceSQL = "sql"
+
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+var (
+	registered = map[int64]Migration{}
+	mu         sync.Mutex
+
+	// RegexpFileName matches migration file names like 20200101120000_create_users.up.sql
+	RegexpFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+	regexpDollarTag = regexp.MustCompile(`^\$[A-Za-z_]*\$$`)
+)
+
+// Func is a migration step function
+type Func func(ctx context.Context, exec Executor) error
+
+// Migration represents a single schema version
+type Migration interface {
+	Version() int64
+	Name() string
+	Source() string
+	Up(ctx context.Context, exec Executor) error
+	Down(ctx context.Context, exec Executor) error
+}
+
+// Executor runs statements of a migration
+// either directly through adapter or inside a transaction
+type Executor interface {
+	Adapter() db.Adapter
+	Exec(query string, binds ...interface{}) (sql.Result, error)
+	Query(dbs db.Select) ([]map[string]interface{}, error)
+}
+
+// Register registers a go migration to be picked up by every migrator
+func Register(version int64, name string, up Func, down Func) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registered[version] = NewFuncMigration(version, name, up, down)
+}
+
+// Registered returns all registered go migrations ordered by version
+func Registered() []Migration {
+	mu.Lock()
+	defer mu.Unlock()
+
+	migrations := make([]Migration, 0, len(registered))
+	for _, m := range registered {
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version() < migrations[j].Version() })
+	return migrations
+}
+
+// FuncMigration is a migration defined by go functions
+type FuncMigration struct {
+	version int64
+	name    string
+	up      Func
+	down    Func
+}
+
+// Version returns migration version
+func (m *FuncMigration) Version() int64 {
+	return m.version
+}
+
+// Name returns migration name
+func (m *FuncMigration) Name() string {
+	return m.name
+}
+
+// Source returns migration source type
+func (m *FuncMigration) Source() string {
+	return SourceGo
+}
+
+// Up applies migration
+func (m *FuncMigration) Up(ctx context.Context, exec Executor) error {
+	if m.up == nil {
+		return nil
+	}
+
+	return m.up(ctx, exec)
+}
+
+// Down reverts migration
+func (m *FuncMigration) Down(ctx context.Context, exec Executor) error {
+	if m.down == nil {
+		return errors.Errorf("Migration %d '%s' is irreversible", m.version, m.name)
+	}
+
+	return m.down(ctx, exec)
+}
+
+// NewFuncMigration creates a new go function migration
+func NewFuncMigration(version int64, name string, up Func, down Func) Migration {
+	return &FuncMigration{
+		version: version,
+		name:    name,
+		up:      up,
+		down:    down,
+	}
+}
+
+// SQLMigration is a migration loaded from .sql files
+type SQLMigration struct {
+	version  int64
+	name     string
+	upFile   string
+	downFile string
+}
+
+// Version returns migration version
+func (m *SQLMigration) Version() int64 {
+	return m.version
+}
+
+// Name returns migration name
+func (m *SQLMigration) Name() string {
+	return m.name
+}
+
+// Source returns migration source type
+func (m *SQLMigration) Source() string {
+	return SourceSQL
+}
+
+// Up applies migration
+func (m *SQLMigration) Up(ctx context.Context, exec Executor) error {
+	if m.upFile == "" {
+		return nil
+	}
+
+	return m.run(m.upFile, exec)
+}
+
+// Down reverts migration
+func (m *SQLMigration) Down(ctx context.Context, exec Executor) error {
+	if m.downFile == "" {
+		return errors.Errorf("Migration %d '%s' is irreversible", m.version, m.name)
+	}
+
+	return m.run(m.downFile, exec)
+}
+
+// run executes every statement of the file
+func (m *SQLMigration) run(file string, exec Executor) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to read migration file '%s'", file)
+	}
+
+	for _, statement := range SplitStatements(string(content)) {
+		if _, err := exec.Exec(statement); err != nil {
+			return errors.Wrapf(err, "Migration %d '%s' failed", m.version, m.name)
+		}
+	}
+
+	return nil
+}
+
+// LoadDirectory loads .sql migrations from directory
+func LoadDirectory(dir string) ([]Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Migration{}, nil
+		}
+
+		return nil, errors.Wrapf(err, "Unable to read migrations directory '%s'", dir)
+	}
+
+	found := make(map[int64]*SQLMigration)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		m := RegexpFileName.FindStringSubmatch(file.Name())
+		if len(m) == 0 {
+			continue
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid migration version in '%s'", file.Name())
+		}
+
+		mig, ok := found[version]
+		if !ok {
+			mig = &SQLMigration{version: version, name: m[2]}
+			found[version] = mig
+		} else if mig.name != m[2] {
+			return nil, errors.Errorf("Migration version %d is used by '%s' and '%s'", version, mig.name, m[2])
+		}
+
+		if m[3] == DirectionUp {
+			mig.upFile = filepath.Join(dir, file.Name())
+		} else {
+			mig.downFile = filepath.Join(dir, file.Name())
+		}
+	}
+
+	migrations := make([]Migration, 0, len(found))
+	for _, mig := range found {
+		migrations = append(migrations, mig)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version() < migrations[j].Version() })
+	return migrations, nil
+}
+
+// SplitStatements splits sql script into separate statements
+// Semicolons inside quotes, comments and dollar quoted bodies are ignored
+func SplitStatements(script string) []string {
+	statements := make([]string, 0)
+	var buf strings.Builder
+	var quote byte
+	dollarTag := ""
+	lineComment := false
+	blockComment := false
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		switch {
+		case lineComment:
+			if c == '\n' {
+				lineComment = false
+				buf.WriteByte(c)
+			}
+			continue
+
+		case blockComment:
+			if c == '*' && i+1 < len(script) && script[i+1] == '/' {
+				blockComment = false
+				i++
+			}
+			continue
+
+		case dollarTag != "":
+			if strings.HasPrefix(script[i:], dollarTag) {
+				buf.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				dollarTag = ""
+				continue
+			}
+
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && i+1 < len(script) {
+				buf.WriteByte(c)
+				i++
+				c = script[i]
+			}
+
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			lineComment = true
+			continue
+
+		case c == '/' && i+1 < len(script) && script[i+1] == '*':
+			blockComment = true
+			i++
+			continue
+
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+
+		case c == '$':
+			if end := strings.IndexByte(script[i+1:], '$'); end >= 0 {
+				tag := script[i : i+end+2]
+				if regexpDollarTag.MatchString(tag) {
+					dollarTag = tag
+					buf.WriteString(tag)
+					i += len(tag) - 1
+					continue
+				}
+			}
+
+		case c == ';':
+			if statement := strings.TrimSpace(buf.String()); statement != "" {
+				statements = append(statements, statement)
+			}
+			buf.Reset()
+			continue
+		}
+
+		buf.WriteByte(c)
+	}
+
+	if statement := strings.TrimSpace(buf.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
@@ -0,0 +1,89 @@
+package migration
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	cases := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			"plain",
+			"CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);",
+			[]string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			"quotes",
+			`INSERT INTO a VALUES ('x;y', "z;", 'it''s;'); INSERT INTO a VALUES ('a\';b')`,
+			[]string{`INSERT INTO a VALUES ('x;y', "z;", 'it''s;')`, `INSERT INTO a VALUES ('a\';b')`},
+		},
+		{
+			"comments",
+			"-- first; statement\nSELECT 1; /* block; comment */ SELECT 2;",
+			[]string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			"dollar quoted body",
+			"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql; SELECT $$a;b$$",
+			[]string{"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql", "SELECT $$a;b$$"},
+		},
+		{
+			"empty statements",
+			" ; ;\n",
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := SplitStatements(c.script); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"2_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email TEXT",
+		"1_create_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY)",
+		"1_create_users.down.sql": "DROP TABLE users",
+		"readme.txt":              "not a migration",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrations, err := LoadDirectory(dir)
+	if err != nil {
+		t.Fatalf("LoadDirectory: %v", err)
+	}
+
+	if len(migrations) != 2 || migrations[0].Version() != 1 || migrations[1].Name() != "add_email" || migrations[1].Source() != SourceSQL {
+		t.Fatalf("LoadDirectory = %v", migrations)
+	}
+
+	if mig := migrations[1].(*SQLMigration); mig.downFile != "" {
+		t.Fatalf("migration without down file has down file '%s'", mig.downFile)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "2_other.down.sql"), []byte("SELECT 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadDirectory(dir); err == nil {
+		t.Fatal("LoadDirectory with duplicated version = nil, want error")
+	}
+
+	if migrations, err := LoadDirectory(filepath.Join(dir, "missing")); err != nil || len(migrations) != 0 {
+		t.Fatalf("LoadDirectory of missing directory = %v, %v", migrations, err)
+	}
+}
@@ -0,0 +1,437 @@
+package migration
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/utils"
+)
+
+// Status represents migration state
+type Status struct {
+	Version   int64
+	Name      string
+	Source    string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts schema migrations
+type Migrator struct {
+	Options    *Config
+	Adapter    db.Adapter
+	migrations []Migration
+	loaded     bool
+	mu         sync.Mutex
+}
+
+// Add adds migrations to migrator
+func (m *Migrator) Add(migrations ...Migration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.add(migrations...)
+}
+
+// Adds migrations keeping them ordered by version, caller must hold the lock
+func (m *Migrator) add(migrations ...Migration) error {
+	for _, mig := range migrations {
+		for _, existing := range m.migrations {
+			if existing.Version() == mig.Version() {
+				return errors.Errorf("Migration version %d is used by '%s' and '%s'", mig.Version(), existing.Name(), mig.Name())
+			}
+		}
+
+		m.migrations = append(m.migrations, mig)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool { return m.migrations[i].Version() < m.migrations[j].Version() })
+	return nil
+}
+
+// Load loads registered go migrations and sql migrations from directory
+func (m *Migrator) Load() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loaded {
+		return nil
+	}
+
+	migrations := Registered()
+	if m.Options.Directory != "" {
+		dirMigrations, err := LoadDirectory(m.Directory())
+		if err != nil {
+			return err
+		}
+
+		migrations = append(migrations, dirMigrations...)
+	}
+
+	if err := m.add(migrations...); err != nil {
+		return err
+	}
+
+	m.loaded = true
+	return nil
+}
+
+// Directory returns absolute path to migrations directory
+func (m *Migrator) Directory() string {
+	if filepath.IsAbs(m.Options.Directory) {
+		return m.Options.Directory
+	}
+
+	return filepath.Join(config.AppRootPath, filepath.FromSlash(m.Options.Directory))
+}
+
+// Migrations returns all known migrations
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status returns state of each known migration
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	if err := m.Load(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := &Status{
+			Version: mig.Version(),
+			Name:    mig.Name(),
+			Source:  mig.Source(),
+		}
+
+		if a, ok := applied[mig.Version()]; ok {
+			st.Applied = true
+			st.AppliedAt = a.AppliedAt
+		}
+
+		statuses = append(statuses, st)
+	}
+
+	return statuses, nil
+}
+
+// Migrate applies all pending migrations and returns the number of applied ones
+func (m *Migrator) Migrate(ctx context.Context) (int, error) {
+	if err := m.Load(); err != nil {
+		return 0, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version()]; ok {
+			continue
+		}
+
+		if err := m.run(ctx, mig, DirectionUp); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// Rollback reverts the given number of last applied migrations
+func (m *Migrator) Rollback(ctx context.Context, steps int) (int, error) {
+	reverted, err := m.rollback(ctx, steps)
+	return len(reverted), err
+}
+
+// Redo reverts and applies again the last applied migration
+func (m *Migrator) Redo(ctx context.Context) error {
+	reverted, err := m.rollback(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	// Only the reverted migration is applied, pending ones are left for Migrate
+	for _, mig := range reverted {
+		if err := m.run(ctx, mig, DirectionUp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollback reverts the given number of last applied migrations and returns reverted ones
+func (m *Migrator) rollback(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.Load(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	reverted := make([]Migration, 0, steps)
+	for _, version := range versions {
+		if len(reverted) >= steps {
+			break
+		}
+
+		mig := m.find(version)
+		if mig == nil {
+			return reverted, errors.Errorf("Applied migration %d '%s' is unknown", version, applied[version].Name)
+		}
+
+		if err := m.run(ctx, mig, DirectionDown); err != nil {
+			return reverted, err
+		}
+
+		reverted = append(reverted, mig)
+	}
+
+	return reverted, nil
+}
+
+// run applies or reverts a single migration and updates the tracking table
+func (m *Migrator) run(ctx context.Context, mig Migration, direction string) (err error) {
+	var exec Executor
+	var tx db.Transaction
+	if m.Options.Transactional && m.Adapter.SupportsParameters("transactionalDDL") {
+		tx, err = m.Adapter.BeginTransaction(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to begin transaction for migration %d '%s'", mig.Version(), mig.Name())
+		}
+
+		exec = &transactionExecutor{adapter: m.Adapter, tx: tx}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			}
+		}()
+	} else {
+		exec = &adapterExecutor{adapter: m.Adapter, ctx: ctx}
+	}
+
+	if direction == DirectionUp {
+		if err = mig.Up(ctx, exec); err != nil {
+			return errors.Wrapf(err, "Unable to apply migration %d '%s'", mig.Version(), mig.Name())
+		}
+
+		_, err = exec.Exec("INSERT INTO " + m.Adapter.QuoteIdentifier(m.Options.Table, true) +
+			" (" + m.Adapter.QuoteIdentifier("version", true) + ", " + m.Adapter.QuoteIdentifier("name", true) + ", " + m.Adapter.QuoteIdentifier("applied_at", true) + ")" +
+			" VALUES (" + m.Adapter.Quote(mig.Version()) + ", " + m.Adapter.Quote(mig.Name()) + ", " + m.Adapter.Quote(time.Now().UTC().Format("2006-01-02 15:04:05")) + ")")
+	} else {
+		if err = mig.Down(ctx, exec); err != nil {
+			return errors.Wrapf(err, "Unable to revert migration %d '%s'", mig.Version(), mig.Name())
+		}
+
+		_, err = exec.Exec("DELETE FROM " + m.Adapter.QuoteIdentifier(m.Options.Table, true) +
+			" WHERE " + m.Adapter.QuoteIdentifier("version", true) + " = " + m.Adapter.Quote(mig.Version()))
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "Unable to record migration %d '%s'", mig.Version(), mig.Name())
+	}
+
+	if tx != nil {
+		if err = tx.Commit(); err != nil {
+			return errors.Wrapf(err, "Unable to commit migration %d '%s'", mig.Version(), mig.Name())
+		}
+	}
+
+	return nil
+}
+
+// applied returns applied migrations from the tracking table
+func (m *Migrator) applied(ctx context.Context) (map[int64]*Status, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	dbs := m.Adapter.Select().
+		From(m.Options.Table, []string{"version", "name", "applied_at"}).
+		Order("version ASC")
+	if err := dbs.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.Adapter.Query(ctx, dbs)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read applied migrations")
+	}
+
+	applied := make(map[int64]*Status)
+	for _, row := range rows {
+		version, err := toInt64(row["version"])
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid migration version in tracking table")
+		}
+
+		st := &Status{Version: version, Applied: true}
+		st.Name, _ = utils.InterfaceToString(row["name"])
+		switch v := row["applied_at"].(type) {
+		case time.Time:
+			st.AppliedAt = v
+
+		case string:
+			st.AppliedAt, _ = time.Parse("2006-01-02 15:04:05", v)
+
+		case []byte:
+			st.AppliedAt, _ = time.Parse("2006-01-02 15:04:05", string(v))
+		}
+
+		applied[version] = st
+	}
+
+	return applied, nil
+}
+
+// createTable creates the tracking table if not exists
+func (m *Migrator) createTable(ctx context.Context) error {
+	_, err := m.Adapter.Exec(ctx, "CREATE TABLE IF NOT EXISTS "+m.Adapter.QuoteIdentifier(m.Options.Table, true)+" ("+
+		m.Adapter.QuoteIdentifier("version", true)+" BIGINT NOT NULL PRIMARY KEY, "+
+		m.Adapter.QuoteIdentifier("name", true)+" VARCHAR(255) NOT NULL, "+
+		m.Adapter.QuoteIdentifier("applied_at", true)+" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	if err != nil {
+		return errors.Wrapf(err, "Unable to create migrations table '%s'", m.Options.Table)
+	}
+
+	return nil
+}
+
+// find returns migration by version
+func (m *Migrator) find(version int64) Migration {
+	for _, mig := range m.migrations {
+		if mig.Version() == version {
+			return mig
+		}
+	}
+
+	return nil
+}
+
+// NewMigrator creates a new migrator
+func NewMigrator(options config.Config) (*Migrator, error) {
+	cfg := &Config{}
+	cfg.Defaults()
+	if err := cfg.Populate(options); err != nil {
+		return nil, err
+	}
+
+	return NewMigratorFromConfig(cfg, nil)
+}
+
+// NewMigratorFromConfig creates a new migrator from config
+// If adapter is nil the one named in config or the default one is used
+func NewMigratorFromConfig(cfg *Config, adp db.Adapter) (*Migrator, error) {
+	if adp == nil {
+		if cfg.Adapter != "" {
+			a, err := db.SetupAdapter(cfg.Adapter)
+			if err != nil {
+				return nil, err
+			}
+
+			adp = a
+		} else {
+			adp = db.GetDefaultAdapter()
+		}
+	}
+
+	if adp == nil {
+		return nil, errors.New("Migrator requires a database adapter")
+	}
+
+	if strings.TrimSpace(cfg.Table) == "" {
+		return nil, errors.New("Migrations table name can not be empty")
+	}
+
+	return &Migrator{
+		Options:    cfg,
+		Adapter:    adp,
+		migrations: make([]Migration, 0),
+	}, nil
+}
+
+// adapterExecutor runs statements directly through adapter
+type adapterExecutor struct {
+	adapter db.Adapter
+	ctx     context.Context
+}
+
+// Adapter returns database adapter
+func (e *adapterExecutor) Adapter() db.Adapter {
+	return e.adapter
+}
+
+// Exec executes a raw query
+func (e *adapterExecutor) Exec(query string, binds ...interface{}) (sql.Result, error) {
+	return e.adapter.Exec(e.ctx, query, binds...)
+}
+
+// Query runs a select query
+func (e *adapterExecutor) Query(dbs db.Select) ([]map[string]interface{}, error) {
+	return e.adapter.Query(e.ctx, dbs)
+}
+
+// transactionExecutor runs statements inside transaction
+type transactionExecutor struct {
+	adapter db.Adapter
+	tx      db.Transaction
+}
+
+// Adapter returns database adapter
+func (e *transactionExecutor) Adapter() db.Adapter {
+	return e.adapter
+}
+
+// Exec executes a raw query
+func (e *transactionExecutor) Exec(query string, binds ...interface{}) (sql.Result, error) {
+	return e.tx.Exec(query, binds...)
+}
+
+// Query runs a select query
+func (e *transactionExecutor) Query(dbs db.Select) ([]map[string]interface{}, error) {
+	return e.tx.Query(dbs)
+}
+
+// toInt64 converts value from tracking table to version number
+func toInt64(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int64:
+		return v, nil
+
+	case []byte:
+		return toInt64(string(v))
+
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	i, err := utils.InterfaceToInt(val)
+	return int64(i), err
+}
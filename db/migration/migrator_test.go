@@ -0,0 +1,166 @@
+package migration
+
+import (
+	goctx "context"
+	"testing"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/db/sqlite"
+)
+
+// Creates migrator working on in-memory database
+func newTestMigrator(t *testing.T, migrations ...Migration) (*Migrator, context.Context) {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{
+		"adapter": map[string]interface{}{
+			"type":   sqlite.TYPEAdapter,
+			"dbname": sqlite.Memory,
+		},
+	})
+
+	d, err := db.NewDB(cfg)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	db.SetInstance(d)
+
+	mcfg := &Config{}
+	mcfg.Defaults()
+	mcfg.Directory = ""
+	m, err := NewMigratorFromConfig(mcfg, d.Adapter())
+	if err != nil {
+		t.Fatalf("NewMigratorFromConfig: %v", err)
+	}
+
+	if err := m.Add(migrations...); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	ctx, _ := context.NewContext(goctx.Background())
+	return m, ctx
+}
+
+// Creates migration executing statements and counting runs
+func newTableMigration(version int64, name string, up string, down string, runs map[string]int) Migration {
+	return NewFuncMigration(version, name, func(ctx context.Context, exec Executor) error {
+		runs[name+" up"]++
+		_, err := exec.Exec(up)
+		return err
+	}, func(ctx context.Context, exec Executor) error {
+		runs[name+" down"]++
+		_, err := exec.Exec(down)
+		return err
+	})
+}
+
+// Returns versions of applied migrations
+func appliedVersions(t *testing.T, m *Migrator, ctx context.Context) []int64 {
+	t.Helper()
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+
+	versions := make([]int64, 0)
+	for _, st := range statuses {
+		if st.Applied {
+			versions = append(versions, st.Version)
+		}
+	}
+
+	return versions
+}
+
+func TestMigratorUpDownStatus(t *testing.T) {
+	runs := make(map[string]int)
+	m, ctx := newTestMigrator(t,
+		newTableMigration(2, "create_posts", "CREATE TABLE posts (id INTEGER PRIMARY KEY)", "DROP TABLE posts", runs),
+		newTableMigration(1, "create_users", "CREATE TABLE users (id INTEGER PRIMARY KEY)", "DROP TABLE users", runs),
+	)
+
+	if err := m.Add(NewFuncMigration(1, "duplicate", nil, nil)); err == nil {
+		t.Fatal("Add of duplicated version = nil, want error")
+	}
+
+	if n, err := m.Migrate(ctx); err != nil || n != 2 {
+		t.Fatalf("Migrate = %d, %v, want 2", n, err)
+	}
+
+	if versions := appliedVersions(t, m, ctx); len(versions) != 2 {
+		t.Fatalf("applied versions = %v", versions)
+	}
+
+	if n, err := m.Migrate(ctx); err != nil || n != 0 {
+		t.Fatalf("Migrate of migrated database = %d, %v, want 0", n, err)
+	}
+
+	if n, err := m.Rollback(ctx, 1); err != nil || n != 1 {
+		t.Fatalf("Rollback = %d, %v, want 1", n, err)
+	}
+
+	if versions := appliedVersions(t, m, ctx); len(versions) != 1 || versions[0] != 1 {
+		t.Fatalf("applied versions after Rollback = %v, want [1]", versions)
+	}
+
+	if _, err := m.Adapter.Exec(ctx, "SELECT id FROM posts"); err == nil {
+		t.Fatal("table of reverted migration exists")
+	}
+
+	if n, err := m.Rollback(ctx, 5); err != nil || n != 1 {
+		t.Fatalf("Rollback = %d, %v, want 1", n, err)
+	}
+
+	if runs["create_users up"] != 1 || runs["create_users down"] != 1 || runs["create_posts up"] != 1 || runs["create_posts down"] != 1 {
+		t.Fatalf("runs = %v", runs)
+	}
+}
+
+func TestMigratorFailedMigrationIsRolledBack(t *testing.T) {
+	runs := make(map[string]int)
+	m, ctx := newTestMigrator(t,
+		newTableMigration(1, "create_users", "CREATE TABLE users (id INTEGER PRIMARY KEY)", "DROP TABLE users", runs),
+		newTableMigration(2, "broken", "CREATE TABLE", "", runs),
+	)
+
+	if n, err := m.Migrate(ctx); err == nil || n != 1 {
+		t.Fatalf("Migrate = %d, %v, want error after 1", n, err)
+	}
+
+	if versions := appliedVersions(t, m, ctx); len(versions) != 1 || versions[0] != 1 {
+		t.Fatalf("applied versions = %v, want [1]", versions)
+	}
+}
+
+func TestMigratorRedo(t *testing.T) {
+	runs := make(map[string]int)
+	m, ctx := newTestMigrator(t,
+		newTableMigration(1, "create_users", "CREATE TABLE users (id INTEGER PRIMARY KEY)", "DROP TABLE users", runs),
+	)
+
+	if _, err := m.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	// Migration added later is pending and must not be applied by Redo
+	if err := m.Add(newTableMigration(2, "create_posts", "CREATE TABLE posts (id INTEGER PRIMARY KEY)", "DROP TABLE posts", runs)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if err := m.Redo(ctx); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+
+	if runs["create_users down"] != 1 || runs["create_users up"] != 2 || runs["create_posts up"] != 0 {
+		t.Fatalf("runs = %v", runs)
+	}
+
+	if versions := appliedVersions(t, m, ctx); len(versions) != 1 || versions[0] != 1 {
+		t.Fatalf("applied versions after Redo = %v, want [1]", versions)
+	}
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+var jsonNull = []byte("null")
+
+// NullString represents a string column that may be null
+// It is marshaled to JSON as a string or null
+type NullString struct {
+	sql.NullString
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(n.String)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	n.Valid = string(b) != "null"
+	if !n.Valid {
+		n.String = ""
+		return nil
+	}
+
+	return json.Unmarshal(b, &n.String)
+}
+
+// NewNullString creates a valid NullString
+func NewNullString(v string) NullString {
+	return NullString{sql.NullString{String: v, Valid: true}}
+}
+
+// NullInt64 represents an integer column that may be null
+// It is marshaled to JSON as a number or null
+type NullInt64 struct {
+	sql.NullInt64
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(n.Int64)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullInt64) UnmarshalJSON(b []byte) error {
+	n.Valid = string(b) != "null"
+	if !n.Valid {
+		n.Int64 = 0
+		return nil
+	}
+
+	return json.Unmarshal(b, &n.Int64)
+}
+
+// NewNullInt64 creates a valid NullInt64
+func NewNullInt64(v int64) NullInt64 {
+	return NullInt64{sql.NullInt64{Int64: v, Valid: true}}
+}
+
+// NullFloat64 represents a float column that may be null
+// It is marshaled to JSON as a number or null
+type NullFloat64 struct {
+	sql.NullFloat64
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullFloat64) UnmarshalJSON(b []byte) error {
+	n.Valid = string(b) != "null"
+	if !n.Valid {
+		n.Float64 = 0
+		return nil
+	}
+
+	return json.Unmarshal(b, &n.Float64)
+}
+
+// NewNullFloat64 creates a valid NullFloat64
+func NewNullFloat64(v float64) NullFloat64 {
+	return NullFloat64{sql.NullFloat64{Float64: v, Valid: true}}
+}
+
+// NullBool represents a boolean column that may be null
+// It is marshaled to JSON as a boolean or null
+type NullBool struct {
+	sql.NullBool
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(n.Bool)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	n.Valid = string(b) != "null"
+	if !n.Valid {
+		n.Bool = false
+		return nil
+	}
+
+	return json.Unmarshal(b, &n.Bool)
+}
+
+// NewNullBool creates a valid NullBool
+func NewNullBool(v bool) NullBool {
+	return NullBool{sql.NullBool{Bool: v, Valid: true}}
+}
+
+// NullTime represents a time column that may be null
+// It is marshaled to JSON as RFC 3339 time or null
+type NullTime struct {
+	sql.NullTime
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(n.Time)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullTime) UnmarshalJSON(b []byte) error {
+	n.Valid = string(b) != "null"
+	if !n.Valid {
+		n.Time = time.Time{}
+		return nil
+	}
+
+	return json.Unmarshal(b, &n.Time)
+}
+
+// NewNullTime creates a valid NullTime
+func NewNullTime(v time.Time) NullTime {
+	return NullTime{sql.NullTime{Time: v, Valid: true}}
+}
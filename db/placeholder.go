@@ -0,0 +1,170 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// placeholderMarker delimits a reference to a collected bind in unfinished sql
+const placeholderMarker = '\x00'
+
+// placeholders collects binds of a statement while it is built
+// Binds are referenced by markers which are replaced with adapter placeholders
+// once sql is complete, so binds follow the order of placeholders in sql
+// and numbered placeholders of embedded subqueries are renumbered
+type placeholders struct {
+	values []interface{}
+}
+
+// bind collects a value and returns its marker
+func (p *placeholders) bind(value interface{}) string {
+	p.values = append(p.values, value)
+	return string(placeholderMarker) + strconv.Itoa(len(p.values)-1) + string(placeholderMarker)
+}
+
+// embed returns sql of subquery with its placeholders replaced by markers of collected subquery binds
+func (p *placeholders) embed(adapter Adapter, sub Select) (string, error) {
+	sql := sub.Assemble()
+	if err := sub.Err(); err != nil {
+		return "", errors.Wrap(err, "Invalid subquery")
+	}
+
+	binds := sub.Binds()
+	if len(binds) == 0 {
+		return sql, nil
+	}
+
+	var err error
+	next := 0
+	sql = scanPlaceholders(adapter, sql, func(marker bool, index int) (string, bool) {
+		if index < 0 {
+			index = next
+			next++
+		}
+
+		if index >= len(binds) {
+			err = errors.Errorf("Subquery has no bind for placeholder %d", index+1)
+			return "", false
+		}
+
+		return p.bind(binds[index]), true
+	})
+
+	return sql, err
+}
+
+// resolve replaces markers and placeholders written in sql by adapter placeholders
+// own holds binds of placeholders written in sql, in their order
+func (p *placeholders) resolve(adapter Adapter, sql string, own []interface{}) (string, []interface{}) {
+	if adapter == nil || len(p.values) == 0 && len(own) == 0 {
+		return sql, own
+	}
+
+	binds := make([]interface{}, 0, len(p.values)+len(own))
+	next := 0
+	sql = scanPlaceholders(adapter, sql, func(marker bool, index int) (string, bool) {
+		values := own
+		if marker {
+			values = p.values
+		} else if index < 0 {
+			index = next
+			next++
+		}
+
+		if index >= len(values) {
+			return "", false
+		}
+
+		binds = append(binds, values[index])
+		return adapter.Placeholder(len(binds)), true
+	})
+
+	return sql, binds
+}
+
+// reset drops collected binds
+func (p *placeholders) reset() {
+	p.values = nil
+}
+
+// scanPlaceholders calls fn for every marker and placeholder outside of quoted literals and identifiers
+// Positional "?" placeholders are recognized for every adapter and numbered ones for adapters using them
+// fn receives index of bind referenced by marker or numbered placeholder, or -1 for positional placeholder
+// Text returned by fn replaces the placeholder, if fn returns false the placeholder is left as is
+func scanPlaceholders(adapter Adapter, sql string, fn func(marker bool, index int) (string, bool)) string {
+	if !strings.ContainsAny(sql, "?$'\"`"+string(placeholderMarker)) {
+		return sql
+	}
+
+	numbered := adapter.Placeholder(1) != "?"
+	backslashes := strings.Contains(adapter.Quote(`'`), `\`)
+
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if quote != 0 {
+			b.WriteByte(c)
+			if c == '\\' && quote == '\'' && backslashes && i+1 < len(sql) {
+				i++
+				b.WriteByte(sql[i])
+			} else if c == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		start, end, index, marker := i, i+1, -1, false
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(c)
+			continue
+
+		case c == placeholderMarker:
+			n := strings.IndexByte(sql[i+1:], placeholderMarker)
+			if n < 0 {
+				b.WriteByte(c)
+				continue
+			}
+
+			end = i + n + 2
+			index, _ = strconv.Atoi(sql[i+1 : end-1])
+			marker = true
+
+		case c == '?':
+
+		case c == '$' && numbered && i+1 < len(sql) && isDigit(sql[i+1]):
+			for end < len(sql) && isDigit(sql[end]) {
+				end++
+			}
+
+			index, _ = strconv.Atoi(sql[i+1 : end])
+			index--
+
+		default:
+			b.WriteByte(c)
+			continue
+		}
+
+		if text, ok := fn(marker, index); ok {
+			b.WriteString(text)
+		} else {
+			b.WriteString(sql[start:end])
+		}
+
+		i = end - 1
+	}
+
+	return b.String()
+}
+
+// Returns true if c is an ascii digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
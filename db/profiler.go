@@ -0,0 +1,324 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/log"
+)
+
+// Guards creation of request profiles
+var contextProfileMu sync.Mutex
+
+// Profiler records executed statements
+// Statements are aggregated per request context and the slow ones are logged
+type Profiler struct {
+	Options       *ProfilerConfig
+	mu            sync.Mutex
+	count         int64
+	slowCount     int64
+	totalDuration time.Duration
+}
+
+// QueryProfile is a record of single executed statement
+type QueryProfile struct {
+	Query        string
+	Binds        []interface{}
+	Table        string
+	Caller       string
+	Start        time.Time
+	Duration     time.Duration
+	RowsAffected int64
+	Err          error
+	Slow         bool
+	profiler     *Profiler
+	ctx          context.Context
+}
+
+// RequestProfile aggregates statements executed within one context
+type RequestProfile struct {
+	mu            sync.Mutex
+	Queries       []*QueryProfile
+	Count         int
+	SlowCount     int
+	Dropped       int
+	TotalDuration time.Duration
+}
+
+// Enable enables profiling
+func (p *Profiler) Enable() *Profiler {
+	p.Options.Enable = true
+	return p
+}
+
+// Disable disables profiling
+func (p *Profiler) Disable() *Profiler {
+	p.Options.Enable = false
+	return p
+}
+
+// IsEnabled returns true if profiling is enabled
+func (p *Profiler) IsEnabled() bool {
+	return p != nil && p.Options.Enable
+}
+
+// SlowThreshold returns the duration after which a statement is considered slow
+func (p *Profiler) SlowThreshold() time.Duration {
+	return time.Duration(p.Options.SlowThreshold) * time.Millisecond
+}
+
+// Start starts profiling of a statement
+// Returns nil if profiling is disabled
+func (p *Profiler) Start(ctx context.Context, query string, binds []interface{}, table string) *QueryProfile {
+	if !p.IsEnabled() {
+		return nil
+	}
+
+	return &QueryProfile{
+		Query:    query,
+		Binds:    binds,
+		Table:    table,
+		Caller:   caller(),
+		Start:    time.Now(),
+		profiler: p,
+		ctx:      ctx,
+	}
+}
+
+// Totals returns number of profiled statements, number of slow ones and their total duration
+func (p *Profiler) Totals() (int64, int64, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.count, p.slowCount, p.totalDuration
+}
+
+// Reset resets profiler totals
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.count = 0
+	p.slowCount = 0
+	p.totalDuration = 0
+}
+
+// Records a finished statement
+func (p *Profiler) record(qp *QueryProfile) {
+	p.mu.Lock()
+	p.count++
+	p.totalDuration += qp.Duration
+	if qp.Slow {
+		p.slowCount++
+	}
+	p.mu.Unlock()
+
+	if qp.ctx != nil {
+		StartContextProfile(qp.ctx).add(qp, p.Options.MaxQueries)
+	}
+
+	if log.Instance() == nil {
+		return
+	}
+
+	if qp.Slow {
+		log.Warning(fmt.Sprintf("[DB_Profiler] Slow query (%s): %s", qp.Duration, qp.Query), qp.extras())
+	} else if p.Options.LogQueries {
+		log.Debug(fmt.Sprintf("[DB_Profiler] Query (%s): %s", qp.Duration, qp.Query), qp.extras())
+	}
+}
+
+// End finishes profiling of the statement
+func (qp *QueryProfile) End(rowsAffected int64, err error) {
+	if qp == nil {
+		return
+	}
+
+	qp.Duration = time.Since(qp.Start)
+	qp.RowsAffected = rowsAffected
+	qp.Err = err
+	qp.Slow = qp.profiler.Options.SlowThreshold > 0 && qp.Duration >= qp.profiler.SlowThreshold()
+	qp.profiler.record(qp)
+}
+
+// EndResult finishes profiling of the statement taking rows affected from result
+func (qp *QueryProfile) EndResult(result sql.Result, err error) {
+	if qp == nil {
+		return
+	}
+
+	var affected int64
+	if err == nil && result != nil {
+		affected, _ = result.RowsAffected()
+	}
+
+	qp.End(affected, err)
+}
+
+// Returns log extras of the statement
+func (qp *QueryProfile) extras() map[string]string {
+	extras := map[string]string{
+		"duration": qp.Duration.String(),
+		"rows":     fmt.Sprint(qp.RowsAffected),
+	}
+
+	if qp.Table != "" {
+		extras["table"] = qp.Table
+	}
+
+	if qp.Caller != "" {
+		extras["caller"] = qp.Caller
+	}
+
+	if len(qp.Binds) > 0 {
+		extras["binds"] = fmt.Sprint(qp.Binds)
+	}
+
+	if qp.Err != nil {
+		extras["error"] = qp.Err.Error()
+	}
+
+	return extras
+}
+
+// Adds a statement to the request profile
+func (rp *RequestProfile) add(qp *QueryProfile, max int) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.Count++
+	rp.TotalDuration += qp.Duration
+	if qp.Slow {
+		rp.SlowCount++
+	}
+
+	if max > 0 && len(rp.Queries) >= max {
+		rp.Dropped++
+		return
+	}
+
+	rp.Queries = append(rp.Queries, qp)
+}
+
+// Totals returns number of statements, number of slow ones, number of not recorded ones and their total duration
+func (rp *RequestProfile) Totals() (int, int, int, time.Duration) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	return rp.Count, rp.SlowCount, rp.Dropped, rp.TotalDuration
+}
+
+// Snapshot returns a copy of recorded statements
+func (rp *RequestProfile) Snapshot() []*QueryProfile {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	queries := make([]*QueryProfile, len(rp.Queries))
+	copy(queries, rp.Queries)
+	return queries
+}
+
+// Slowest returns the slowest recorded statement
+func (rp *RequestProfile) Slowest() *QueryProfile {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	var slowest *QueryProfile
+	for _, qp := range rp.Queries {
+		if slowest == nil || qp.Duration > slowest.Duration {
+			slowest = qp
+		}
+	}
+
+	return slowest
+}
+
+// StartContextProfile returns statements profile of the context creating it if there is none
+// Statements of a request may run concurrently, so profile is created under a lock
+func StartContextProfile(ctx context.Context) *RequestProfile {
+	contextProfileMu.Lock()
+	defer contextProfileMu.Unlock()
+
+	rp := ContextProfile(ctx)
+	if rp == nil {
+		rp = &RequestProfile{Queries: make([]*QueryProfile, 0)}
+		ctx.SetValue(contextKeyProfile, rp)
+	}
+
+	return rp
+}
+
+// ContextProfile returns statements profile of the context or nil if nothing was profiled
+func ContextProfile(ctx context.Context) *RequestProfile {
+	if ctx == nil {
+		return nil
+	}
+
+	if rp, ok := ctx.Value(contextKeyProfile).(*RequestProfile); ok {
+		return rp
+	}
+
+	return nil
+}
+
+// Returns the first caller outside of db package
+func caller() string {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.Function, "/wsf/db.") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// StatementTable returns table name the statement operates on
+func StatementTable(stmt interface{}) string {
+	switch v := stmt.(type) {
+	case *DefaultSelect:
+		if len(v.Parts.From) > 0 && !v.Parts.From[0].Derived {
+			return v.Parts.From[0].TableName
+		}
+
+	case *DefaultInsert:
+		return v.Table
+
+	case *DefaultUpdate:
+		return v.Name
+
+	case *DefaultDelete:
+		return v.Name
+	}
+
+	return ""
+}
+
+// NewProfiler creates a new profiler
+func NewProfiler(options config.Config) (*Profiler, error) {
+	cfg := &ProfilerConfig{}
+	cfg.Defaults()
+	if err := cfg.Populate(options); err != nil {
+		return nil, err
+	}
+
+	return NewProfilerFromConfig(cfg), nil
+}
+
+// NewProfilerFromConfig creates a new profiler from given config
+func NewProfilerFromConfig(cfg *ProfilerConfig) *Profiler {
+	return &Profiler{
+		Options: cfg,
+	}
+}
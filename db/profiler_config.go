@@ -0,0 +1,40 @@
+package db
+
+import (
+	"github.com/noxyicm/wsf/config"
+)
+
+// ProfilerConfig defines set of profiler variables
+type ProfilerConfig struct {
+	Enable        bool
+	SlowThreshold int
+	LogQueries    bool
+	MaxQueries    int
+}
+
+// Populate populates Config values using given Config source
+func (c *ProfilerConfig) Populate(cfg config.Config) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *ProfilerConfig) Defaults() error {
+	c.Enable = false
+	c.SlowThreshold = 1000
+	c.LogQueries = false
+	c.MaxQueries = 500
+	return nil
+}
+
+// Valid validates the configuration
+func (c *ProfilerConfig) Valid() error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/noxyicm/wsf/cache"
+	"github.com/noxyicm/wsf/context"
+)
+
+var (
+	defaultQueryCache cache.Interface
+
+	regexpCacheTagSymbols = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+func init() {
+	gob.Register(time.Time{})
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+}
+
+// SetDefaultQueryCache sets the default cache for query results
+func SetDefaultQueryCache(queryCache interface{}) {
+	defaultQueryCache, _ = SetupMetadataCache(queryCache)
+}
+
+// DefaultQueryCache returns the default cache for query results
+func DefaultQueryCache() cache.Interface {
+	return defaultQueryCache
+}
+
+// TableCacheTag returns a cache tag of query results read from table
+func TableCacheTag(table string) string {
+	return "db_table_" + regexpCacheTagSymbols.ReplaceAllString(table, "_")
+}
+
+// InvalidateQueryCache removes cached query results read from tables
+func InvalidateQueryCache(queryCache cache.Interface, tables ...string) bool {
+	if queryCache == nil || len(tables) == 0 {
+		return false
+	}
+
+	tags := make([]string, len(tables))
+	for i, table := range tables {
+		tags[i] = TableCacheTag(table)
+	}
+
+	return queryCache.Clear(cache.CleaningModeMatchingAnyTag, tags)
+}
+
+// Invalidates query cache of tables changed with context
+// Inside a transaction invalidation is deferred until it commits,
+// so results read by other connections meanwhile are dropped as well
+func invalidateContextQueryCache(ctx context.Context, adp Adapter, queryCache cache.Interface, tables ...string) {
+	if queryCache == nil {
+		return
+	}
+
+	if hooks := contextCommitHooks(ctx, adp); hooks != nil {
+		hooks.add(func() {
+			InvalidateQueryCache(queryCache, tables...)
+		})
+		return
+	}
+
+	InvalidateQueryCache(queryCache, tables...)
+}
+
+// Runs a query reading and storing results in query cache if select is marked as cached
+// Cache is bypassed inside transaction
+func cachedQuery(ctx context.Context, queryCache cache.Interface, adp Adapter, dbs Select) ([]map[string]interface{}, error) {
+	if queryCache == nil || !dbs.IsCached() || !queryCache.Enabled() || activeTransaction(ctx, adp) != nil {
+		return contextQuery(ctx, adp, dbs)
+	}
+
+	id := queryCacheID(adp, dbs, "rowset")
+	var data []map[string]interface{}
+	if raw, ok := queryCache.Load(id, true); ok {
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&data); err == nil {
+			return data, nil
+		}
+	}
+
+	data, err := contextQuery(ctx, adp, dbs)
+	if err != nil {
+		return data, err
+	}
+
+	saveQueryResult(queryCache, id, dbs, data)
+	return data, nil
+}
+
+// Runs a single row query reading and storing result in query cache if select is marked as cached
+// Cache is bypassed inside transaction
+func cachedQueryRow(ctx context.Context, queryCache cache.Interface, adp Adapter, dbs Select) (map[string]interface{}, error) {
+	if queryCache == nil || !dbs.IsCached() || !queryCache.Enabled() || activeTransaction(ctx, adp) != nil {
+		return contextQueryRow(ctx, adp, dbs)
+	}
+
+	id := queryCacheID(adp, dbs, "row")
+	var data map[string]interface{}
+	if raw, ok := queryCache.Load(id, true); ok {
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&data); err == nil {
+			return data, nil
+		}
+	}
+
+	data, err := contextQueryRow(ctx, adp, dbs)
+	if err != nil {
+		return data, err
+	}
+
+	saveQueryResult(queryCache, id, dbs, data)
+	return data, nil
+}
+
+// Stores query result tagged by select tags and tables it reads from
+func saveQueryResult(queryCache cache.Interface, id string, dbs Select, data interface{}) {
+	buf := bytes.Buffer{}
+	if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+		return
+	}
+
+	tags := append([]string{}, dbs.CacheTags()...)
+	for _, table := range dbs.Tables() {
+		tags = append(tags, TableCacheTag(table))
+	}
+
+	queryCache.Save(buf.Bytes(), id, tags, dbs.CacheLifetime())
+}
+
+// Returns cache id of query made from adapter dsn, assembled sql and binds
+func queryCacheID(adp Adapter, dbs Select, kind string) string {
+	hashed := md5.New()
+	hashed.Write([]byte(adp.FormatDSN() + "\n" + kind + "\n" + dbs.Assemble() + "\n" + fmt.Sprintf("%#v", dbs.Binds())))
+	return "db_query_" + hex.EncodeToString(hashed.Sum(nil))
+}
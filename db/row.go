@@ -2,14 +2,16 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
+	"github.com/noxyicm/wsf/context"
 	"github.com/noxyicm/wsf/errors"
 	"github.com/noxyicm/wsf/registry"
 
 	"github.com/go-sql-driver/mysql"
-	"github.com/mitchellh/mapstructure"
 )
 
 const (
@@ -35,22 +37,37 @@ type Row interface {
 	GetFloat(key string) float64
 	GetBool(key string) bool
 	GetTime(key string) time.Time
+	GetAll() map[string]interface{}
 	Unmarshal(output interface{}) error
 	Populate(data map[string]interface{})
+	PopulateStruct(input interface{}) error
 	Prepare(rows *sql.Rows) error
 	SetTable(table Table) error
 	Table() Table
 	IsEmpty() bool
+	IsModified() bool
+	PrimaryKey() map[string]interface{}
+	Save(ctx context.Context) error
+	Refresh(ctx context.Context) error
+	Delete(ctx context.Context) error
+	FindParentRow(ctx context.Context, parentTable Table, ruleKey string, slct Select) (Row, error)
+	FindDependentRowset(ctx context.Context, dependentTable Table, ruleKey string, slct Select) (Rowset, error)
+	FindManyToManyRowset(ctx context.Context, matchTable Table, intersectionTable Table, callerRefRule string, matchRefRule string, slct Select) (Rowset, error)
+	SetRelated(key string, related interface{})
+	Related(key string) (interface{}, bool)
 }
 
 // DefaultRow holds data and operates over row
 type DefaultRow struct {
-	Options   *RowConfig
-	Data      map[string]interface{}
-	Tbl       Table
-	Connected bool
-	Stored    bool
-	ReadOnly  bool
+	Options        *RowConfig
+	Data           map[string]interface{}
+	CleanData      map[string]interface{}
+	ModifiedFields map[string]bool
+	Tbl            Table
+	Connected      bool
+	Stored         bool
+	ReadOnly       bool
+	related        map[string]interface{}
 }
 
 // Setup the object
@@ -61,6 +78,7 @@ func (r *DefaultRow) Setup() error {
 // Set value v to row data with key k
 func (r *DefaultRow) Set(k string, v interface{}) {
 	r.Data[k] = v
+	r.ModifiedFields[k] = true
 }
 
 // Get returns a value by its key
@@ -149,22 +167,40 @@ func (r *DefaultRow) GetAll() map[string]interface{} {
 	return r.Data
 }
 
-// Unmarshal unmarshals data into struct
+// Unmarshal unmarshals data into struct using db tags
+// Untagged fields are matched by snake cased name, or by lower cased name as a fallback
 func (r *DefaultRow) Unmarshal(output interface{}) error {
-	if err := mapstructure.Decode(r.Data, output); err != nil {
-		return err
-	}
-
-	return nil
+	return MapToStruct(r.Data, output)
 }
 
 // Populate the row object with provided data
+// Data of a stored row is considered clean, otherwise it is marked as modified
 func (r *DefaultRow) Populate(data map[string]interface{}) {
 	for key, value := range data {
 		r.Data[key] = value
+		if r.Stored {
+			r.CleanData[key] = value
+			delete(r.ModifiedFields, key)
+		} else {
+			r.ModifiedFields[key] = true
+		}
 	}
 }
 
+// PopulateStruct sets row columns from struct fields mapped by db tags
+func (r *DefaultRow) PopulateStruct(input interface{}) error {
+	data, err := StructToMap(input)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range data {
+		r.Set(key, value)
+	}
+
+	return nil
+}
+
 // Prepare initializes row
 func (r *DefaultRow) Prepare(rows *sql.Rows) (err error) {
 	if r.Tbl == nil {
@@ -202,6 +238,8 @@ func (r *DefaultRow) Prepare(rows *sql.Rows) (err error) {
 		r.Data[columns[i].Name()] = r.Tbl.GetAdapter().Dereference(scanArgs[i])
 	}
 
+	r.Stored = true
+	r.clean()
 	return nil
 }
 
@@ -221,12 +259,460 @@ func (r *DefaultRow) IsEmpty() bool {
 	return len(r.Data) == 0
 }
 
+// IsModified returns true if row has unsaved changes
+func (r *DefaultRow) IsModified() bool {
+	return len(r.modified()) > 0
+}
+
+// PrimaryKey returns primary key values of the row
+func (r *DefaultRow) PrimaryKey() map[string]interface{} {
+	return r.primaryKey(false)
+}
+
+// Save inserts a new row or updates modified columns of a stored one
+func (r *DefaultRow) Save(ctx context.Context) error {
+	if r.Tbl == nil {
+		return errors.New("Row is not connected to a table")
+	}
+
+	if r.ReadOnly {
+		return errors.New("This row has been marked read-only")
+	}
+
+	if !r.Stored {
+		return r.insert(ctx)
+	}
+
+	return r.update(ctx)
+}
+
+// Refresh reloads row data from the table
+func (r *DefaultRow) Refresh(ctx context.Context) error {
+	if r.Tbl == nil {
+		return errors.New("Row is not connected to a table")
+	}
+
+	cond, err := r.primaryKeyCondition(r.Stored)
+	if err != nil {
+		return err
+	}
+
+	slct := r.Tbl.Select(true)
+	for c, v := range cond {
+		slct.Where(c, v)
+	}
+	slct.Limit(1, 0)
+
+	data, err := contextQueryRow(ctx, r.Tbl.GetAdapter(), slct)
+	if err != nil {
+		return errors.Wrap(err, "Unable to refresh row")
+	}
+
+	if len(data) == 0 {
+		return errors.New("Cannot refresh row as parent is missing")
+	}
+
+	r.Data = data
+	r.Stored = true
+	r.clean()
+	return nil
+}
+
+// Delete removes the row from the table
+func (r *DefaultRow) Delete(ctx context.Context) error {
+	if r.Tbl == nil {
+		return errors.New("Row is not connected to a table")
+	}
+
+	if r.ReadOnly {
+		return errors.New("This row has been marked read-only")
+	}
+
+	if !r.Stored {
+		return errors.New("Cannot delete a row that is not stored")
+	}
+
+	cond, err := r.primaryKeyCondition(true)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Tbl.Delete(ctx, cond); err != nil {
+		return err
+	}
+
+	r.Stored = false
+	r.CleanData = make(map[string]interface{})
+	r.ModifiedFields = make(map[string]bool)
+	for key := range r.Data {
+		r.ModifiedFields[key] = true
+	}
+
+	return nil
+}
+
+// FindParentRow returns the parent row referenced by this row
+// or nil if reference columns of the row are empty
+func (r *DefaultRow) FindParentRow(ctx context.Context, parentTable Table, ruleKey string, slct Select) (Row, error) {
+	if r.Tbl == nil {
+		return nil, errors.New("Row is not connected to a table")
+	}
+
+	parentName := parentTable.Info().Name
+	ref, err := r.Tbl.Reference(parentName, ruleKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if slct == nil {
+		if related, ok := r.Related(parentRelationKey(parentName, ruleKey)); ok {
+			row, _ := related.(Row)
+			return row, nil
+		}
+
+		slct = parentTable.Select(true)
+	}
+
+	for i, col := range ref.Columns {
+		value := r.Get(col)
+		if value == nil {
+			return nil, nil
+		}
+
+		slct.Where(parentName+"."+ref.RefColumns[i]+" = ?", value)
+	}
+
+	return parentTable.FetchRow(ctx, slct)
+}
+
+// FindDependentRowset returns rows of dependent table referencing this row
+func (r *DefaultRow) FindDependentRowset(ctx context.Context, dependentTable Table, ruleKey string, slct Select) (Rowset, error) {
+	if r.Tbl == nil {
+		return nil, errors.New("Row is not connected to a table")
+	}
+
+	dependentName := dependentTable.Info().Name
+	ref, err := dependentTable.Reference(r.Tbl.Info().Name, ruleKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if slct == nil {
+		if related, ok := r.Related(dependentRelationKey(dependentName, ruleKey)); ok {
+			if rowset, ok := related.(Rowset); ok {
+				return rowset, nil
+			}
+		}
+
+		slct = dependentTable.Select(true)
+	}
+
+	for i, col := range ref.Columns {
+		slct.Where(dependentName+"."+col+" = ?", r.Get(ref.RefColumns[i]))
+	}
+
+	return dependentTable.FetchAll(ctx, slct)
+}
+
+// FindManyToManyRowset returns rows of match table related to this row through intersection table
+func (r *DefaultRow) FindManyToManyRowset(ctx context.Context, matchTable Table, intersectionTable Table, callerRefRule string, matchRefRule string, slct Select) (Rowset, error) {
+	if r.Tbl == nil {
+		return nil, errors.New("Row is not connected to a table")
+	}
+
+	matchInfo := matchTable.Info()
+	intersectionInfo := intersectionTable.Info()
+
+	callerRef, err := intersectionTable.Reference(r.Tbl.Info().Name, callerRefRule)
+	if err != nil {
+		return nil, err
+	}
+
+	matchRef, err := intersectionTable.Reference(matchInfo.Name, matchRefRule)
+	if err != nil {
+		return nil, err
+	}
+
+	if slct == nil {
+		slct = matchTable.Select(false)
+	}
+
+	interName := intersectionInfo.Name
+	if intersectionInfo.Schema != "" {
+		interName = intersectionInfo.Schema + "." + interName
+	}
+
+	matchName := matchInfo.Name
+	if matchInfo.Schema != "" {
+		matchName = matchInfo.Schema + "." + matchName
+	}
+
+	joinCond := make([]string, len(matchRef.Columns))
+	for i, col := range matchRef.Columns {
+		joinCond[i] = "i." + col + " = m." + matchRef.RefColumns[i]
+	}
+
+	// Select given by caller may already define its FROM part
+	if len(slct.Tables()) == 0 {
+		slct.FromAs(interName, "i", nil)
+	}
+
+	slct.JoinInnerAs(matchName, "m", strings.Join(joinCond, " AND "), SQLWildcard)
+	for i, col := range callerRef.Columns {
+		slct.Where("i."+col+" = ?", r.Get(callerRef.RefColumns[i]))
+	}
+
+	return matchTable.FetchAll(ctx, slct)
+}
+
+// SetRelated stores eager loaded related row or rowset
+func (r *DefaultRow) SetRelated(key string, related interface{}) {
+	if r.related == nil {
+		r.related = make(map[string]interface{})
+	}
+
+	r.related[key] = related
+}
+
+// Related returns eager loaded related row or rowset
+func (r *DefaultRow) Related(key string) (interface{}, bool) {
+	related, ok := r.related[key]
+	return related, ok
+}
+
+// Inserts a new row into the table
+func (r *DefaultRow) insert(ctx context.Context) error {
+	data := make(map[string]interface{})
+	for key, value := range r.Data {
+		if r.ModifiedFields[key] || value != nil {
+			data[key] = value
+		}
+	}
+
+	id, err := r.Tbl.Insert(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range data {
+		r.Data[key] = value
+	}
+
+	// Identity column left empty is generated by database,
+	// other columns of a compound key are never generated
+	primary := r.Tbl.Info().Primary
+	for _, column := range primary {
+		if len(primary) > 1 && !r.Tbl.IsIdentity(column) {
+			continue
+		}
+
+		if value, ok := r.Data[column]; !ok || value == nil || value == `` {
+			r.Data[column] = id
+		}
+	}
+
+	r.Stored = true
+	r.clean()
+	return r.Refresh(ctx)
+}
+
+// Updates modified columns of stored row
+func (r *DefaultRow) update(ctx context.Context) error {
+	diff := r.modified()
+	if len(diff) == 0 {
+		return nil
+	}
+
+	cond, err := r.primaryKeyCondition(true)
+	if err != nil {
+		return err
+	}
+
+	oldPrimaryKey := r.primaryKey(true)
+	newPrimaryKey := r.primaryKey(false)
+	pkChanged := !reflect.DeepEqual(oldPrimaryKey, newPrimaryKey)
+
+	if pkChanged {
+		for ruleKey := range r.Tbl.GetDependentTables() {
+			depTable, err := r.Tbl.DependentTable(ruleKey)
+			if err != nil {
+				return err
+			}
+
+			if _, err := depTable.CascadeUpdate(ctx, r.Tbl.Info().Name, oldPrimaryKey, newPrimaryKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := r.Tbl.Update(ctx, diff, cond); err != nil {
+		return err
+	}
+
+	r.clean()
+	return nil
+}
+
+// Returns columns which values differ from clean data
+func (r *DefaultRow) modified() map[string]interface{} {
+	diff := make(map[string]interface{})
+	for key := range r.ModifiedFields {
+		value := r.Data[key]
+		if clean, ok := r.CleanData[key]; !ok || !reflect.DeepEqual(clean, value) {
+			diff[key] = value
+		}
+	}
+
+	return diff
+}
+
+// Marks current data as clean
+func (r *DefaultRow) clean() {
+	r.CleanData = make(map[string]interface{}, len(r.Data))
+	for key, value := range r.Data {
+		r.CleanData[key] = value
+	}
+
+	r.ModifiedFields = make(map[string]bool)
+}
+
+// Returns primary key values from current or clean data
+func (r *DefaultRow) primaryKey(useClean bool) map[string]interface{} {
+	pk := make(map[string]interface{})
+	if r.Tbl == nil {
+		return pk
+	}
+
+	data := r.Data
+	if useClean {
+		data = r.CleanData
+	}
+
+	for _, col := range r.Tbl.Info().Primary {
+		pk[col] = data[col]
+	}
+
+	return pk
+}
+
+// Returns a where condition map matching primary key of the row
+func (r *DefaultRow) primaryKeyCondition(useClean bool) (map[string]interface{}, error) {
+	pk := r.primaryKey(useClean)
+	if len(pk) == 0 {
+		return nil, errors.Errorf("A table must have a primary key, but none was found for table '%s'", r.Tbl.Info().Name)
+	}
+
+	cond := make(map[string]interface{}, len(pk))
+	for col, value := range pk {
+		if value == nil {
+			return nil, errors.Errorf("Primary key column '%s' of the row is empty", col)
+		}
+
+		cond[r.Tbl.GetAdapter().QuoteIdentifier(col, true)+" = ?"] = value
+	}
+
+	return cond, nil
+}
+
+// Returns a key of eager loaded parent row
+func parentRelationKey(table string, ruleKey string) string {
+	return "parent:" + table + ":" + ruleKey
+}
+
+// Returns a key of eager loaded dependent rowset
+func dependentRelationKey(table string, ruleKey string) string {
+	return "dependent:" + table + ":" + ruleKey
+}
+
+// Returns a string representation of reference columns values
+func referenceValuesKey(row Row, cols []string) (string, []interface{}, bool) {
+	values := make([]interface{}, len(cols))
+	parts := make([]string, len(cols))
+	for i, col := range cols {
+		values[i] = row.Get(col)
+		if values[i] == nil {
+			return "", nil, false
+		}
+
+		parts[i] = referenceValueKey(values[i])
+	}
+
+	return strings.Join(parts, "\x00"), values, true
+}
+
+// Returns a string representation of reference column value
+// Values of the same key read as different types by drivers have the same representation
+func referenceValueKey(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+
+	case []byte:
+		return string(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// Adds where conditions matching any of reference values
+// Single column references are matched by IN list
+func referenceWhere(slct Select, adp Adapter, table string, cols []string, values [][]interface{}) {
+	if len(cols) == 1 {
+		list := make([]string, len(values))
+		for i, value := range values {
+			if b, ok := value[0].([]byte); ok {
+				list[i] = adp.Quote(string(b))
+			} else {
+				list[i] = adp.Quote(value[0])
+			}
+		}
+
+		slct.Where(table+"."+cols[0]+" IN ?", NewExpr("("+strings.Join(list, ", ")+")"))
+		return
+	}
+
+	cond := make([]string, len(cols))
+	for i, col := range cols {
+		cond[i] = table + "." + col + " = ?"
+	}
+
+	for _, value := range values {
+		term := strings.Join(cond, " "+SQLAnd+" ")
+		for _, v := range value {
+			term = adp.QuoteInto(term, v, 1)
+		}
+
+		slct.OrWhere(term, nil)
+	}
+}
+
 // NewDefaultRow creates default row
 func NewDefaultRow(options *RowConfig) (Row, error) {
 	r := &DefaultRow{
-		Options:   options,
-		Data:      make(map[string]interface{}),
-		Connected: false,
+		Options:        options,
+		Data:           make(map[string]interface{}),
+		CleanData:      make(map[string]interface{}),
+		ModifiedFields: make(map[string]bool),
+		Connected:      false,
+		Stored:         options.Stored,
+		ReadOnly:       options.ReadOnly,
+	}
+
+	for key, value := range options.Data {
+		r.Data[key] = value
+	}
+
+	if r.Stored {
+		r.clean()
 	}
 
 	if options.Table != "" {
@@ -247,9 +733,11 @@ func NewDefaultRow(options *RowConfig) (Row, error) {
 // EmptyDefaultRow creates empty, not settuped default row
 func EmptyDefaultRow(options *RowConfig) *DefaultRow {
 	return &DefaultRow{
-		Options:   options,
-		Data:      make(map[string]interface{}),
-		Connected: false,
+		Options:        options,
+		Data:           make(map[string]interface{}),
+		CleanData:      make(map[string]interface{}),
+		ModifiedFields: make(map[string]bool),
+		Connected:      false,
 	}
 }
 
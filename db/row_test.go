@@ -0,0 +1,33 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestReferenceValueKeyNormalizesTypes(t *testing.T) {
+	keys := []string{
+		referenceValueKey(int64(42)),
+		referenceValueKey(42),
+		referenceValueKey(uint32(42)),
+		referenceValueKey([]byte("42")),
+		referenceValueKey("42"),
+	}
+
+	for i, key := range keys {
+		if key != "42" {
+			t.Errorf("key %d = %q, want \"42\"", i, key)
+		}
+	}
+}
+
+func TestReferenceWhereSingleColumnUsesIn(t *testing.T) {
+	adp := newTestAdapter(t, NewMySQLAdapter)
+
+	slct := newTestSelect(adp).From("users", "*")
+	referenceWhere(slct, adp, "users", []string{"id"}, [][]interface{}{{int64(1)}, {[]byte("2")}, {"x'y"}})
+
+	want := "SELECT `users`.* FROM `users` WHERE (`users`.`id` IN (1, '2', 'x\\'y'))"
+	if sql := slct.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"sync"
+	"github.com/noxyicm/wsf/context"
 	"github.com/noxyicm/wsf/errors"
 )
 
@@ -36,6 +37,9 @@ type Rowset interface {
 	Table() Table
 	Count() int
 	IsEmpty() bool
+	Unmarshal(output interface{}) error
+	EagerLoadParent(ctx context.Context, parentTable Table, ruleKey string) error
+	EagerLoadDependent(ctx context.Context, dependentTable Table, ruleKey string) error
 }
 
 // DefaultRowset holds and operates over rows
@@ -124,6 +128,10 @@ func (r *DefaultRowset) PopulateMap(data []map[string]interface{}) error {
 			return errors.Wrap(err, "Database rowset populate Error")
 		}
 
+		if r.Tbl != nil {
+			row.SetTable(r.Tbl)
+		}
+
 		row.Populate(rowdata)
 		r.Data = append(r.Data, row)
 	}
@@ -228,6 +236,145 @@ func (r *DefaultRowset) IsEmpty() bool {
 	return r.Cnt == 0
 }
 
+// Unmarshal fills slice pointed by output with structs made from rows using db tags
+func (r *DefaultRowset) Unmarshal(output interface{}) error {
+	rows := make([]map[string]interface{}, len(r.Data))
+	for i, row := range r.Data {
+		rows[i] = row.GetAll()
+	}
+
+	return MapToSlice(rows, output)
+}
+
+// EagerLoadParent loads parent rows of all rows with a single query
+// Loaded rows are returned by Row.FindParentRow called without select
+func (r *DefaultRowset) EagerLoadParent(ctx context.Context, parentTable Table, ruleKey string) error {
+	if r.Tbl == nil {
+		return errors.New("Database rowset eager load Error: Reference table must be set")
+	}
+
+	parentName := parentTable.Info().Name
+	ref, err := r.Tbl.Reference(parentName, ruleKey)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool)
+	values := make([][]interface{}, 0)
+	for _, row := range r.Data {
+		if key, value, ok := referenceValuesKey(row, ref.Columns); ok && !keys[key] {
+			keys[key] = true
+			values = append(values, value)
+		}
+	}
+
+	parents := make(map[string]Row)
+	if len(values) > 0 {
+		slct := parentTable.Select(true)
+		referenceWhere(slct, parentTable.GetAdapter(), parentName, ref.RefColumns, values)
+
+		rowset, err := parentTable.FetchAll(ctx, slct)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < rowset.Count(); i++ {
+			parent := rowset.GetOffset(i)
+			if key, _, ok := referenceValuesKey(parent, ref.RefColumns); ok {
+				parents[key] = parent
+			}
+		}
+	}
+
+	for _, row := range r.Data {
+		key, _, _ := referenceValuesKey(row, ref.Columns)
+		if parent, ok := parents[key]; ok {
+			row.SetRelated(parentRelationKey(parentName, ruleKey), parent)
+		} else {
+			row.SetRelated(parentRelationKey(parentName, ruleKey), nil)
+		}
+	}
+
+	return nil
+}
+
+// EagerLoadDependent loads dependent rows of all rows with a single query
+// Loaded rowsets are returned by Row.FindDependentRowset called without select
+func (r *DefaultRowset) EagerLoadDependent(ctx context.Context, dependentTable Table, ruleKey string) error {
+	if r.Tbl == nil {
+		return errors.New("Database rowset eager load Error: Reference table must be set")
+	}
+
+	dependentName := dependentTable.Info().Name
+	ref, err := dependentTable.Reference(r.Tbl.Info().Name, ruleKey)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool)
+	values := make([][]interface{}, 0)
+	for _, row := range r.Data {
+		if key, value, ok := referenceValuesKey(row, ref.RefColumns); ok && !keys[key] {
+			keys[key] = true
+			values = append(values, value)
+		}
+	}
+
+	dependents := make(map[string][]Row)
+	if len(values) > 0 {
+		slct := dependentTable.Select(true)
+		referenceWhere(slct, dependentTable.GetAdapter(), dependentName, ref.Columns, values)
+
+		rowset, err := dependentTable.FetchAll(ctx, slct)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < rowset.Count(); i++ {
+			dependent := rowset.GetOffset(i)
+			if key, _, ok := referenceValuesKey(dependent, ref.Columns); ok {
+				dependents[key] = append(dependents[key], dependent)
+			}
+		}
+	}
+
+	for _, row := range r.Data {
+		key, _, _ := referenceValuesKey(row, ref.RefColumns)
+		rowset, err := newTableRowset(dependentTable, dependents[key])
+		if err != nil {
+			return err
+		}
+
+		row.SetRelated(dependentRelationKey(dependentName, ruleKey), rowset)
+	}
+
+	return nil
+}
+
+// Creates a rowset of table type populated with rows
+func newTableRowset(table Table, rows []Row) (Rowset, error) {
+	info := table.Info()
+	rstConfig := &RowsetConfig{
+		Type:   info.RowsetType,
+		Table:  info.Name,
+		Stored: true,
+		Row: &RowConfig{
+			Type:   info.RowType,
+			Table:  info.Name,
+			Stored: true,
+		},
+	}
+
+	rst, err := NewRowset(rstConfig.Type, rstConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Database rowset eager load Error")
+	}
+
+	rst.SetTable(table)
+	rst.Populate(rows)
+	return rst, nil
+}
+
 // NewDefaultRowset creates default rowset
 func NewDefaultRowset(options *RowsetConfig) (Rowset, error) {
 	return &DefaultRowset{
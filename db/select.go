@@ -101,23 +101,36 @@ func init() {
 // Select is a select interface
 type Select interface {
 	SetAdapter(adapter Adapter) error
-	From(name string, cols interface{}) Select
-	FromAs(name string, alias string, cols interface{}) Select
+	Distinct(flag bool) Select
+	From(name interface{}, cols interface{}) Select
+	FromAs(name interface{}, alias string, cols interface{}) Select
 	FromSchema(name string, cols interface{}, schema string) Select
 	FromSchemaAs(name string, alias string, cols interface{}, schema string) Select
 	Columns(cols interface{}, correlationName string) Select
 	Union(sql interface{}, typ string) Select
-	Join(name string, cond string, cols interface{}) Select
-	JoinAs(name string, alias string, cond string, cols interface{}) Select
-	JoinInner(name string, cond string, cols interface{}) Select
-	JoinInnerAs(name string, alias string, cond string, cols interface{}) Select
-	JoinLeft(name string, cond string, cols interface{}) Select
-	JoinLeftAs(name string, alias string, cond string, cols interface{}) Select
+	Join(name interface{}, cond string, cols interface{}) Select
+	JoinAs(name interface{}, alias string, cond string, cols interface{}) Select
+	JoinInner(name interface{}, cond string, cols interface{}) Select
+	JoinInnerAs(name interface{}, alias string, cond string, cols interface{}) Select
+	JoinLeft(name interface{}, cond string, cols interface{}) Select
+	JoinLeftAs(name interface{}, alias string, cond string, cols interface{}) Select
 	Where(cond string, value interface{}) Select
 	OrWhere(cond string, value interface{}) Select
+	Group(spec interface{}) Select
+	Having(cond string, value interface{}) Select
+	OrHaving(cond string, value interface{}) Select
+	ForUpdate(flag bool) Select
 	Limit(count int, offset int) Select
 	Order(order interface{}) Select
+	AddBind(name string, value interface{}) Select
 	Binds() []interface{}
+	Cache(lifetime int64, tags ...string) Select
+	NoCache() Select
+	IsCached() bool
+	CacheLifetime() int64
+	CacheTags() []string
+	Tables() []string
+	Clone() Select
 	Err() error
 	Reset(string) Select
 	Clear() Select
@@ -168,6 +181,13 @@ type DefaultSelect struct {
 	Bind    map[string]interface{}
 	Parts   *selectParts
 	Errors  []error
+
+	bindOrder     []string
+	subBinds      placeholders
+	subTables     []string
+	cached        bool
+	cacheLifetime int64
+	cacheTags     []string
 }
 
 // SelectParts is a select object parts holder
@@ -200,6 +220,7 @@ type selectFrom struct {
 	TableName     string
 	JoinCondition string
 	Alias         string
+	Derived       bool
 }
 
 // SelectUnion is a select object union representation
@@ -227,12 +248,12 @@ func (s *DefaultSelect) Distinct(flag bool) Select {
 }
 
 // From adds a FROM table and optional columns to the query
-func (s *DefaultSelect) From(name string, cols interface{}) Select {
+func (s *DefaultSelect) From(name interface{}, cols interface{}) Select {
 	return s.prepareJoin(From, name, "", "", cols, "")
 }
 
 // FromAs adds a FROM table and optional columns to the query
-func (s *DefaultSelect) FromAs(name string, alias string, cols interface{}) Select {
+func (s *DefaultSelect) FromAs(name interface{}, alias string, cols interface{}) Select {
 	return s.prepareJoin(From, name, alias, "", cols, "")
 }
 
@@ -279,11 +300,11 @@ func (s *DefaultSelect) Union(sql interface{}, typ string) Select {
 	switch t := sql.(type) {
 	case []Select:
 		for _, target := range sql.([]Select) {
-			s.Parts.Union = append(s.Parts.Union, &selectUnion{Target: target.Assemble(), Type: typ})
+			s.Parts.Union = append(s.Parts.Union, &selectUnion{Target: s.subquery(target), Type: typ})
 		}
 
 	case Select:
-		s.Parts.Union = append(s.Parts.Union, &selectUnion{Target: sql.(Select).Assemble(), Type: typ})
+		s.Parts.Union = append(s.Parts.Union, &selectUnion{Target: s.subquery(sql.(Select)), Type: typ})
 
 	case []string:
 		for _, target := range sql.([]string) {
@@ -302,44 +323,107 @@ func (s *DefaultSelect) Union(sql interface{}, typ string) Select {
 }
 
 // Join adds a JOIN table and columns to the query
-func (s *DefaultSelect) Join(name string, cond string, cols interface{}) Select {
+func (s *DefaultSelect) Join(name interface{}, cond string, cols interface{}) Select {
 	return s.JoinInner(name, cond, cols)
 }
 
 // JoinAs adds a JOIN table and columns to the query
-func (s *DefaultSelect) JoinAs(name string, alias string, cond string, cols interface{}) Select {
+func (s *DefaultSelect) JoinAs(name interface{}, alias string, cond string, cols interface{}) Select {
 	return s.JoinInnerAs(name, alias, cond, cols)
 }
 
 // JoinInner add an INNER JOIN table and colums to the query
-func (s *DefaultSelect) JoinInner(name string, cond string, cols interface{}) Select {
+func (s *DefaultSelect) JoinInner(name interface{}, cond string, cols interface{}) Select {
 	return s.prepareJoin(InnerJoin, name, "", cond, cols, "")
 }
 
 // JoinInnerAs add an INNER JOIN table and colums to the query
-func (s *DefaultSelect) JoinInnerAs(name string, alias string, cond string, cols interface{}) Select {
+func (s *DefaultSelect) JoinInnerAs(name interface{}, alias string, cond string, cols interface{}) Select {
 	return s.prepareJoin(InnerJoin, name, alias, cond, cols, "")
 }
 
 // JoinLeft add an LEFT JOIN table and colums to the query
-func (s *DefaultSelect) JoinLeft(name string, cond string, cols interface{}) Select {
+func (s *DefaultSelect) JoinLeft(name interface{}, cond string, cols interface{}) Select {
 	return s.prepareJoin(LeftJoin, name, "", cond, cols, "")
 }
 
 // JoinLeftAs add an LEFT JOIN table and colums to the query
-func (s *DefaultSelect) JoinLeftAs(name string, alias string, cond string, cols interface{}) Select {
+func (s *DefaultSelect) JoinLeftAs(name interface{}, alias string, cond string, cols interface{}) Select {
 	return s.prepareJoin(LeftJoin, name, alias, cond, cols, "")
 }
 
 // Where adds a WHERE condition to the query by AND
 func (s *DefaultSelect) Where(cond string, value interface{}) Select {
-	s.Parts.Where = append(s.Parts.Where, s.where(cond, value, "", true))
+	if where := s.where(cond, value, "", true); where != "" {
+		s.Parts.Where = append(s.Parts.Where, where)
+	}
+
 	return s
 }
 
 // OrWhere adds a WHERE condition to the query by OR
 func (s *DefaultSelect) OrWhere(cond string, value interface{}) Select {
-	s.Parts.Where = append(s.Parts.Where, s.where(cond, value, "", false))
+	if where := s.where(cond, value, "", false); where != "" {
+		s.Parts.Where = append(s.Parts.Where, where)
+	}
+
+	return s
+}
+
+// Group adds a GROUP BY clause to the query
+func (s *DefaultSelect) Group(spec interface{}) Select {
+	if len(s.Parts.Union) > 0 {
+		s.Errors = append(s.Errors, errors.Errorf("Invalid use of group clause with %s", Union))
+		return s
+	}
+
+	switch groupPart := spec.(type) {
+	case []*SQLExpr:
+		for _, expr := range groupPart {
+			s.Parts.Group = append(s.Parts.Group, expr.ToString())
+		}
+
+	case *SQLExpr:
+		s.Parts.Group = append(s.Parts.Group, groupPart.ToString())
+
+	case []string:
+		for _, col := range groupPart {
+			s.Parts.Group = append(s.Parts.Group, s.groupColumn(col))
+		}
+
+	case string:
+		for _, col := range strings.Split(groupPart, ",") {
+			s.Parts.Group = append(s.Parts.Group, s.groupColumn(col))
+		}
+
+	default:
+		s.Errors = append(s.Errors, errors.Errorf("Invalid group type '%v'", reflect.TypeOf(spec)))
+	}
+
+	return s
+}
+
+// Having adds a HAVING condition to the query by AND
+func (s *DefaultSelect) Having(cond string, value interface{}) Select {
+	if having := s.having(cond, value, true); having != "" {
+		s.Parts.Having = append(s.Parts.Having, having)
+	}
+
+	return s
+}
+
+// OrHaving adds a HAVING condition to the query by OR
+func (s *DefaultSelect) OrHaving(cond string, value interface{}) Select {
+	if having := s.having(cond, value, false); having != "" {
+		s.Parts.Having = append(s.Parts.Having, having)
+	}
+
+	return s
+}
+
+// ForUpdate makes the query SELECT FOR UPDATE
+func (s *DefaultSelect) ForUpdate(flag bool) Select {
+	s.Parts.ForUpdate = flag
 	return s
 }
 
@@ -395,18 +479,109 @@ func (s *DefaultSelect) Order(order interface{}) Select {
 	return s
 }
 
-// Binds returns binds
-func (s *DefaultSelect) Binds() []interface{} {
-	binds := make([]interface{}, len(s.Bind))
-	i := 0
-	for _, value := range s.Bind {
-		binds[i] = value
-		i++
+// AddBind adds a bind value to the query
+func (s *DefaultSelect) AddBind(name string, value interface{}) Select {
+	if _, ok := s.Bind[name]; !ok {
+		s.bindOrder = append(s.bindOrder, name)
 	}
 
+	s.Bind[name] = value
+	return s
+}
+
+// Binds returns binds in order of their placeholders in assembled query
+// Binds added by AddBind fill placeholders written in query parts in order they were added
+func (s *DefaultSelect) Binds() []interface{} {
+	_, binds := s.subBinds.resolve(s.Adapter, s.render(), s.ownBinds())
 	return binds
 }
 
+// Cache marks query results to be cached for lifetime seconds with tags
+// Zero lifetime uses the lifetime of cache backend
+func (s *DefaultSelect) Cache(lifetime int64, tags ...string) Select {
+	s.cached = true
+	s.cacheLifetime = lifetime
+	s.cacheTags = tags
+	return s
+}
+
+// NoCache disables caching of query results
+func (s *DefaultSelect) NoCache() Select {
+	s.cached = false
+	s.cacheLifetime = 0
+	s.cacheTags = nil
+	return s
+}
+
+// IsCached returns true if query results should be cached
+func (s *DefaultSelect) IsCached() bool {
+	return s.cached
+}
+
+// CacheLifetime returns lifetime of cached query results
+func (s *DefaultSelect) CacheLifetime() int64 {
+	return s.cacheLifetime
+}
+
+// CacheTags returns tags of cached query results
+func (s *DefaultSelect) CacheTags() []string {
+	return s.cacheTags
+}
+
+// Tables returns names of tables the query reads from
+// Tables of embedded subqueries are included
+func (s *DefaultSelect) Tables() []string {
+	tables := make([]string, 0, len(s.Parts.From)+len(s.Parts.Join)+len(s.subTables))
+	for _, parts := range [][]*selectFrom{s.Parts.From, s.Parts.Join} {
+		for _, from := range parts {
+			if from.Derived {
+				continue
+			}
+
+			if from.Schema != "" {
+				tables = append(tables, from.Schema+"."+from.TableName)
+			} else {
+				tables = append(tables, from.TableName)
+			}
+		}
+	}
+
+	for _, table := range s.subTables {
+		if !utils.InSSlice(table, tables) {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables
+}
+
+// Clone returns a copy of the query which can be changed without affecting this one
+func (s *DefaultSelect) Clone() Select {
+	c := *s
+	parts := *s.Parts
+	parts.Columns = append([]*selectColumn{}, s.Parts.Columns...)
+	parts.Union = append([]*selectUnion{}, s.Parts.Union...)
+	parts.From = append([]*selectFrom{}, s.Parts.From...)
+	parts.Join = append([]*selectFrom{}, s.Parts.Join...)
+	parts.Where = append([]string{}, s.Parts.Where...)
+	parts.Group = append([]interface{}{}, s.Parts.Group...)
+	parts.Having = append([]interface{}{}, s.Parts.Having...)
+	parts.Order = append([]string{}, s.Parts.Order...)
+	c.Parts = &parts
+
+	c.Bind = make(map[string]interface{}, len(s.Bind))
+	for name, value := range s.Bind {
+		c.Bind[name] = value
+	}
+
+	c.bindOrder = append([]string{}, s.bindOrder...)
+	c.subBinds = placeholders{values: append([]interface{}{}, s.subBinds.values...)}
+	c.subTables = append([]string{}, s.subTables...)
+	c.Errors = append([]error{}, s.Errors...)
+	c.cacheTags = append([]string{}, s.cacheTags...)
+	return &c
+}
+
 // Err pops last acuired error or nil if no errors
 func (s *DefaultSelect) Err() error {
 	if len(s.Errors) > 0 {
@@ -426,6 +601,9 @@ func (s *DefaultSelect) ToString() string {
 // Clear select struct
 func (s *DefaultSelect) Clear() Select {
 	s.Bind = make(map[string]interface{})
+	s.bindOrder = []string{}
+	s.subBinds.reset()
+	s.subTables = []string{}
 	s.Parts.Dinstinct = false
 	s.Parts.Columns = []*selectColumn{}
 	s.Parts.Union = []*selectUnion{}
@@ -439,6 +617,7 @@ func (s *DefaultSelect) Clear() Select {
 	s.Parts.LimitOffset = 0
 	s.Parts.ForUpdate = false
 	s.Errors = make([]error, 0)
+	s.NoCache()
 	return s
 }
 
@@ -487,17 +666,23 @@ func (s *DefaultSelect) Reset(part string) Select {
 
 // Assemble converts this object to an SQL SELECT string
 func (s *DefaultSelect) Assemble() string {
+	sql, _ := s.subBinds.resolve(s.Adapter, s.render(), s.ownBinds())
+	return sql
+}
+
+// Renders query parts leaving markers of subqueries binds in place
+func (s *DefaultSelect) render() string {
 	sql := SQLSelect
 	sql = s.renderDistinct(sql)
 	sql = s.renderColumns(sql)
 	sql = s.renderFrom(sql)
 	sql = s.renderWhere(sql)
+	sql = s.renderGroup(sql)
+	sql = s.renderHaving(sql)
 	sql = s.renderUnion(sql)
-	//sql = s.renderGroup(sql)
-	//sql = s.renderHaving(sql)
 	sql = s.renderOrder(sql)
 	sql = s.renderLimit(sql)
-	//sql = s.renderForupdate(sql)
+	sql = s.renderForupdate(sql)
 
 	return sql
 }
@@ -515,6 +700,7 @@ func (s *DefaultSelect) prepareJoin(typ string, name interface{}, alias string,
 
 	correlationName := alias
 	tableName := ""
+	derived := false
 	switch t := name.(type) {
 	case map[string]string:
 		for tmpCorrelationName, tmpTableName := range name.(map[string]string) {
@@ -532,12 +718,14 @@ func (s *DefaultSelect) prepareJoin(typ string, name interface{}, alias string,
 
 	case *SQLExpr:
 		tableName = name.(*SQLExpr).Assemble()
+		derived = true
 		if alias == "" {
 			correlationName = s.uniqueCorrelation("t")
 		}
 
 	case Select:
-		tableName = name.(Select).Assemble()
+		tableName = "(" + s.subquery(name.(Select)) + ")"
+		derived = true
 		if alias == "" {
 			correlationName = s.uniqueCorrelation("t")
 		}
@@ -559,7 +747,7 @@ func (s *DefaultSelect) prepareJoin(typ string, name interface{}, alias string,
 	}
 
 	// Schema from table name overrides schema argument
-	if strings.Index(tableName, ".") > 0 {
+	if !derived && strings.Index(tableName, ".") > 0 {
 		parts := strings.Split(tableName, ".")
 		schema, tableName = parts[0], parts[1]
 	}
@@ -590,6 +778,7 @@ func (s *DefaultSelect) prepareJoin(typ string, name interface{}, alias string,
 			TableName:     tableName,
 			JoinCondition: s.Adapter.QuoteIdentifier(cond, true),
 			Alias:         correlationName,
+			Derived:       derived,
 		}
 
 		if fromkey == 0 {
@@ -613,7 +802,7 @@ func (s *DefaultSelect) where(condition string, value interface{}, typ string, b
 
 	condition = s.Adapter.QuoteIdentifier(condition, true)
 	if value != nil {
-		condition = s.Adapter.QuoteInto(condition, value, -1)
+		condition = s.Adapter.QuoteInto(condition, s.subqueryValue(value), -1)
 	}
 
 	cond := ""
@@ -628,6 +817,86 @@ func (s *DefaultSelect) where(condition string, value interface{}, typ string, b
 	return cond + "(" + condition + ")"
 }
 
+func (s *DefaultSelect) having(condition string, value interface{}, b bool) string {
+	if len(s.Parts.Union) > 0 {
+		s.Errors = append(s.Errors, errors.Errorf("Invalid use of having clause with %s", Union))
+		return ""
+	}
+
+	condition = s.Adapter.QuoteIdentifier(condition, true)
+	if value != nil {
+		condition = s.Adapter.QuoteInto(condition, s.subqueryValue(value), -1)
+	}
+
+	cond := ""
+	if len(s.Parts.Having) > 0 {
+		if b {
+			cond = SQLAnd + " "
+		} else {
+			cond = SQLOr + " "
+		}
+	}
+
+	return cond + "(" + condition + ")"
+}
+
+// Quotes a single GROUP BY column leaving function calls as is
+func (s *DefaultSelect) groupColumn(col string) string {
+	col = strings.TrimSpace(col)
+	if strings.Contains(col, "(") {
+		return NewExpr(col).ToString()
+	}
+
+	return s.Adapter.QuoteIdentifier(col, true)
+}
+
+// Replaces subqueries used as value with expression of their sql
+func (s *DefaultSelect) subqueryValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Select:
+		return NewExpr("(" + s.subquery(v) + ")")
+
+	case []Select:
+		subs := make([]string, 0, len(v))
+		for _, sub := range v {
+			subs = append(subs, "("+s.subquery(sub)+")")
+		}
+
+		return NewExpr(strings.Join(subs, ", "))
+	}
+
+	return value
+}
+
+// Returns sql of a subquery embedded into this query
+// Binds of subquery are merged in order of its placeholders
+func (s *DefaultSelect) subquery(sub Select) string {
+	if s.Adapter == nil {
+		s.Errors = append(s.Errors, errors.New("Adapter is not set"))
+		return ""
+	}
+
+	sql, err := s.subBinds.embed(s.Adapter, sub)
+	if err != nil {
+		s.Errors = append(s.Errors, err)
+	}
+
+	s.subTables = append(s.subTables, sub.Tables()...)
+	return sql
+}
+
+// Returns binds added by AddBind in order they were added
+func (s *DefaultSelect) ownBinds() []interface{} {
+	binds := make([]interface{}, 0, len(s.bindOrder))
+	for _, name := range s.bindOrder {
+		if value, ok := s.Bind[name]; ok {
+			binds = append(binds, value)
+		}
+	}
+
+	return binds
+}
+
 // Adds to the internal table-to-column mapping array
 func (s *DefaultSelect) tableCols(correlationName string, cols interface{}, afterCorrelationName string) Select {
 	columnValues := []*selectColumn{}
@@ -849,7 +1118,9 @@ func (s *DefaultSelect) renderFrom(sql string) string {
 		}
 
 		tmp = tmp + s.quotedSchema(table.Schema)
-		if table.TableName == table.Alias {
+		if table.Derived {
+			tmp = tmp + s.Adapter.QuoteTableAs(NewExpr(table.TableName), table.Alias, true)
+		} else if table.TableName == table.Alias {
 			tmp = tmp + s.quotedTable(table.TableName, "")
 		} else {
 			tmp = tmp + s.quotedTable(table.TableName, table.Alias)
@@ -892,6 +1163,34 @@ func (s *DefaultSelect) renderWhere(sql string) string {
 	return sql
 }
 
+// Render GROUP BY clause
+func (s *DefaultSelect) renderGroup(sql string) string {
+	if len(s.Parts.From) > 0 && len(s.Parts.Group) > 0 {
+		group := make([]string, 0, len(s.Parts.Group))
+		for _, part := range s.Parts.Group {
+			group = append(group, part.(string))
+		}
+
+		sql = sql + " " + SQLGroupBy + " " + strings.Join(group, ", ")
+	}
+
+	return sql
+}
+
+// Render HAVING clause
+func (s *DefaultSelect) renderHaving(sql string) string {
+	if len(s.Parts.From) > 0 && len(s.Parts.Having) > 0 {
+		having := make([]string, 0, len(s.Parts.Having))
+		for _, part := range s.Parts.Having {
+			having = append(having, part.(string))
+		}
+
+		sql = sql + " " + SQLHaving + " " + strings.Join(having, " ")
+	}
+
+	return sql
+}
+
 // Render ORDER BY clause
 func (s *DefaultSelect) renderOrder(sql string) string {
 	if len(s.Parts.Order) > 0 {
@@ -923,7 +1222,7 @@ func (s *DefaultSelect) renderLimit(sql string) string {
 
 // Render FOR UPDATE clause
 func (s *DefaultSelect) renderForupdate(sql string) string {
-	if s.Parts.ForUpdate {
+	if s.Parts.ForUpdate && s.Adapter.SupportsParameters("forUpdate") {
 		sql = sql + " " + SQLForUpdate
 	}
 
@@ -966,7 +1265,8 @@ func NewDefaultSelect(options *SelectConfig) (Select, error) {
 			LimitOffset: 0,
 			ForUpdate:   false,
 		},
-		Errors: make([]error, 0),
+		Errors:    make([]error, 0),
+		bindOrder: []string{},
 	}, nil
 }
 
@@ -992,6 +1292,7 @@ func NewSelectEmpty() Select {
 			LimitOffset: 0,
 			ForUpdate:   false,
 		},
-		Errors: make([]error, 0),
+		Errors:    make([]error, 0),
+		bindOrder: []string{},
 	}
 }
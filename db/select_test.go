@@ -0,0 +1,217 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestAdapter(t *testing.T, constructor func(*AdapterConfig) (Adapter, error)) Adapter {
+	t.Helper()
+
+	cfg := &AdapterConfig{}
+	cfg.Defaults()
+
+	adp, err := constructor(cfg)
+	if err != nil {
+		t.Fatalf("adapter: %v", err)
+	}
+
+	return adp
+}
+
+func newTestSelect(adp Adapter) Select {
+	slct := NewSelectEmpty()
+	slct.SetAdapter(adp)
+	return slct
+}
+
+// Builds a query with a derived table added after a WHERE IN subquery
+func buildSubqueriesSelect(adp Adapter) Select {
+	orders := newTestSelect(adp).From("orders", "user_id").Where("status = ?", nil).AddBind("status", "paid")
+	accounts := newTestSelect(adp).From("accounts", "*").Where("region = ?", nil).AddBind("region", "eu")
+
+	slct := newTestSelect(adp)
+	slct.Where("id IN ?", orders)
+	slct.FromAs(accounts, "a", "*")
+	slct.Where("name = ?", nil).AddBind("name", "bob")
+	return slct
+}
+
+func TestSelectSubqueryBindsFollowPlaceholders(t *testing.T) {
+	cases := []struct {
+		name        string
+		constructor func(*AdapterConfig) (Adapter, error)
+		sql         string
+	}{
+		{
+			name:        "postgres",
+			constructor: NewPostgresAdapter,
+			sql:         `SELECT "a".* FROM (SELECT "accounts".* FROM "accounts" WHERE ("region" = $1)) AS "a" WHERE ("id" IN (SELECT "orders"."user_id" FROM "orders" WHERE ("status" = $2))) AND ("name" = $3)`,
+		},
+		{
+			name:        "mysql",
+			constructor: NewMySQLAdapter,
+			sql:         "SELECT `a`.* FROM (SELECT `accounts`.* FROM `accounts` WHERE (`region` = ?)) AS `a` WHERE (`id` IN (SELECT `orders`.`user_id` FROM `orders` WHERE (`status` = ?))) AND (`name` = ?)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			slct := buildSubqueriesSelect(newTestAdapter(t, c.constructor))
+			if err := slct.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+
+			if sql := slct.Assemble(); sql != c.sql {
+				t.Errorf("Assemble()\n got: %s\nwant: %s", sql, c.sql)
+			}
+
+			if binds, want := slct.Binds(), []interface{}{"eu", "paid", "bob"}; !reflect.DeepEqual(binds, want) {
+				t.Errorf("Binds() = %v, want %v", binds, want)
+			}
+		})
+	}
+}
+
+func TestSelectNestedSubqueryRenumbered(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	inner := newTestSelect(adp).From("items", "order_id").Where("sku = ?", nil).AddBind("sku", "A1")
+	middle := newTestSelect(adp).From("orders", "user_id").Where("total > ?", nil).AddBind("total", 10).Where("id IN ?", inner)
+	slct := newTestSelect(adp).From("users", "*").Where("active = ?", nil).AddBind("active", true).Where("id IN ?", middle)
+
+	sql := slct.Assemble()
+	for i, placeholder := range []string{"$1", "$2", "$3"} {
+		if strings.Count(sql, placeholder) != 1 {
+			t.Fatalf("placeholder %d (%s) is not rendered once in %s", i+1, placeholder, sql)
+		}
+	}
+
+	if !(strings.Index(sql, "$1") < strings.Index(sql, "$2") && strings.Index(sql, "$2") < strings.Index(sql, "$3")) {
+		t.Errorf("placeholders are out of order in %s", sql)
+	}
+
+	if binds, want := slct.Binds(), []interface{}{true, 10, "A1"}; !reflect.DeepEqual(binds, want) {
+		t.Errorf("Binds() = %v, want %v", binds, want)
+	}
+}
+
+func TestSelectUnionBinds(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	first := newTestSelect(adp).From("a", "id").Where("x = ?", nil).AddBind("x", 1)
+	second := newTestSelect(adp).From("b", "id").Where("y = ?", nil).AddBind("y", 2)
+	slct := newTestSelect(adp).From("c", "id").Where("z = ?", nil).AddBind("z", 3).Union([]Select{first, second}, SQLUnionAll)
+
+	want := `SELECT "c"."id" FROM "c" WHERE ("z" = $1) UNION ALL SELECT "a"."id" FROM "a" WHERE ("x" = $2) UNION ALL SELECT "b"."id" FROM "b" WHERE ("y" = $3)`
+	if sql := slct.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+
+	if binds := slct.Binds(); !reflect.DeepEqual(binds, []interface{}{3, 1, 2}) {
+		t.Errorf("Binds() = %v", binds)
+	}
+}
+
+func TestSelectGroupHavingForUpdate(t *testing.T) {
+	adp := newTestAdapter(t, NewMySQLAdapter)
+
+	slct := newTestSelect(adp).From("orders", "user_id").Group("user_id").Having("COUNT(id) > ?", 2).OrHaving("SUM(total) > ?", 100).ForUpdate(true)
+	want := "SELECT `orders`.`user_id` FROM `orders` GROUP BY `user_id` HAVING (COUNT(`id`) > 2) OR (SUM(`total`) > 100) FOR UPDATE"
+	if sql := slct.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+}
+
+func TestSelectHavingWithUnionRecordsError(t *testing.T) {
+	adp := newTestAdapter(t, NewMySQLAdapter)
+
+	slct := newTestSelect(adp).From("a", "id").Union(newTestSelect(adp).From("b", "id"), SQLUnion)
+	slct.Having("COUNT(id) > ?", 1)
+	slct.Where("id = ?", 1)
+
+	if err := slct.Err(); err == nil {
+		t.Fatal("Err() = nil, want error of having with union")
+	}
+
+	if sql := slct.Assemble(); strings.Contains(sql, "HAVING") || strings.Contains(sql, "WHERE") {
+		t.Errorf("Assemble() = %s, rejected clauses are rendered", sql)
+	}
+}
+
+func TestSelectSubqueryErrorIsRecorded(t *testing.T) {
+	adp := newTestAdapter(t, NewMySQLAdapter)
+
+	sub := newTestSelect(adp).From("a", "id").Union("SELECT 1", "INVALID")
+	slct := newTestSelect(adp).From("b", "id").Where("id IN ?", sub)
+
+	if err := slct.Err(); err == nil {
+		t.Fatal("Err() = nil, want error of invalid subquery")
+	}
+}
+
+func TestScanPlaceholdersSkipsQuoted(t *testing.T) {
+	cases := []struct {
+		name        string
+		constructor func(*AdapterConfig) (Adapter, error)
+		sql         string
+		want        string
+		binds       []interface{}
+	}{
+		{"postgres", NewPostgresAdapter, `SELECT '?', '$1', "a?" FROM t WHERE a = $2 AND b = ?`, `SELECT '?', '$1', "a?" FROM t WHERE a = $1 AND b = $2`, []interface{}{2, 1}},
+		{"mysql", NewMySQLAdapter, "SELECT 'it\\'s ?', `a?` FROM t WHERE a = ?", "SELECT 'it\\'s ?', `a?` FROM t WHERE a = ?", []interface{}{1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var p placeholders
+			sql, binds := p.resolve(newTestAdapter(t, c.constructor), c.sql, []interface{}{1, 2})
+			if sql != c.want {
+				t.Errorf("resolve()\n got: %s\nwant: %s", sql, c.want)
+			}
+
+			if !reflect.DeepEqual(binds, c.binds) {
+				t.Errorf("resolve() binds = %v, want %v", binds, c.binds)
+			}
+		})
+	}
+}
+
+func TestSelectClone(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	sub := newTestSelect(adp).From("orders", "user_id").Where("status = ?", nil).AddBind("status", "paid")
+	slct := newTestSelect(adp).From("users", "*").Where("id IN ?", sub).Limit(10, 20)
+	sql := slct.Assemble()
+
+	clone := slct.Clone().Limit(0, 0).Where("active = ?", 1)
+	if slct.Assemble() != sql {
+		t.Errorf("changing clone changed original query: %s", slct.Assemble())
+	}
+
+	want := `SELECT "users".* FROM "users" WHERE ("id" IN (SELECT "orders"."user_id" FROM "orders" WHERE ("status" = $1))) AND ("active" = 1)`
+	if got := clone.Assemble(); got != want {
+		t.Errorf("clone Assemble()\n got: %s\nwant: %s", got, want)
+	}
+
+	if binds := clone.Binds(); !reflect.DeepEqual(binds, []interface{}{"paid"}) {
+		t.Errorf("clone Binds() = %v", binds)
+	}
+}
+
+func TestSelectTablesIncludeSubqueries(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	slct := buildSubqueriesSelect(adp)
+	slct.JoinInner("users", "users.id = a.user_id", "name")
+	slct.Where("id NOT IN ?", newTestSelect(adp).From("orders", "user_id"))
+
+	if tables := slct.Tables(); !reflect.DeepEqual(tables, []string{"users", "orders", "accounts"}) {
+		t.Errorf("Tables() = %v", tables)
+	}
+
+	if tables := slct.Clone().Tables(); !reflect.DeepEqual(tables, []string{"users", "orders", "accounts"}) {
+		t.Errorf("clone Tables() = %v", tables)
+	}
+}
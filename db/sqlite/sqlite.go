@@ -0,0 +1,652 @@
+package sqlite
+
+import (
+	goctx "context"
+	"database/sql"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+
+	// SQLite uses cgo driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// TYPEAdapter represents sqlite db adapter
+	TYPEAdapter = "SQLite"
+
+	// Memory is a database name for in-memory database
+	Memory = ":memory:"
+)
+
+// Adapter is registered by importing the package
+// Driver requires cgo, so it is kept out of the core db package
+func init() {
+	db.RegisterAdapter(TYPEAdapter, NewAdapter)
+}
+
+// Adapter for SQLite databases
+type Adapter struct {
+	db.DefaultAdapter
+}
+
+// Setup the adapter
+func (a *Adapter) Setup() {
+	a.IdentifierSymbol = `"`
+	a.EscapeQuotesByDoubling = true
+	a.AutoQuoteIdentifiers = true
+	a.PingTimeout = time.Duration(a.Options.PingTimeout) * time.Second
+	a.QueryTimeout = time.Duration(a.Options.QueryTimeout) * time.Second
+
+	a.Unquoteable = []string{
+		"BETWEEN",
+		"LIKE",
+		"GLOB",
+		"AND",
+		"OR",
+		"DESC",
+		"ASC",
+		"=",
+		"!=",
+		">",
+		">=",
+		"<",
+		"<=",
+		"<>",
+		"/",
+		"+",
+		"-",
+		"?",
+		"*",
+		"(",
+		")",
+		"IS",
+		"NOT",
+		"NULL",
+		"IN",
+		"IN(",
+		" ",
+		".",
+	}
+
+	a.Spliters = []string{
+		"=",
+		"!=",
+		">",
+		">=",
+		"<",
+		"<=",
+		"<>",
+		"/",
+		"+",
+		"-",
+		".",
+		" ",
+	}
+
+	a.UnquoteableFunctions = []string{
+		"lower",
+		"upper",
+		"length",
+		"substr",
+		"trim",
+		"max",
+		"min",
+		"avg",
+		"sum",
+		"total",
+		"abs",
+		"round",
+		"count",
+		"random",
+		"date",
+		"datetime",
+		"strftime",
+		"coalesce",
+		"ifnull",
+		"nullif",
+	}
+
+	a.Params = map[string]interface{}{
+		"positional":       true,
+		"named":            true,
+		"transactionalDDL": true,
+		"forUpdate":        false,
+	}
+}
+
+// Init a connection to database
+func (a *Adapter) Init() (err error) {
+	conn, err := sql.Open("sqlite3", a.FormatDSN())
+	if err != nil {
+		return errors.Wrap(err, "SQLite Error")
+	}
+
+	if a.Options.DBname == "" || a.Options.DBname == Memory {
+		// Every connection to in-memory database opens a new empty database
+		conn.SetMaxOpenConns(1)
+		conn.SetMaxIdleConns(1)
+		conn.SetConnMaxLifetime(0)
+	} else {
+		conn.SetConnMaxLifetime(time.Duration(a.Options.ConnectionMaxLifeTime) * time.Second)
+		conn.SetMaxIdleConns(a.Options.MaxIdleConnections)
+		conn.SetMaxOpenConns(a.Options.MaxOpenConnections)
+	}
+
+	if a.PingTimeout > 0 {
+		tctx, cancel := goctx.WithTimeout(goctx.Background(), a.PingTimeout)
+		defer cancel()
+
+		if err = conn.PingContext(tctx); err != nil {
+			return errors.Wrap(err, "SQLite Error")
+		}
+	} else {
+		if err = conn.PingContext(goctx.Background()); err != nil {
+			return errors.Wrap(err, "SQLite Error")
+		}
+	}
+
+	a.Db = conn
+	return nil
+}
+
+// SetOptions sets new options for SQLite adapter
+func (a *Adapter) SetOptions(options *db.AdapterConfig) error {
+	a.Options = options
+	return nil
+}
+
+// GetOptions returns SQLite adapter options
+func (a *Adapter) GetOptions() *db.AdapterConfig {
+	return a.Options
+}
+
+// Select creates a new adapter specific select object
+func (a *Adapter) Select() db.Select {
+	sel := db.NewSelectFromConfig(db.Options().Select)
+	sel.SetAdapter(a)
+	return sel
+}
+
+// Query runs a query
+func (a *Adapter) Query(ctx context.Context, dbs db.Select) ([]map[string]interface{}, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	if err := dbs.Err(); err != nil {
+		return nil, errors.Wrap(err, "SQLite query Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, db.StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
+	if err != nil {
+		qp.End(0, err)
+		return nil, errors.Wrap(err, "SQLite query Error")
+	}
+	defer rows.Close()
+
+	data, err := a.PrepareRowset(rows)
+	qp.End(int64(len(data)), err)
+	return data, err
+}
+
+// QueryRow runs a query
+func (a *Adapter) QueryRow(ctx context.Context, dbs db.Select) (map[string]interface{}, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	if err := dbs.Err(); err != nil {
+		return nil, errors.Wrap(err, "SQLite query Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := a.Profiler().Start(ctx, query, binds, db.StatementTable(dbs))
+	rows, err := a.Db.QueryContext(qctx, query, binds...)
+	if err != nil {
+		qp.End(0, err)
+		return nil, errors.Wrap(err, "SQLite query Error")
+	}
+	defer rows.Close()
+
+	row, err := a.PrepareRow(rows)
+	var count int64
+	if row != nil {
+		count = 1
+	}
+
+	qp.End(count, err)
+	return row, err
+}
+
+// PrepareRowset parses sql.Rows into mapstructure slice
+func (a *Adapter) PrepareRowset(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, errors.Wrap(err, "SQLite prepare result Error")
+	}
+
+	scanArgs := make([]interface{}, len(columns))
+	for i := range columns {
+		scanArgs[i] = a.Reference(columns[i].ScanType())
+	}
+
+	data := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		if err = rows.Scan(scanArgs...); err != nil {
+			return nil, errors.Wrap(err, "SQLite prepare result Error")
+		}
+
+		rowdata := make(map[string]interface{})
+		for i := range columns {
+			rowdata[columns[i].Name()] = a.Dereference(scanArgs[i])
+		}
+		data = append(data, rowdata)
+	}
+
+	if err := rows.Err(); err != nil {
+		if err == sql.ErrNoRows {
+			return data, nil
+		}
+
+		return nil, errors.Wrap(err, "SQLite prepare result Error")
+	}
+
+	return data, nil
+}
+
+// PrepareRow parses a first row of sql.Rows into map structure
+func (a *Adapter) PrepareRow(rows *sql.Rows) (map[string]interface{}, error) {
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, errors.Wrap(err, "SQLite prepare result error")
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "SQLite prepare result error")
+		}
+
+		return nil, nil
+	}
+
+	scanArgs := make([]interface{}, len(columns))
+	for i := range columns {
+		scanArgs[i] = a.Reference(columns[i].ScanType())
+	}
+
+	if err = rows.Scan(scanArgs...); err != nil {
+		return nil, errors.Wrap(err, "SQLite prepare result error")
+	}
+
+	data := make(map[string]interface{})
+	for i := range columns {
+		data[columns[i].Name()] = a.Dereference(scanArgs[i])
+	}
+
+	return data, nil
+}
+
+// Insert inserts new row into table
+func (a *Adapter) Insert(ctx context.Context, table string, data map[string]interface{}) (int, error) {
+	if a.Db == nil {
+		return 0, errors.New("Database is not initialized")
+	}
+
+	cols := []string{}
+	vals := []string{}
+	binds := []interface{}{}
+	for col, val := range data {
+		cols = append(cols, a.QuoteIdentifier(col, true))
+
+		switch v := val.(type) {
+		case *db.SQLExpr:
+			vals = append(vals, v.ToString())
+
+		default:
+			vals = append(vals, "?")
+			binds = append(binds, val)
+		}
+	}
+
+	query := "INSERT INTO " + a.QuoteIdentifier(table, true)
+	if len(cols) > 0 {
+		query = query + " (" + strings.Join(cols, ", ") + ") VALUES (" + strings.Join(vals, ", ") + ")"
+	} else {
+		query = query + " DEFAULT VALUES"
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, binds, table)
+	result, err := a.Db.ExecContext(qctx, query, binds...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return 0, errors.Wrap(err, "SQLite insert Error")
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err == nil {
+		a.LastInsertID = int(lastInsertID)
+	}
+
+	return a.LastInsertID, nil
+}
+
+// Update updates rows into table be condition
+func (a *Adapter) Update(ctx context.Context, table string, data map[string]interface{}, cond map[string]interface{}) (bool, error) {
+	if a.Db == nil {
+		return false, errors.New("Database is not initialized")
+	}
+
+	if len(data) == 0 {
+		return false, errors.New("SQLite update Error: Nothing to update")
+	}
+
+	set := []string{}
+	binds := []interface{}{}
+	for col, val := range data {
+		switch v := val.(type) {
+		case *db.SQLExpr:
+			set = append(set, a.QuoteIdentifier(col, true)+" = "+v.ToString())
+
+		default:
+			set = append(set, a.QuoteIdentifier(col, true)+" = ?")
+			binds = append(binds, val)
+		}
+	}
+
+	query := "UPDATE " + a.QuoteIdentifier(table, true) + " SET " + strings.Join(set, ", ")
+	if where := a.WhereExpresion(cond); where != "" {
+		query = query + " WHERE " + where
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, binds, table)
+	result, err := a.Db.ExecContext(qctx, query, binds...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return false, errors.Wrap(err, "SQLite update Error")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return true, nil
+	}
+
+	return affected > 0, nil
+}
+
+// Delete removes rows from table
+func (a *Adapter) Delete(ctx context.Context, table string, cond map[string]interface{}) (bool, error) {
+	if a.Db == nil {
+		return false, errors.New("Database is not initialized")
+	}
+
+	query := "DELETE FROM " + a.QuoteIdentifier(table, true)
+	if where := a.WhereExpresion(cond); where != "" {
+		query = query + " WHERE " + where
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	qp := a.Profiler().Start(ctx, query, nil, table)
+	result, err := a.Db.ExecContext(qctx, query)
+	qp.EndResult(result, err)
+	if err != nil {
+		return false, errors.Wrap(err, "SQLite delete Error")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return true, nil
+	}
+
+	return affected > 0, nil
+}
+
+// DescribeTable returns information about columns in table
+func (a *Adapter) DescribeTable(table string, schema string) (map[string]*db.TableColumn, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	sqlstr := "PRAGMA table_info(" + a.QuoteIdentifier(table, true) + ")"
+	if schema != "" {
+		sqlstr = "PRAGMA " + a.QuoteIdentifier(schema, true) + ".table_info(" + a.QuoteIdentifier(table, true) + ")"
+	}
+
+	ctx := goctx.Background()
+	if a.Ctx != nil {
+		ctx = a.Ctx
+	}
+
+	qctx, cancel := goctx.WithTimeout(ctx, a.QueryTimeout)
+	defer cancel()
+
+	rows, err := a.Db.QueryContext(qctx, sqlstr)
+	if err != nil {
+		return nil, errors.Wrap(err, "SQLite Error")
+	}
+	defer rows.Close()
+
+	desc := make(map[string]*db.TableColumn)
+	primary := make([]*db.TableColumn, 0)
+	for rows.Next() {
+		var cid, notnull, pk int64
+		var name, dataType string
+		var dflt sql.NullString
+
+		if err := rows.Scan(&cid, &name, &dataType, &notnull, &dflt, &pk); err != nil {
+			return nil, errors.Wrap(err, "SQLite Error")
+		}
+
+		row := &db.TableColumn{
+			TableSchema: schema,
+			TableName:   table,
+			Name:        name,
+			Position:    cid + 1,
+			DataType:    strings.ToUpper(dataType),
+			ColumnType:  dataType,
+			IsNullable:  notnull == 0,
+		}
+
+		if dflt.Valid {
+			row.Default = dflt.String
+		}
+
+		if pk > 0 {
+			row.Primary = true
+			row.PrimaryPosition = pk - 1
+			row.ColumnKey = "PRI"
+			primary = append(primary, row)
+		}
+
+		desc[row.Name] = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "SQLite Error")
+	}
+
+	// A single INTEGER PRIMARY KEY column is an alias for rowid
+	if len(primary) == 1 && primary[0].DataType == "INTEGER" {
+		primary[0].Identity = true
+	}
+
+	if len(desc) == 0 {
+		return nil, errors.Errorf("SQLite Error: Table '%s' does not exist", table)
+	}
+
+	return desc, nil
+}
+
+// Limit adds a limit clause to query
+func (a *Adapter) Limit(sql string, count int, offset int) string {
+	if count > 0 {
+		sql = sql + " LIMIT " + strconv.Itoa(count)
+
+		if offset > 0 {
+			sql = sql + " OFFSET " + strconv.Itoa(offset)
+		}
+	} else if offset > 0 {
+		sql = sql + " LIMIT -1 OFFSET " + strconv.Itoa(offset)
+	}
+
+	return sql
+}
+
+// NextSequenceID returns next value from sequence
+// SQLite has no sequences so identity columns are generated by database
+func (a *Adapter) NextSequenceID(sequence string) int {
+	return 0
+}
+
+// BeginTransaction creates a new database transaction
+func (a *Adapter) BeginTransaction(ctx context.Context) (db.Transaction, error) {
+	if a.Db == nil {
+		return nil, errors.New("Database is not initialized")
+	}
+
+	tx, err := a.Db.BeginTx(ctx, &sql.TxOptions{Isolation: a.Options.Transaction.IsolationLevel, ReadOnly: a.Options.Transaction.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+
+	trns, err := db.NewTransaction(a.Options.Transaction.Type, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	trns.SetAdapter(a)
+	trns.SetContext(ctx)
+	return trns, err
+}
+
+// FormatDSN returns a formated dsn string
+func (a *Adapter) FormatDSN() string {
+	name := a.Options.DBname
+	if name == "" {
+		name = Memory
+	}
+
+	params := url.Values{}
+	params.Set("_foreign_keys", "1")
+	if a.Options.QueryTimeout > 0 {
+		params.Set("_busy_timeout", strconv.Itoa(a.Options.QueryTimeout*1000))
+	}
+
+	if a.Options.TimeFormat != nil {
+		params.Set("_loc", a.Options.TimeFormat.String())
+	}
+
+	return "file:" + name + "?" + params.Encode()
+}
+
+// Dereference returns a value from pointer
+func (a *Adapter) Dereference(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *sql.NullBool:
+		if t.Valid {
+			return t.Bool
+		}
+
+		return nil
+	case *sql.NullString:
+		if t.Valid {
+			return t.String
+		}
+
+		return nil
+	case *sql.NullInt64:
+		if t.Valid {
+			return t.Int64
+		}
+
+		return nil
+	case *sql.NullFloat64:
+		if t.Valid {
+			return t.Float64
+		}
+
+		return nil
+	case *sql.NullTime:
+		if t.Valid {
+			return t.Time
+		}
+
+		return nil
+	case *[]byte:
+		if *t == nil {
+			return nil
+		}
+
+		return string(*t)
+	case *interface{}:
+		if b, ok := (*t).([]byte); ok {
+			return string(b)
+		}
+
+		return *t
+	default:
+		return a.DefaultAdapter.Dereference(v)
+	}
+}
+
+// Reference creates a pointer to value
+func (a *Adapter) Reference(tp reflect.Type) interface{} {
+	if tp == reflect.TypeOf(sql.NullBool{}) {
+		var v sql.NullBool
+		return &v
+	} else if tp == reflect.TypeOf(sql.NullInt64{}) {
+		var v sql.NullInt64
+		return &v
+	} else if tp == reflect.TypeOf(sql.NullFloat64{}) {
+		var v sql.NullFloat64
+		return &v
+	} else if tp == reflect.TypeOf(sql.NullString{}) {
+		var v sql.NullString
+		return &v
+	} else if tp == reflect.TypeOf(sql.NullTime{}) {
+		var v sql.NullTime
+		return &v
+	} else if tp == reflect.TypeOf(sql.RawBytes{}) {
+		var v []byte
+		return &v
+	}
+
+	var v interface{}
+	return &v
+}
+
+// ReferenceNulls creates a pointer to nullable value
+func (a *Adapter) ReferenceNulls(tp reflect.Type) interface{} {
+	return a.Reference(tp)
+}
+
+// NewAdapter creates a new SQLite adapter
+func NewAdapter(options *db.AdapterConfig) (ai db.Adapter, err error) {
+	adp := &Adapter{}
+	adp.Options = options
+	adp.Setup()
+
+	return adp, nil
+}
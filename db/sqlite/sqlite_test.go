@@ -0,0 +1,293 @@
+package sqlite
+
+import (
+	goctx "context"
+	"testing"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+)
+
+// Opens in-memory database and executes schema statements
+func newMemoryDB(t *testing.T, schema ...string) db.Adapter {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{
+		"adapter": map[string]interface{}{
+			"type":   TYPEAdapter,
+			"dbname": Memory,
+		},
+	})
+
+	d, err := db.NewDB(cfg)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	db.SetInstance(d)
+	adp := d.Adapter()
+
+	ctx, _ := context.NewContext(goctx.Background())
+	for _, stmt := range schema {
+		if _, err := adp.Exec(ctx, stmt); err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+	}
+
+	return adp
+}
+
+func newTable(t *testing.T, adp db.Adapter, name string) *db.DefaultTable {
+	t.Helper()
+
+	tbl := db.NewEmptyDefaultTable(&db.TableConfig{DefaultSource: db.DefaultNone})
+	tbl.Name = name
+	tbl.SetAdapter(adp)
+	if err := tbl.Setup(); err != nil {
+		t.Fatalf("table setup: %v", err)
+	}
+
+	return tbl
+}
+
+func newMemoryTable(t *testing.T) (db.Adapter, *db.DefaultTable) {
+	t.Helper()
+
+	adp := newMemoryDB(t, `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT)`)
+	return adp, newTable(t, adp, "users")
+}
+
+func TestTableInsertFindUpdateDelete(t *testing.T) {
+	_, tbl := newMemoryTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	id, err := tbl.Insert(ctx, map[string]interface{}{"name": "alice", "email": "alice@example.com"})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	if id != 1 {
+		t.Fatalf("Insert id = %d, want 1", id)
+	}
+
+	row, err := tbl.FetchRow(ctx, map[string]interface{}{"id = ?": id})
+	if err != nil {
+		t.Fatalf("FetchRow: %v", err)
+	}
+
+	if row.IsEmpty() || row.GetString("name") != "alice" || row.GetInt("id") != 1 {
+		t.Fatalf("FetchRow = %v", row.GetAll())
+	}
+
+	if ok, err := tbl.Update(ctx, map[string]interface{}{"email": "a@example.com"}, map[string]interface{}{"id = ?": id}); err != nil || !ok {
+		t.Fatalf("Update = %v, %v", ok, err)
+	}
+
+	rowset, err := tbl.FetchAll(ctx, map[string]interface{}{"name = ?": "alice"})
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	if rowset.Count() != 1 || rowset.GetOffset(0).GetString("email") != "a@example.com" {
+		t.Fatalf("FetchAll returned %d rows", rowset.Count())
+	}
+
+	if ok, err := tbl.Delete(ctx, map[string]interface{}{"id = ?": id}); err != nil || !ok {
+		t.Fatalf("Delete = %v, %v", ok, err)
+	}
+
+	row, err = tbl.FetchRow(ctx, map[string]interface{}{"id = ?": id})
+	if err != nil {
+		t.Fatalf("FetchRow: %v", err)
+	}
+
+	// Missing row is returned as a blank row
+	if row.Get("id") != nil {
+		t.Fatalf("row still exists after Delete: %v", row.GetAll())
+	}
+}
+
+func TestRowSaveRefreshDelete(t *testing.T) {
+	_, tbl := newMemoryTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	row := tbl.CreateRow(map[string]interface{}{"name": "bob"}, db.DefaultNone)
+	if err := row.Save(ctx); err != nil {
+		t.Fatalf("Save new row: %v", err)
+	}
+
+	if row.GetInt("id") == 0 {
+		t.Fatalf("Save did not set identity: %v", row.GetAll())
+	}
+
+	row.Set("email", "bob@example.com")
+	if err := row.Save(ctx); err != nil {
+		t.Fatalf("Save stored row: %v", err)
+	}
+
+	stored, err := tbl.FetchRow(ctx, map[string]interface{}{"id = ?": row.GetInt("id")})
+	if err != nil {
+		t.Fatalf("FetchRow: %v", err)
+	}
+
+	if stored.GetString("email") != "bob@example.com" {
+		t.Fatalf("stored email = %q", stored.GetString("email"))
+	}
+
+	if err := stored.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if err := stored.Delete(ctx); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	rowset, err := tbl.FetchAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	if rowset.Count() != 0 {
+		t.Fatalf("FetchAll returned %d rows after Delete", rowset.Count())
+	}
+}
+
+func TestDescribeTable(t *testing.T) {
+	adp, _ := newMemoryTable(t)
+
+	desc, err := adp.DescribeTable("users", "")
+	if err != nil {
+		t.Fatalf("DescribeTable: %v", err)
+	}
+
+	id, ok := desc["id"]
+	if !ok || !id.Primary || !id.Identity {
+		t.Fatalf("id column = %+v", id)
+	}
+
+	if desc["name"].IsNullable || !desc["email"].IsNullable {
+		t.Fatal("nullability is not described")
+	}
+}
+
+func TestRowRelations(t *testing.T) {
+	adp := newMemoryDB(t,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`,
+		`CREATE TABLE groups (id INTEGER PRIMARY KEY, title TEXT NOT NULL)`,
+		`CREATE TABLE user_groups (user_id INTEGER NOT NULL, group_id INTEGER NOT NULL, PRIMARY KEY (user_id, group_id))`,
+		`INSERT INTO users (id, name) VALUES (1, 'alice'), (2, 'bob')`,
+		`INSERT INTO groups (id, title) VALUES (1, 'admins'), (2, 'editors'), (3, 'guests')`,
+		`INSERT INTO user_groups (user_id, group_id) VALUES (1, 1), (1, 2), (2, 3)`,
+	)
+
+	users := newTable(t, adp, "users")
+	groups := newTable(t, adp, "groups")
+	userGroups := newTable(t, adp, "user_groups")
+	userGroups.AddReference("User", []string{"user_id"}, "users", []string{"id"}, "", "")
+	userGroups.AddReference("Group", []string{"group_id"}, "groups", []string{"id"}, "", "")
+
+	ctx, _ := context.NewContext(goctx.Background())
+	alice, err := users.FetchRow(ctx, map[string]interface{}{"id = ?": 1})
+	if err != nil {
+		t.Fatalf("FetchRow: %v", err)
+	}
+
+	rowset, err := alice.FindManyToManyRowset(ctx, groups, userGroups, "User", "Group", nil)
+	if err != nil {
+		t.Fatalf("FindManyToManyRowset: %v", err)
+	}
+
+	if rowset.Count() != 2 {
+		t.Fatalf("FindManyToManyRowset returned %d rows, want 2", rowset.Count())
+	}
+
+	// Select given with its own FROM part is joined with match table as is
+	slct := groups.Select(false).FromAs("user_groups", "i", nil).Where("i.group_id > ?", 1)
+	rowset, err = alice.FindManyToManyRowset(ctx, groups, userGroups, "User", "Group", slct)
+	if err != nil {
+		t.Fatalf("FindManyToManyRowset with select: %v", err)
+	}
+
+	if rowset.Count() != 1 || rowset.GetOffset(0).GetString("title") != "editors" {
+		t.Fatalf("FindManyToManyRowset with select returned %d rows", rowset.Count())
+	}
+
+	memberships, err := userGroups.FetchAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	if err := memberships.EagerLoadParent(ctx, users, "User"); err != nil {
+		t.Fatalf("EagerLoadParent: %v", err)
+	}
+
+	for i := 0; i < memberships.Count(); i++ {
+		membership := memberships.GetOffset(i)
+		parent, err := membership.FindParentRow(ctx, users, "User", nil)
+		if err != nil {
+			t.Fatalf("FindParentRow: %v", err)
+		}
+
+		if parent == nil || parent.GetInt("id") != membership.GetInt("user_id") {
+			t.Fatalf("parent of membership %v is not loaded", membership.GetAll())
+		}
+	}
+}
+
+type testUser struct {
+	ID    int    `db:"id,readonly"`
+	Name  string `db:"name"`
+	Email string `db:"email,omitempty"`
+}
+
+// Untagged fields of structs decoded before struct mapping was introduced
+type testLegacyUser struct {
+	UserID int
+	Email  string
+}
+
+func TestTableStructs(t *testing.T) {
+	_, tbl := newMemoryTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	user := &testUser{Name: "alice"}
+	id, err := tbl.InsertStruct(ctx, user)
+	if err != nil {
+		t.Fatalf("InsertStruct: %v", err)
+	}
+
+	if user.ID != id || id == 0 {
+		t.Fatalf("InsertStruct set id %d, returned %d", user.ID, id)
+	}
+
+	user.Email = "alice@example.com"
+	if ok, err := tbl.UpdateStruct(ctx, user, nil); err != nil || !ok {
+		t.Fatalf("UpdateStruct = %v, %v", ok, err)
+	}
+
+	row, err := tbl.FetchRow(ctx, map[string]interface{}{"id = ?": id})
+	if err != nil {
+		t.Fatalf("FetchRow: %v", err)
+	}
+
+	stored := &testUser{}
+	if err := row.Unmarshal(stored); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if stored.ID != id || stored.Email != "alice@example.com" {
+		t.Fatalf("Unmarshal = %+v", stored)
+	}
+
+	legacy := &testLegacyUser{}
+	row.Set("userid", 7)
+	if err := row.Unmarshal(legacy); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if legacy.UserID != 7 || legacy.Email != "alice@example.com" {
+		t.Fatalf("Unmarshal = %+v", legacy)
+	}
+}
@@ -0,0 +1,312 @@
+package sqlite
+
+import (
+	goctx "context"
+	"testing"
+
+	"github.com/noxyicm/wsf/cache"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Opens users table with metadata kept in struct
+// In-memory database has a single connection, so table operations
+// running outside of the transaction would block on it
+func newTransactionTable(t *testing.T) (db.Adapter, *db.DefaultTable) {
+	t.Helper()
+
+	adp, tbl := newMemoryTable(t)
+	tbl.SetMetadataCacheInStruct(true)
+	if err := tbl.SetupPrimaryKey(); err != nil {
+		t.Fatalf("SetupPrimaryKey: %v", err)
+	}
+
+	return adp, tbl
+}
+
+func countUsers(t *testing.T, ctx context.Context, tbl *db.DefaultTable) int {
+	t.Helper()
+
+	rowset, err := tbl.FetchAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	return rowset.Count()
+}
+
+func TestRunInTransactionCommitAndRollback(t *testing.T) {
+	adp, tbl := newTransactionTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	err := db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		if db.ContextTransaction(ctx) != tx {
+			t.Fatal("transaction is not bound to context")
+		}
+
+		_, err := tbl.Insert(ctx, map[string]interface{}{"name": "alice"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction: %v", err)
+	}
+
+	if db.ContextTransaction(ctx) != nil {
+		t.Fatal("transaction is left bound to context")
+	}
+
+	failure := errors.New("failure")
+	err = db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "bob"}); err != nil {
+			return err
+		}
+
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("RunInTransaction = %v, want %v", err, failure)
+	}
+
+	if n := countUsers(t, ctx, tbl); n != 1 {
+		t.Fatalf("%d users stored, want 1", n)
+	}
+}
+
+func TestRunInTransactionNestedSavepoint(t *testing.T) {
+	adp, tbl := newTransactionTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	err := db.RunInTransaction(ctx, adp, func(outer db.Transaction) error {
+		if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "alice"}); err != nil {
+			return err
+		}
+
+		err := db.RunInTransaction(ctx, adp, func(inner db.Transaction) error {
+			if inner == outer {
+				t.Fatal("nested transaction is the outer one")
+			}
+
+			if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "bob"}); err != nil {
+				return err
+			}
+
+			return errors.New("rolled back to savepoint")
+		})
+		if err == nil {
+			t.Fatal("nested RunInTransaction = nil, want error")
+		}
+
+		if db.ContextTransaction(ctx) != outer {
+			t.Fatal("outer transaction is not restored in context")
+		}
+
+		return db.RunInTransaction(ctx, adp, func(inner db.Transaction) error {
+			_, err := tbl.Insert(ctx, map[string]interface{}{"name": "carol"})
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction: %v", err)
+	}
+
+	rowset, err := tbl.FetchAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	names := make([]string, 0)
+	for i := 0; i < rowset.Count(); i++ {
+		names = append(names, rowset.GetOffset(i).GetString("name"))
+	}
+
+	if len(names) != 2 || names[0] != "alice" || names[1] != "carol" {
+		t.Fatalf("stored users = %v, want [alice carol]", names)
+	}
+}
+
+func TestRunInTransactionRetry(t *testing.T) {
+	adp, tbl := newTransactionTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	options := adp.GetOptions().Transaction
+	options.MaxRetries = 2
+	options.RetryDelay = 1
+	options.MaxRetryDelay = 1
+
+	attempts := 0
+	err := db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		attempts++
+		if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "alice"}); err != nil {
+			return err
+		}
+
+		if attempts == 1 {
+			return errors.New("ERROR: restart transaction (SQLSTATE 40001)")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Fatalf("fn ran %d times, want 2", attempts)
+	}
+
+	if n := countUsers(t, ctx, tbl); n != 1 {
+		t.Fatalf("%d users stored, want 1", n)
+	}
+
+	attempts = 0
+	err = db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		attempts++
+		return errors.New("ERROR: deadlock detected (SQLSTATE 40P01)")
+	})
+	if err == nil || attempts != options.MaxRetries+1 {
+		t.Fatalf("RunInTransaction = %v after %d attempts, want error after %d", err, attempts, options.MaxRetries+1)
+	}
+
+	attempts = 0
+	err = db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		attempts++
+		return errors.New("ERROR: duplicate key (SQLSTATE 23505)")
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("RunInTransaction = %v after %d attempts, want error without retry", err, attempts)
+	}
+}
+
+// Query cache counting invalidations
+type clearCountCache struct {
+	cleared int
+}
+
+func (c *clearCountCache) Init(options *cache.Config) (bool, error)              { return true, nil }
+func (c *clearCountCache) Enabled() bool                                         { return true }
+func (c *clearCountCache) Load(id string, testCacheValidity bool) ([]byte, bool) { return nil, false }
+func (c *clearCountCache) Read(id string, object interface{}, testCacheValidity bool) bool {
+	return false
+}
+func (c *clearCountCache) Test(id string) bool { return false }
+func (c *clearCountCache) Save(data []byte, id string, tags []string, specificLifetime int64) bool {
+	return true
+}
+func (c *clearCountCache) Write(object interface{}, id string, tags []string, specificLifetime int64) bool {
+	return true
+}
+func (c *clearCountCache) Remove(id string) bool { return true }
+func (c *clearCountCache) Clear(mode int64, tags []string) bool {
+	c.cleared++
+	return true
+}
+func (c *clearCountCache) Remember(id string, tags []string, specificLifetime int64, fn func() ([]byte, error)) ([]byte, error) {
+	return fn()
+}
+func (c *clearCountCache) RememberObject(id string, object interface{}, tags []string, specificLifetime int64, fn func() (interface{}, error)) error {
+	return nil
+}
+func (c *clearCountCache) Error() error { return nil }
+
+func TestRunInTransactionDefersQueryCacheInvalidation(t *testing.T) {
+	adp, tbl := newTransactionTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	qc := &clearCountCache{}
+	tbl.SetQueryCache(qc)
+
+	err := db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "alice"}); err != nil {
+			return err
+		}
+
+		return db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+			if _, err := tbl.Update(ctx, map[string]interface{}{"email": "a@example.com"}, map[string]interface{}{"name = ?": "alice"}); err != nil {
+				return err
+			}
+
+			if qc.cleared != 0 {
+				t.Fatalf("query cache invalidated %d times before commit", qc.cleared)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction: %v", err)
+	}
+
+	if qc.cleared != 2 {
+		t.Fatalf("query cache invalidated %d times after commit, want 2", qc.cleared)
+	}
+
+	qc.cleared = 0
+	err = db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "bob"}); err != nil {
+			return err
+		}
+
+		return errors.New("failure")
+	})
+	if err == nil || qc.cleared != 0 {
+		t.Fatalf("RunInTransaction = %v, query cache invalidated %d times after roll back", err, qc.cleared)
+	}
+
+	if _, err := tbl.Insert(ctx, map[string]interface{}{"name": "carol"}); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	if qc.cleared != 1 {
+		t.Fatalf("query cache invalidated %d times outside of transaction, want 1", qc.cleared)
+	}
+}
+
+func TestDbWritesInvalidateQueryCache(t *testing.T) {
+	adp, tbl := newTransactionTable(t)
+	ctx, _ := context.NewContext(goctx.Background())
+
+	qc := &clearCountCache{}
+	db.SetDefaultQueryCache(qc)
+	t.Cleanup(func() { db.SetDefaultQueryCache(nil) })
+
+	if _, err := db.Insert(ctx, "users", map[string]interface{}{"name": "alice"}); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	if _, err := db.Execute(ctx, db.CreateUpdate().Table("users").Set("email", "a@example.com").Where("name = ?", "alice")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if qc.cleared != 2 {
+		t.Fatalf("query cache invalidated %d times, want 2", qc.cleared)
+	}
+
+	qc.cleared = 0
+	err := db.RunInTransaction(ctx, adp, func(tx db.Transaction) error {
+		if _, err := db.Insert(ctx, "users", map[string]interface{}{"name": "bob"}); err != nil {
+			return err
+		}
+
+		if _, err := db.Execute(ctx, db.CreateDelete().From("users").Where("name = ?", "alice")); err != nil {
+			return err
+		}
+
+		if qc.cleared != 0 {
+			t.Fatalf("query cache invalidated %d times before commit", qc.cleared)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction: %v", err)
+	}
+
+	if qc.cleared != 2 {
+		t.Fatalf("query cache invalidated %d times after commit, want 2", qc.cleared)
+	}
+
+	if n := countUsers(t, ctx, tbl); n != 1 {
+		t.Fatalf("%d users stored, want 1", n)
+	}
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Data manipulation constants
+const (
+	SQLInsertInto = "INSERT INTO"
+	SQLValues     = "VALUES"
+	SQLUpdate     = "UPDATE"
+	SQLSet        = "SET"
+	SQLDeleteFrom = "DELETE FROM"
+)
+
+// Statement is a renderable sql statement
+type Statement interface {
+	Assemble() string
+	Binds() []interface{}
+	Err() error
+}
+
+// statementWhere is a WHERE clause holder shared by data manipulation statements
+type statementWhere struct {
+	adapter Adapter
+	parts   []*statementCondition
+	errors  []error
+}
+
+// statementCondition is a condition of WHERE clause
+type statementCondition struct {
+	condition string
+	value     interface{}
+	and       bool
+}
+
+// add adds condition joined by AND or OR
+func (w *statementWhere) add(condition string, value interface{}, b bool) {
+	if w.adapter == nil {
+		w.errors = append(w.errors, errors.New("Adapter is not set"))
+		return
+	}
+
+	w.parts = append(w.parts, &statementCondition{condition: condition, value: value, and: b})
+}
+
+// render renders WHERE clause
+// Binds of subqueries used as condition values are collected into binds
+func (w *statementWhere) render(sql string, binds *placeholders) string {
+	if len(w.parts) == 0 {
+		return sql
+	}
+
+	parts := make([]string, 0, len(w.parts))
+	for _, part := range w.parts {
+		condition := w.adapter.QuoteIdentifier(part.condition, true)
+		if part.value != nil {
+			condition = w.adapter.QuoteInto(condition, statementValue(w.adapter, part.value, binds, &w.errors), -1)
+		}
+
+		cond := ""
+		if len(parts) > 0 {
+			if part.and {
+				cond = SQLAnd + " "
+			} else {
+				cond = SQLOr + " "
+			}
+		}
+
+		parts = append(parts, cond+"("+condition+")")
+	}
+
+	return sql + " " + SQLWhere + " " + strings.Join(parts, " ")
+}
+
+// Replaces subqueries used as condition value with expression of their sql
+func statementValue(adapter Adapter, value interface{}, binds *placeholders, errs *[]error) interface{} {
+	switch v := value.(type) {
+	case Select:
+		sql, err := binds.embed(adapter, v)
+		if err != nil {
+			*errs = append(*errs, err)
+		}
+
+		return NewExpr("(" + sql + ")")
+
+	case []Select:
+		subs := make([]string, 0, len(v))
+		for _, sub := range v {
+			sql, err := binds.embed(adapter, sub)
+			if err != nil {
+				*errs = append(*errs, err)
+			}
+
+			subs = append(subs, "("+sql+")")
+		}
+
+		return NewExpr(strings.Join(subs, ", "))
+	}
+
+	return value
+}
+
+// err pops last acuired error
+func (w *statementWhere) err() error {
+	if len(w.errors) > 0 {
+		err := w.errors[0]
+		w.errors = append([]error{}, w.errors[1:]...)
+		return err
+	}
+
+	return nil
+}
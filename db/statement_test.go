@@ -0,0 +1,168 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertBatch(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	stmt := NewInsert(adp).Into("users").Rows([]map[string]interface{}{
+		{"name": "alice", "age": 30},
+		{"name": "bob", "age": NewExpr("DEFAULT")},
+	})
+
+	want := `INSERT INTO "users" ("age", "name") VALUES ($1, $2), (DEFAULT, $3)`
+	if sql := stmt.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+
+	if err := stmt.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if binds := stmt.Binds(); !reflect.DeepEqual(binds, []interface{}{30, "alice", "bob"}) {
+		t.Errorf("Binds() = %v", binds)
+	}
+}
+
+func TestInsertSubqueryValueBinds(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	sub := newTestSelect(adp).From("groups", "id").Where("name = ?", nil).AddBind("name", "admins")
+	stmt := NewInsert(adp).Into("members").Columns("group_id", "user_id", "role").Values(map[string]interface{}{
+		"user_id":  7,
+		"group_id": sub,
+		"role":     "owner",
+	})
+
+	want := `INSERT INTO "members" ("group_id", "user_id", "role") VALUES ((SELECT "groups"."id" FROM "groups" WHERE ("name" = $1)), $2, $3)`
+	if sql := stmt.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+
+	if binds := stmt.Binds(); !reflect.DeepEqual(binds, []interface{}{"admins", 7, "owner"}) {
+		t.Errorf("Binds() = %v", binds)
+	}
+}
+
+func TestInsertUpsert(t *testing.T) {
+	cases := []struct {
+		name        string
+		constructor func(*AdapterConfig) (Adapter, error)
+		target      []string
+		update      interface{}
+		sql         string
+		binds       []interface{}
+	}{
+		{
+			name:        "postgres update columns",
+			constructor: NewPostgresAdapter,
+			target:      []string{"id"},
+			update:      []string{"name"},
+			sql:         `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+			binds:       []interface{}{1, "alice"},
+		},
+		{
+			name:        "postgres update values",
+			constructor: NewPostgresAdapter,
+			target:      []string{"id"},
+			update:      map[string]interface{}{"name": "bob"},
+			sql:         `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = $3`,
+			binds:       []interface{}{1, "alice", "bob"},
+		},
+		{
+			name:        "postgres ignore",
+			constructor: NewPostgresAdapter,
+			sql:         `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			binds:       []interface{}{1, "alice"},
+		},
+		{
+			name:        "mysql update columns",
+			constructor: NewMySQLAdapter,
+			update:      []string{"name"},
+			sql:         "INSERT INTO `users` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+			binds:       []interface{}{1, "alice"},
+		},
+		{
+			name:        "mysql ignore",
+			constructor: NewMySQLAdapter,
+			target:      []string{"id"},
+			sql:         "INSERT INTO `users` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`",
+			binds:       []interface{}{1, "alice"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stmt := NewInsert(newTestAdapter(t, c.constructor)).Into("users").Values(map[string]interface{}{"id": 1, "name": "alice"}).OnConflict(c.target, c.update)
+			if sql := stmt.Assemble(); sql != c.sql {
+				t.Errorf("Assemble()\n got: %s\nwant: %s", sql, c.sql)
+			}
+
+			if err := stmt.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+
+			if binds := stmt.Binds(); !reflect.DeepEqual(binds, c.binds) {
+				t.Errorf("Binds() = %v, want %v", binds, c.binds)
+			}
+		})
+	}
+}
+
+func TestInsertUpsertWithoutTarget(t *testing.T) {
+	cases := []struct {
+		name        string
+		constructor func(*AdapterConfig) (Adapter, error)
+		update      interface{}
+	}{
+		{"postgres update", NewPostgresAdapter, []string{"name"}},
+		{"mysql ignore", NewMySQLAdapter, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stmt := NewInsert(newTestAdapter(t, c.constructor)).Into("users").Values(map[string]interface{}{"id": 1, "name": "alice"}).OnConflict(nil, c.update)
+			stmt.Assemble()
+			if err := stmt.Err(); err == nil {
+				t.Fatal("Err() = nil, want error of missing conflict target")
+			}
+		})
+	}
+}
+
+func TestUpdateBindsOrder(t *testing.T) {
+	adp := newTestAdapter(t, NewPostgresAdapter)
+
+	total := newTestSelect(adp).From("orders", NewExpr("SUM(total)")).Where("status = ?", nil).AddBind("status", "paid")
+	blocked := newTestSelect(adp).From("bans", "user_id").Where("reason = ?", nil).AddBind("reason", "spam")
+
+	stmt := NewUpdate(adp).Table("users").Set("spent", total).Set("name", "alice").Where("id NOT IN ?", blocked).Where("age > ?", 18)
+
+	want := `UPDATE "users" SET "spent" = (SELECT SUM(total) FROM "orders" WHERE ("status" = $1)), "name" = $2 WHERE ("id" NOT IN (SELECT "bans"."user_id" FROM "bans" WHERE ("reason" = $3))) AND ("age" > 18)`
+	if sql := stmt.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+
+	if binds := stmt.Binds(); !reflect.DeepEqual(binds, []interface{}{"paid", "alice", "spam"}) {
+		t.Errorf("Binds() = %v", binds)
+	}
+}
+
+func TestDeleteSubqueryBinds(t *testing.T) {
+	adp := newTestAdapter(t, NewMySQLAdapter)
+
+	expired := newTestSelect(adp).From("sessions", "user_id").Where("expires < ?", nil).AddBind("expires", 100)
+	stmt := NewDelete(adp).From("tokens").Where("user_id IN ?", expired).OrWhere("revoked = ?", 1)
+
+	want := "DELETE FROM `tokens` WHERE (`user_id` IN (SELECT `sessions`.`user_id` FROM `sessions` WHERE (`expires` < ?))) OR (`revoked` = 1)"
+	if sql := stmt.Assemble(); sql != want {
+		t.Errorf("Assemble()\n got: %s\nwant: %s", sql, want)
+	}
+
+	if binds := stmt.Binds(); !reflect.DeepEqual(binds, []interface{}{100}) {
+		t.Errorf("Binds() = %v", binds)
+	}
+}
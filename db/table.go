@@ -68,6 +68,9 @@ type Table interface {
 	GetMetadataCache() cache.Interface
 	SetMetadataCacheInStruct(flag bool) Table
 	IsMetadataCacheInClass() bool
+	SetQueryCache(queryCache interface{}) Table
+	GetQueryCache() cache.Interface
+	SetQueryCacheLifetime(lifetime int64) Table
 	Setup() error
 	SetupAdapter() error
 	SetupTableName()
@@ -79,11 +82,18 @@ type Table interface {
 	Select(withFromPart bool) Select
 	Insert(ctx context.Context, data map[string]interface{}) (int, error)
 	IsIdentity(column string) bool
+	InsertStruct(ctx context.Context, input interface{}) (int, error)
 	Update(ctx context.Context, data map[string]interface{}, cond map[string]interface{}) (bool, error)
-	//CascadeUpdate(parentTable string, oldPrimaryKey map[string]string, newPrimaryKey map[string]string) (int, error)
+	UpdateStruct(ctx context.Context, input interface{}, cond map[string]interface{}) (bool, error)
+	CascadeUpdate(ctx context.Context, parentTable string, oldPrimaryKey map[string]interface{}, newPrimaryKey map[string]interface{}) (int, error)
 	Delete(ctx context.Context, cond map[string]interface{}) (bool, error)
-	//CascadeDelete(parentTable string, primaryKey map[string]interface{}) (int, error)
+	CascadeDelete(ctx context.Context, parentTable string, primaryKey map[string]interface{}) (int, error)
+	GetDependentTables() map[string]TableReference
+	DependentTable(ruleKey string) (Table, error)
+	Reference(table string, ruleKey string) (TableReference, error)
 	Find(ctx context.Context, args ...interface{}) (Rowset, error)
+	FetchAll(ctx context.Context, cond interface{}) (Rowset, error)
+	FetchRow(ctx context.Context, cond interface{}) (Row, error)
 }
 
 // NewTable creates a new table from given type and options
@@ -161,6 +171,8 @@ type DefaultTable struct {
 	Metadata              map[string]*TableColumn
 	MetadataCache         cache.Interface
 	MetadataCacheInStruct bool
+	QueryCache            cache.Interface
+	QueryCacheLifetime    int64
 	RowType               string
 	RowsetType            string
 }
@@ -168,6 +180,7 @@ type DefaultTable struct {
 // SetOptions sets object options
 func (t *DefaultTable) SetOptions(options *TableConfig) Table {
 	t.SetAdapter(options.Adapter)
+	t.QueryCacheLifetime = options.QueryCacheLifetime
 	t.Options = options
 	return t
 }
@@ -180,18 +193,26 @@ func (t *DefaultTable) SetRowType(tp string) Table {
 
 // GetRowType returns representaion row type
 func (t *DefaultTable) GetRowType() string {
+	if t.RowType == "" {
+		return TYPEDefaultRow
+	}
+
 	return t.RowType
 }
 
 // SetRowsetType sets a table's representation rowset type
 func (t *DefaultTable) SetRowsetType(tp string) Table {
-	t.RowType = tp
+	t.RowsetType = tp
 	return t
 }
 
 // GetRowsetType returns representaion rowset type
 func (t *DefaultTable) GetRowsetType() string {
-	return t.RowType
+	if t.RowsetType == "" {
+		return TYPEDefaultRowset
+	}
+
+	return t.RowsetType
 }
 
 // AddReference adds a reference to the reference map
@@ -210,6 +231,10 @@ func (t *DefaultTable) AddReference(ruleKey string, columns []string, refTable s
 		reference.OnUpdate = onUpdate
 	}
 
+	if t.ReferenceMap == nil {
+		t.ReferenceMap = make(map[string]TableReference)
+	}
+
 	t.ReferenceMap[ruleKey] = reference
 
 	return t
@@ -226,7 +251,7 @@ func (t *DefaultTable) Reference(table string, ruleKey string) (TableReference,
 	refMap := t.getReferenceMapNormalized()
 	if ruleKey != "" {
 		if _, ok := refMap[ruleKey]; !ok {
-			return TableReference{}, errors.Errorf("No reference rule '%s' from table '%s' to table '%s'", ruleKey, t.Options.DefinitionName, table)
+			return TableReference{}, errors.Errorf("No reference rule '%s' from table '%s' to table '%s'", ruleKey, t.Name, table)
 		}
 
 		if refMap[ruleKey].Table != table {
@@ -242,7 +267,7 @@ func (t *DefaultTable) Reference(table string, ruleKey string) (TableReference,
 		}
 	}
 
-	return TableReference{}, errors.Errorf("No reference from table '%s' to table '%s'", t.Options.DefinitionName, table)
+	return TableReference{}, errors.Errorf("No reference from table '%s' to table '%s'", t.Name, table)
 }
 
 // SetDependentTables sets a dependant tables map
@@ -252,10 +277,40 @@ func (t *DefaultTable) SetDependentTables(dependentTables map[string]TableRefere
 }
 
 // GetDependentTables returns a map of dependant tables
+// Each reference describes dependent table (Table) and its columns (Columns)
+// referencing this table columns (RefColumns)
 func (t *DefaultTable) GetDependentTables() map[string]TableReference {
 	return t.DependentTables
 }
 
+// DependentTable returns a table object for dependent table reference
+// The returned table references this table by the same rule
+func (t *DefaultTable) DependentTable(ruleKey string) (Table, error) {
+	ref, ok := t.DependentTables[ruleKey]
+	if !ok {
+		return nil, errors.Errorf("No dependent table rule '%s' for table '%s'", ruleKey, t.Name)
+	}
+
+	depTable := NewEmptyDefaultTable(nil)
+	depTable.Adapter = t.Adapter
+	depTable.Name = ref.Table
+	depTable.RowType = t.RowType
+	depTable.RowsetType = t.RowsetType
+	depTable.ReferenceMap = map[string]TableReference{
+		ruleKey: {
+			Columns:    ref.Columns,
+			Table:      t.Name,
+			RefColumns: ref.RefColumns,
+			OnDelete:   ref.OnDelete,
+			OnUpdate:   ref.OnUpdate,
+		},
+	}
+	depTable.DependentTables = make(map[string]TableReference)
+	depTable.SetupTableName()
+
+	return depTable, nil
+}
+
 // SetDefaultValues set the default values for the table object
 func (t *DefaultTable) SetDefaultValues(defaultValues map[string]interface{}) Table {
 	for defaultName, defaultValue := range defaultValues {
@@ -305,6 +360,27 @@ func (t *DefaultTable) IsMetadataCacheInClass() bool {
 	return t.MetadataCacheInStruct
 }
 
+// SetQueryCache sets the cache for results of table fetches
+func (t *DefaultTable) SetQueryCache(queryCache interface{}) Table {
+	t.QueryCache, _ = SetupMetadataCache(queryCache)
+	return t
+}
+
+// GetQueryCache returns the cache for results of table fetches or the default query cache
+func (t *DefaultTable) GetQueryCache() cache.Interface {
+	if t.QueryCache == nil {
+		return defaultQueryCache
+	}
+
+	return t.QueryCache
+}
+
+// SetQueryCacheLifetime makes all table fetches cached for lifetime seconds, zero disables it
+func (t *DefaultTable) SetQueryCacheLifetime(lifetime int64) Table {
+	t.QueryCacheLifetime = lifetime
+	return t
+}
+
 // Setup is turnkey for initialization of a table object
 // Calls other protected methods for individual tasks, to make it easier
 // for a subclass to override part of the setup logic
@@ -321,8 +397,8 @@ func (t *DefaultTable) Setup() error {
 func (t *DefaultTable) SetupAdapter() error {
 	if t.Adapter == nil {
 		t.Adapter = GetDefaultAdapter()
-		if t.Adapter != nil {
-			return errors.Errorf("No adapter found for '%t'", reflect.TypeOf(t))
+		if t.Adapter == nil {
+			return errors.Errorf("No adapter found for '%s'", t.Name)
 		}
 	}
 
@@ -456,6 +532,7 @@ func (t *DefaultTable) Info() TableInfo {
 // Select returns an instance of a dbselect.Interface object
 func (t *DefaultTable) Select(withFromPart bool) Select {
 	slct := NewSelectFromConfig(Options().Select)
+	slct.SetAdapter(t.GetAdapter())
 	if withFromPart == SelectWithFormPart {
 		tableSpec := t.Name
 		if t.Schema != "" {
@@ -476,6 +553,13 @@ func (t *DefaultTable) Insert(ctx context.Context, data map[string]interface{})
 	primary := t.Primary
 	pkIdentity := primary[t.Identity]
 
+	// Work on a copy, caller's data is left untouched
+	values := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		values[k] = v
+	}
+	data = values
+
 	if _, ok := data[pkIdentity]; ok {
 		if data[pkIdentity] == nil || data[pkIdentity] == `` {
 			delete(data, pkIdentity)
@@ -491,16 +575,59 @@ func (t *DefaultTable) Insert(ctx context.Context, data map[string]interface{})
 		tableSpec = t.Schema + "." + tableSpec
 	}
 
-	_, err := t.Adapter.Insert(ctx, tableSpec, data)
+	id, err := contextInsert(ctx, t.Adapter, tableSpec, data)
 	if err != nil {
 		return 0, err
 	}
 
-	//if _, ok := data[pkIdentity]; !ok && t.Sequence != "" {
-	//	data[pkIdentity] = t.Adapter.LastInsertID()
-	//}
+	invalidateContextQueryCache(ctx, t.Adapter, t.GetQueryCache(), tableSpec)
+
+	if _, ok := data[pkIdentity]; !ok {
+		data[pkIdentity] = id
+	}
+
+	if v, err := utils.InterfaceToInt(data[pkIdentity]); err == nil {
+		return v, nil
+	}
+
+	return id, nil
+}
+
+// InsertStruct inserts a new row from struct fields mapped by db tags
+// Empty identity column is left to database and generated value is set back into struct
+func (t *DefaultTable) InsertStruct(ctx context.Context, input interface{}) (int, error) {
+	data, err := StructToMap(input)
+	if err != nil {
+		return 0, err
+	}
 
-	return data[pkIdentity].(int), nil
+	if err := t.SetupPrimaryKey(); err != nil {
+		return 0, err
+	}
+
+	pkIdentity := t.Primary[t.Identity]
+	if v, ok := data[pkIdentity]; ok && (v == nil || isEmptyValue(reflect.ValueOf(v))) {
+		delete(data, pkIdentity)
+	}
+
+	id, err := t.Insert(ctx, data)
+	if err != nil {
+		return 0, err
+	}
+
+	if rv := reflect.ValueOf(input); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		if m, err := MappingOf(input); err == nil {
+			if f, ok := m.Field(pkIdentity); ok {
+				if fv, ok := fieldByIndex(rv.Elem(), f.Index, false); ok && isEmptyValue(fv) {
+					if err := assignValue(fv, id); err != nil {
+						return id, errors.Wrapf(err, "Unable to set generated identity into field of type '%s'", f.Type)
+					}
+				}
+			}
+		}
+	}
+
+	return id, nil
 }
 
 // IsIdentity check if the provided column is an identity of the table
@@ -523,37 +650,91 @@ func (t *DefaultTable) Update(ctx context.Context, data map[string]interface{},
 		tableSpec = t.Schema + "." + tableSpec
 	}
 
-	return t.Adapter.Update(ctx, tableSpec, data, cond)
+	ok, err := contextUpdate(ctx, t.Adapter, tableSpec, data, cond)
+	if err == nil {
+		invalidateContextQueryCache(ctx, t.Adapter, t.GetQueryCache(), tableSpec)
+	}
+
+	return ok, err
+}
+
+// UpdateStruct updates rows from struct fields mapped by db tags
+// If condition is empty rows are matched by primary key values of the struct,
+// which are read from mapped fields even if they are read only
+func (t *DefaultTable) UpdateStruct(ctx context.Context, input interface{}, cond map[string]interface{}) (bool, error) {
+	data, err := StructToMap(input)
+	if err != nil {
+		return false, err
+	}
+
+	if len(cond) == 0 {
+		if err := t.SetupPrimaryKey(); err != nil {
+			return false, err
+		}
+
+		m, err := MappingOf(input)
+		if err != nil {
+			return false, err
+		}
+
+		rv := reflect.Indirect(reflect.ValueOf(input))
+		cond = make(map[string]interface{})
+		for _, col := range t.Primary {
+			var fv reflect.Value
+			f, ok := m.Field(col)
+			if ok {
+				fv, ok = fieldByIndex(rv, f.Index, false)
+			}
+
+			if !ok {
+				return false, errors.Errorf("Unable to update table '%s': primary key column '%s' is not mapped", t.Name, col)
+			}
+
+			value, err := columnValue(fv)
+			if err != nil {
+				return false, errors.Wrapf(err, "Unable to update table '%s': invalid value of primary key column '%s'", t.Name, col)
+			}
+
+			cond[t.Adapter.QuoteIdentifier(col, true)+" = ?"] = value
+			delete(data, f.Column)
+		}
+	}
+
+	return t.Update(ctx, data, cond)
 }
 
 // CascadeUpdate called by a row object for the parent table's class during save() method
-func (t *DefaultTable) CascadeUpdate(ctx context.Context, parentTable string, oldPrimaryKey map[string]string, newPrimaryKey map[string]string) (int, error) {
-	t.SetupMetadata()
+func (t *DefaultTable) CascadeUpdate(ctx context.Context, parentTable string, oldPrimaryKey map[string]interface{}, newPrimaryKey map[string]interface{}) (int, error) {
 	rowsAffected := 0
 	for _, ref := range t.getReferenceMapNormalized() {
 		if ref.Table == parentTable && ref.OnUpdate != "" {
-			switch ref.OnUpdate {
-			case Cascade:
-				newRefs := map[string]interface{}{}
-				where := map[string]interface{}{}
-				for i := 0; i < len(ref.Columns); i++ {
-					col := t.Adapter.FoldCase(ref.Columns[i])
-					refCol := t.Adapter.FoldCase(ref.RefColumns[i])
-					if v, ok := newPrimaryKey[refCol]; ok {
-						newRefs[col] = v
-					}
-
-					where[t.Adapter.QuoteIdentifier(col, true)+" = ?"] = oldPrimaryKey[refCol]
+			where := map[string]interface{}{}
+			newRefs := map[string]interface{}{}
+			for i := 0; i < len(ref.Columns); i++ {
+				col := t.Adapter.FoldCase(ref.Columns[i])
+				refCol := t.Adapter.FoldCase(ref.RefColumns[i])
+				if v, ok := newPrimaryKey[refCol]; ok {
+					newRefs[col] = v
 				}
 
-				ok, err := t.Adapter.Update(ctx, ref.Table, newRefs, where)
-				if err == nil {
+				where[t.Adapter.QuoteIdentifier(col, true)+" = ?"] = oldPrimaryKey[refCol]
+			}
+
+			switch ref.OnUpdate {
+			case Cascade:
+				ok, err := t.Update(ctx, newRefs, where)
+				if err != nil {
 					return rowsAffected, err
 				}
 
 				if ok {
 					rowsAffected++
 				}
+
+			case Restrict:
+				if err := t.restrict(ctx, where, parentTable); err != nil {
+					return rowsAffected, err
+				}
 			}
 		}
 	}
@@ -570,13 +751,18 @@ func (t *DefaultTable) Delete(ctx context.Context, cond map[string]interface{})
 			return false, err
 		}
 
-		if !resultSet.IsEmpty() {
-			for resultSet.Next() {
-				/*row := resultSet.Get()
-				// Execute cascading deletes against dependent tables
-				for _, tbl := range depTables {
-					tbl.CascadeDelete(t.Name, row.PrimaryKey())
-				}*/
+		for resultSet.Next() {
+			row := resultSet.Get()
+			// Execute cascading deletes against dependent tables
+			for ruleKey := range depTables {
+				depTable, err := t.DependentTable(ruleKey)
+				if err != nil {
+					return false, err
+				}
+
+				if _, err := depTable.CascadeDelete(ctx, t.Name, row.PrimaryKey()); err != nil {
+					return false, err
+				}
 			}
 		}
 	}
@@ -586,57 +772,57 @@ func (t *DefaultTable) Delete(ctx context.Context, cond map[string]interface{})
 		tableSpec = t.Schema + "." + tableSpec
 	}
 
-	return t.Adapter.Delete(ctx, tableSpec, cond)
+	ok, err := contextDelete(ctx, t.Adapter, tableSpec, cond)
+	if err == nil {
+		invalidateContextQueryCache(ctx, t.Adapter, t.GetQueryCache(), tableSpec)
+	}
+
+	return ok, err
 }
 
 // CascadeDelete called by parent table's object during delete() method
 func (t *DefaultTable) CascadeDelete(ctx context.Context, parentTable string, primaryKey map[string]interface{}) (int, error) {
-	t.SetupMetadata()
-
 	rowsAffected := 0
 	for _, ref := range t.getReferenceMapNormalized() {
 		if ref.Table == parentTable && ref.OnDelete != "" {
 			cond := make(map[string]interface{})
+			for i := 0; i < len(ref.Columns); i++ {
+				col := t.Adapter.FoldCase(ref.Columns[i])
+				refCol := t.Adapter.FoldCase(ref.RefColumns[i])
+				cond[t.Adapter.QuoteIdentifier(col, true)+" = ?"] = primaryKey[refCol]
+			}
 
-			if ref.OnDelete == Cascade || ref.OnDelete == CascadeRecurse {
-				for i := 0; i < len(ref.Columns); i++ {
-					col := t.Adapter.FoldCase(ref.Columns[i])
-					refCol := t.Adapter.FoldCase(ref.RefColumns[i])
-					cond[t.Adapter.QuoteIdentifier(col, true)+" = ?"] = primaryKey[refCol]
+			var ok bool
+			var err error
+			switch ref.OnDelete {
+			case Cascade:
+				ok, err = contextDelete(ctx, t.Adapter, t.tableSpec(), cond)
+				if err == nil {
+					invalidateContextQueryCache(ctx, t.Adapter, t.GetQueryCache(), t.tableSpec())
 				}
-			}
 
-			if ref.OnDelete == CascadeRecurse {
-				// Execute cascading deletes against dependent tables
-				depTables := t.GetDependentTables()
-				if len(depTables) > 0 {
-					/*for _, tbl := range depTables {
-						resultSet, err := t.FetchAll(cond)
-						for resultSet.Next() {
-							row := resultSet.Get()
-
-							ok, err := d.Adapter.CascadeDelete(t.Name, row.PrimaryKey())
-							if err != nil {
-								return rowsAffected, err
-							}
-
-							if ok {
-								rowsAffected++
-							}
-						}
-					}*/
+			case CascadeRecurse:
+				// Delete through the table to execute cascading deletes against its dependent tables
+				ok, err = t.Delete(ctx, cond)
+
+			case SetNull:
+				nulls := make(map[string]interface{})
+				for _, col := range ref.Columns {
+					nulls[t.Adapter.FoldCase(col)] = nil
 				}
+
+				ok, err = t.Update(ctx, nulls, cond)
+
+			case Restrict:
+				err = t.restrict(ctx, cond, parentTable)
 			}
 
-			if ref.OnDelete == Cascade || ref.OnDelete == CascadeRecurse {
-				ok, err := t.Adapter.Delete(ctx, t.Name, cond)
-				if err != nil {
-					return rowsAffected, err
-				}
+			if err != nil {
+				return rowsAffected, err
+			}
 
-				if ok {
-					rowsAffected++
-				}
+			if ok {
+				rowsAffected++
 			}
 		}
 	}
@@ -644,6 +830,24 @@ func (t *DefaultTable) CascadeDelete(ctx context.Context, parentTable string, pr
 	return rowsAffected, nil
 }
 
+// Returns an error if any row matches the condition
+func (t *DefaultTable) restrict(ctx context.Context, cond map[string]interface{}, parentTable string) error {
+	slct := t.Select(true)
+	t.where(slct, cond)
+	slct.Limit(1, 0)
+
+	row, err := contextQueryRow(ctx, t.Adapter, slct)
+	if err != nil {
+		return err
+	}
+
+	if len(row) > 0 {
+		return errors.Errorf("Cannot change row of table '%s' as it is referenced from table '%s'", parentTable, t.Name)
+	}
+
+	return nil
+}
+
 // Find fetches rows by primary key.  The argument specifies one or more primary
 // key value(s).  To find multiple rows by primary key, the argument must
 // be an array.
@@ -746,9 +950,12 @@ func (t *DefaultTable) fetchAll(ctx context.Context, cond interface{}, order int
 	case Select:
 		slct = cond.(Select)
 
-	case string:
+	case string, map[string]interface{}:
 		slct = t.Select(true)
 		t.where(slct, cond)
+
+	default:
+		slct = t.Select(true)
 	}
 
 	if order != nil {
@@ -759,7 +966,11 @@ func (t *DefaultTable) fetchAll(ctx context.Context, cond interface{}, order int
 		slct.Limit(count, offset)
 	}
 
-	rows, err := t.GetAdapter().Query(ctx, slct)
+	if t.QueryCacheLifetime > 0 && !slct.IsCached() {
+		slct.Cache(t.QueryCacheLifetime)
+	}
+
+	rows, err := cachedQuery(ctx, t.GetQueryCache(), t.GetAdapter(), slct)
 	if err != nil {
 		return NewEmptyRowset(t.GetRowsetType()), err
 	}
@@ -770,11 +981,13 @@ func (t *DefaultTable) fetchAll(ctx context.Context, cond interface{}, order int
 	defer rows.Close()*/
 
 	rstConfig := &RowsetConfig{
-		Type:  t.GetRowsetType(),
-		Table: t.Name,
+		Type:   t.GetRowsetType(),
+		Table:  t.Name,
+		Stored: true,
 		Row: &RowConfig{
-			Type:  t.GetRowType(),
-			Table: t.Name,
+			Type:   t.GetRowType(),
+			Table:  t.Name,
+			Stored: true,
 		},
 	}
 
@@ -783,6 +996,7 @@ func (t *DefaultTable) fetchAll(ctx context.Context, cond interface{}, order int
 		return NewEmptyRowset(t.GetRowsetType()), errors.Wrap(err, "Table fetchAll Error")
 	}
 
+	rst.SetTable(t)
 	if err = rst.PopulateMap(rows); err != nil {
 		return NewEmptyRowset(t.GetRowsetType()), errors.Wrap(err, "Table fetchAll Error")
 	}
@@ -825,9 +1039,12 @@ func (t *DefaultTable) fetchRow(ctx context.Context, cond interface{}, order int
 	case Select:
 		slct = cond.(Select)
 
-	case string:
+	case string, map[string]interface{}:
 		slct = t.Select(true)
 		t.where(slct, cond)
+
+	default:
+		slct = t.Select(true)
 	}
 
 	if order != nil {
@@ -840,12 +1057,33 @@ func (t *DefaultTable) fetchRow(ctx context.Context, cond interface{}, order int
 		slct.Limit(1, 0)
 	}
 
-	row, err := t.GetAdapter().QueryRow(ctx, slct)
+	if t.QueryCacheLifetime > 0 && !slct.IsCached() {
+		slct.Cache(t.QueryCacheLifetime)
+	}
+
+	row, err := cachedQueryRow(ctx, t.GetQueryCache(), t.GetAdapter(), slct)
 	if err != nil {
 		return t.CreateRow(nil, DefaultNone), err
 	}
 
-	return t.CreateRow(row, DefaultNone), nil
+	if len(row) == 0 {
+		return t.CreateRow(nil, DefaultNone), nil
+	}
+
+	config := &RowConfig{
+		Type:   t.GetRowType(),
+		Table:  t.Name,
+		Data:   row,
+		Stored: true,
+	}
+
+	stored, err := NewRow(t.GetRowType(), config)
+	if err != nil {
+		return t.CreateRow(nil, DefaultNone), errors.Wrap(err, "Table fetchRow Error")
+	}
+
+	stored.SetTable(t)
+	return stored, nil
 }
 
 // CreateRow fetches a new blank row (not from the database)
@@ -970,6 +1208,15 @@ func (t *DefaultTable) order(slct Select, order interface{}) Select {
 	return slct
 }
 
+// Returns table name prefixed with schema if any
+func (t *DefaultTable) tableSpec() string {
+	if t.Schema != "" {
+		return t.Schema + "." + t.Name
+	}
+
+	return t.Name
+}
+
 func (t *DefaultTable) getReferenceMapNormalized() map[string]TableReference {
 	return t.ReferenceMap
 }
@@ -6,20 +6,21 @@ import (
 
 // TableConfig defines set of table variables
 type TableConfig struct {
-	Type            string
-	Adapter         string
-	Definition      map[string]interface{}
-	DefinitionName  string
-	Primary         []string
-	Identity        int64
-	Schema          string
-	Name            string
-	ReferenceMap    map[string]interface{}
-	DependentTables map[string]interface{}
-	DefaultSource   string
-	DefaultValues   map[string]interface{}
-	RowsetType      string
-	RowType         string
+	Type               string
+	Adapter            string
+	Definition         map[string]interface{}
+	DefinitionName     string
+	Primary            []string
+	Identity           int64
+	Schema             string
+	Name               string
+	ReferenceMap       map[string]interface{}
+	DependentTables    map[string]interface{}
+	DefaultSource      string
+	DefaultValues      map[string]interface{}
+	RowsetType         string
+	RowType            string
+	QueryCacheLifetime int64
 }
 
 // Populate populates Config values using given Config source
@@ -27,12 +27,17 @@ func init() {
 type Transaction interface {
 	SetAdapter(adpt Adapter) error
 	SetContext(ctx context.Context) error
+	Adapter() Adapter
+	Begin() (Transaction, error)
 	Commit() error
 	Rollback() error
 	Update(table string, data map[string]interface{}, cond map[string]interface{}) (bool, error)
 	Insert(table string, data map[string]interface{}) (int, error)
 	Delete(table string, cond map[string]interface{}) (bool, error)
 	Query(dbs Select) ([]map[string]interface{}, error)
+	QueryRow(dbs Select) (map[string]interface{}, error)
+	Exec(query string, binds ...interface{}) (sql.Result, error)
+	Execute(stmt Statement) (sql.Result, error)
 }
 
 // NewTransaction creates a new rowset
@@ -50,10 +55,13 @@ func RegisterTransaction(transactionType string, handler func(*sql.Tx) (Transact
 }
 
 // DefaultTransaction represents database transaction
+// A transaction started by Begin of another transaction is bound to a savepoint
 type DefaultTransaction struct {
-	Tx  *sql.Tx
-	Adp Adapter
-	Ctx context.Context
+	Tx        *sql.Tx
+	Adp       Adapter
+	Ctx       context.Context
+	savepoint string
+	level     int
 }
 
 // SetAdapter sets the transaction sql adapter
@@ -68,8 +76,49 @@ func (t *DefaultTransaction) SetContext(ctx context.Context) error {
 	return nil
 }
 
+// Adapter returns the transaction sql adapter
+func (t *DefaultTransaction) Adapter() Adapter {
+	return t.Adp
+}
+
+// Begin starts a nested transaction using a savepoint
+func (t *DefaultTransaction) Begin() (Transaction, error) {
+	nested, err := t.nested()
+	if err != nil {
+		return nil, err
+	}
+
+	return nested, nil
+}
+
+// Creates a savepoint and returns a transaction bound to it
+func (t *DefaultTransaction) nested() (*DefaultTransaction, error) {
+	level := t.level + 1
+	savepoint := "sp_" + strconv.Itoa(level)
+	if _, err := t.Exec("SAVEPOINT " + savepoint); err != nil {
+		return nil, errors.Wrap(err, "Unable to create savepoint")
+	}
+
+	return &DefaultTransaction{
+		Tx:        t.Tx,
+		Adp:       t.Adp,
+		Ctx:       t.Ctx,
+		savepoint: savepoint,
+		level:     level,
+	}, nil
+}
+
 // Commit commits a transaction
+// Nested transaction releases its savepoint
 func (t *DefaultTransaction) Commit() error {
+	if t.savepoint != "" {
+		if _, err := t.Exec("RELEASE SAVEPOINT " + t.savepoint); err != nil {
+			return errors.Wrap(err, "Transaction commit error")
+		}
+
+		return nil
+	}
+
 	err := t.Tx.Commit()
 	if err != nil {
 		return errors.Wrap(err, "Transaction commit error")
@@ -79,7 +128,16 @@ func (t *DefaultTransaction) Commit() error {
 }
 
 // Rollback roll back a transaction
+// Nested transaction rolls back to its savepoint
 func (t *DefaultTransaction) Rollback() error {
+	if t.savepoint != "" {
+		if _, err := t.Exec("ROLLBACK TO SAVEPOINT " + t.savepoint); err != nil {
+			return errors.Wrap(err, "Transaction roll back error")
+		}
+
+		return nil
+	}
+
 	err := t.Tx.Rollback()
 	if err != nil {
 		return errors.Wrap(err, "Transaction roll back error")
@@ -128,7 +186,9 @@ func (t *DefaultTransaction) Insert(table string, data map[string]interface{}) (
 	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(t.Adp.GetOptions().QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := t.Adp.Profiler().Start(t.Ctx, query, binds, table)
 	result, err := stmt.ExecContext(qctx, binds...)
+	qp.EndResult(result, err)
 	if err != nil {
 		stmt.Close()
 		return 0, errors.Wrap(err, "Database insert Error")
@@ -190,7 +250,9 @@ func (t *DefaultTransaction) Update(table string, data map[string]interface{}, c
 	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(t.Adp.GetOptions().QueryTimeout)*time.Second)
 	defer cancel()
 
+	qp := t.Adp.Profiler().Start(t.Ctx, query, binds, table)
 	rows, err := stmt.QueryContext(qctx, binds...)
+	qp.End(0, err)
 	if err != nil {
 		stmt.Close()
 		return false, err
@@ -228,13 +290,13 @@ func (t *DefaultTransaction) Delete(table string, cond map[string]interface{}) (
 	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(t.Adp.GetOptions().QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := stmt.QueryContext(qctx)
+	qp := t.Adp.Profiler().Start(t.Ctx, sql, nil, table)
+	result, err := stmt.ExecContext(qctx)
+	qp.EndResult(result, err)
+	stmt.Close()
 	if err != nil {
-		stmt.Close()
 		return false, err
 	}
-	stmt.Close()
-	rows.Close()
 
 	return true, nil
 }
@@ -252,13 +314,76 @@ func (t *DefaultTransaction) Query(dbs Select) ([]map[string]interface{}, error)
 	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(t.Adp.GetOptions().QueryTimeout)*time.Second)
 	defer cancel()
 
-	rows, err := t.Tx.QueryContext(qctx, dbs.Assemble(), dbs.Binds()...)
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := t.Adp.Profiler().Start(t.Ctx, query, binds, StatementTable(dbs))
+	rows, err := t.Tx.QueryContext(qctx, query, binds...)
 	if err != nil {
+		qp.End(0, err)
 		return nil, errors.Wrap(err, "Database query Error")
 	}
 	defer rows.Close()
 
-	return t.Adp.PrepareRowset(rows)
+	data, err := t.Adp.PrepareRowset(rows)
+	qp.End(int64(len(data)), err)
+	return data, err
+}
+
+// QueryRow runs a query and returns the first row
+func (t *DefaultTransaction) QueryRow(dbs Select) (map[string]interface{}, error) {
+	if t.Adp == nil {
+		return nil, errors.New("Database adapter is not set")
+	}
+
+	if err := dbs.Err(); err != nil {
+		return nil, errors.Wrap(err, "Database query Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(t.Adp.GetOptions().QueryTimeout)*time.Second)
+	defer cancel()
+
+	query, binds := dbs.Assemble(), dbs.Binds()
+	qp := t.Adp.Profiler().Start(t.Ctx, query, binds, StatementTable(dbs))
+	rows, err := t.Tx.QueryContext(qctx, query, binds...)
+	if err != nil {
+		qp.End(0, err)
+		return nil, errors.Wrap(err, "Database query Error")
+	}
+	defer rows.Close()
+
+	row, err := t.Adp.PrepareRow(rows)
+	var count int64
+	if row != nil {
+		count = 1
+	}
+
+	qp.End(count, err)
+	return row, err
+}
+
+// Exec executes a raw query inside transaction
+func (t *DefaultTransaction) Exec(query string, binds ...interface{}) (sql.Result, error) {
+	var qp *QueryProfile
+	if t.Adp != nil {
+		qp = t.Adp.Profiler().Start(t.Ctx, query, binds, "")
+	}
+
+	result, err := t.Tx.ExecContext(t.Ctx, query, binds...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "Database exec Error")
+	}
+
+	return result, nil
+}
+
+// Execute executes an insert, update or delete statement inside transaction
+func (t *DefaultTransaction) Execute(stmt Statement) (sql.Result, error) {
+	query := stmt.Assemble()
+	if err := stmt.Err(); err != nil {
+		return nil, err
+	}
+
+	return t.Exec(query, stmt.Binds()...)
 }
 
 // NewDefaultTransaction creates default transaction
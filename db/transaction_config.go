@@ -10,6 +10,9 @@ type TransactionConfig struct {
 	Type           string
 	IsolationLevel sql.IsolationLevel
 	ReadOnly       bool
+	MaxRetries     int
+	RetryDelay     int
+	MaxRetryDelay  int
 }
 
 // Populate populates Config values using given Config source
@@ -26,6 +29,9 @@ func (c *TransactionConfig) Defaults() error {
 	c.Type = "default"
 	c.IsolationLevel = sql.LevelDefault
 	c.ReadOnly = false
+	c.MaxRetries = 5
+	c.RetryDelay = 50
+	c.MaxRetryDelay = 2000
 	return nil
 }
 
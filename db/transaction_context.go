@@ -0,0 +1,266 @@
+package db
+
+import (
+	"database/sql"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// ContextTransaction returns the transaction active in context or nil
+func ContextTransaction(ctx context.Context) Transaction {
+	if ctx == nil {
+		return nil
+	}
+
+	if tx, ok := ctx.Value(contextKeyTransaction).(Transaction); ok {
+		return tx
+	}
+
+	return nil
+}
+
+// RunInTransaction runs fn inside a transaction bound to context
+// Table and row operations of the same adapter made with this context run inside the transaction.
+// If context already has an active transaction fn runs in a nested transaction using a savepoint,
+// otherwise the transaction is retried with backoff while adapter reports the error as retryable
+func RunInTransaction(ctx context.Context, adp Adapter, fn func(tx Transaction) error) error {
+	if adp == nil {
+		return errors.New("Database adapter is not set")
+	}
+
+	if parent := activeTransaction(ctx, adp); parent != nil {
+		tx, err := parent.Begin()
+		if err != nil {
+			return err
+		}
+
+		return runTransaction(ctx, tx, fn)
+	}
+
+	options := adp.GetOptions().Transaction
+	for attempt := 0; ; attempt++ {
+		tx, err := adp.BeginTransaction(ctx)
+		if err != nil {
+			return errors.Wrap(err, "Unable to begin transaction")
+		}
+
+		err = runRootTransaction(ctx, tx, fn)
+		if err == nil || attempt >= options.MaxRetries || !adp.IsRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "Transaction retry canceled")
+
+		case <-time.After(retryDelay(options, attempt)):
+		}
+	}
+}
+
+// Runs top level transaction and actions deferred until it commits
+func runRootTransaction(ctx context.Context, tx Transaction, fn func(tx Transaction) error) error {
+	hooks := &commitHooks{}
+	prev := ctx.Value(contextKeyCommitHooks)
+	ctx.SetValue(contextKeyCommitHooks, hooks)
+	defer ctx.SetValue(contextKeyCommitHooks, prev)
+
+	if err := runTransaction(ctx, tx, fn); err != nil {
+		return err
+	}
+
+	hooks.run()
+	return nil
+}
+
+// Runs fn with tx bound to context then commits or rolls back tx
+func runTransaction(ctx context.Context, tx Transaction, fn func(tx Transaction) error) (err error) {
+	prev := ctx.Value(contextKeyTransaction)
+	ctx.SetValue(contextKeyTransaction, tx)
+	defer func() {
+		ctx.SetValue(contextKeyTransaction, prev)
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// commitHooks holds actions deferred until the transaction run by RunInTransaction commits
+type commitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// Adds an action to run after commit
+func (h *commitHooks) add(fn func()) {
+	h.mu.Lock()
+	h.hooks = append(h.hooks, fn)
+	h.mu.Unlock()
+}
+
+// Runs actions in order they were added
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// Returns actions deferred until commit of the transaction active in context on adapter
+func contextCommitHooks(ctx context.Context, adp Adapter) *commitHooks {
+	if activeTransaction(ctx, adp) == nil {
+		return nil
+	}
+
+	hooks, _ := ctx.Value(contextKeyCommitHooks).(*commitHooks)
+	return hooks
+}
+
+// Returns exponential backoff delay with jitter for retry attempt
+func retryDelay(options *TransactionConfig, attempt int) time.Duration {
+	delay := time.Duration(options.RetryDelay) * time.Millisecond
+	max := time.Duration(options.MaxRetryDelay) * time.Millisecond
+	for i := 0; i < attempt && (max <= 0 || delay < max); i++ {
+		delay *= 2
+	}
+
+	if max > 0 && delay > max {
+		delay = max
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// Returns the transaction active in context if it runs on adapter
+func activeTransaction(ctx context.Context, adp Adapter) Transaction {
+	tx := ContextTransaction(ctx)
+	if tx == nil || adp == nil {
+		return nil
+	}
+
+	if primaryAdapter(tx.Adapter()) != primaryAdapter(adp) {
+		return nil
+	}
+
+	return tx
+}
+
+// Returns the adapter transactions are started on
+func primaryAdapter(adp Adapter) Adapter {
+	if ra, ok := adp.(*ReplicationAdapter); ok {
+		return ra.Primary()
+	}
+
+	return adp
+}
+
+// Returns SQLSTATE code of a database error if driver provides it
+func errorSQLState(err error) string {
+	for err != nil {
+		if serr, ok := err.(interface{ SQLState() string }); ok {
+			return serr.SQLState()
+		}
+
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	if err == nil {
+		return ""
+	}
+
+	msg := err.Error()
+	if i := strings.Index(msg, "SQLSTATE "); i >= 0 && len(msg) >= i+14 {
+		return msg[i+9 : i+14]
+	}
+
+	if strings.Contains(msg, "restart transaction") {
+		return "40001"
+	}
+
+	return ""
+}
+
+// Runs a query inside the active transaction of context or on adapter
+func contextQuery(ctx context.Context, adp Adapter, dbs Select) ([]map[string]interface{}, error) {
+	if tx := activeTransaction(ctx, adp); tx != nil {
+		return tx.Query(dbs)
+	}
+
+	return adp.Query(ctx, dbs)
+}
+
+// Runs a single row query inside the active transaction of context or on adapter
+func contextQueryRow(ctx context.Context, adp Adapter, dbs Select) (map[string]interface{}, error) {
+	if tx := activeTransaction(ctx, adp); tx != nil {
+		return tx.QueryRow(dbs)
+	}
+
+	return adp.QueryRow(ctx, dbs)
+}
+
+// Inserts a row inside the active transaction of context or on adapter
+func contextInsert(ctx context.Context, adp Adapter, table string, data map[string]interface{}) (int, error) {
+	if tx := activeTransaction(ctx, adp); tx != nil {
+		return tx.Insert(table, data)
+	}
+
+	return adp.Insert(ctx, table, data)
+}
+
+// Updates rows inside the active transaction of context or on adapter
+func contextUpdate(ctx context.Context, adp Adapter, table string, data map[string]interface{}, cond map[string]interface{}) (bool, error) {
+	if tx := activeTransaction(ctx, adp); tx != nil {
+		return tx.Update(table, data, cond)
+	}
+
+	return adp.Update(ctx, table, data, cond)
+}
+
+// Executes a statement inside the active transaction of context or on adapter
+func contextExecute(ctx context.Context, adp Adapter, stmt Statement) (sql.Result, error) {
+	if tx := activeTransaction(ctx, adp); tx != nil {
+		return tx.Execute(stmt)
+	}
+
+	return adp.Execute(ctx, stmt)
+}
+
+// Deletes rows inside the active transaction of context or on adapter
+func contextDelete(ctx context.Context, adp Adapter, table string, cond map[string]interface{}) (bool, error) {
+	if tx := activeTransaction(ctx, adp); tx != nil {
+		return tx.Delete(table, cond)
+	}
+
+	return adp.Delete(ctx, table, cond)
+}
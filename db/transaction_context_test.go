@@ -0,0 +1,60 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+type sqlStateError struct {
+	state string
+}
+
+func (e *sqlStateError) Error() string {
+	return "database error"
+}
+
+func (e *sqlStateError) SQLState() string {
+	return e.state
+}
+
+func TestErrorSQLState(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"driver error", &sqlStateError{"40001"}, "40001"},
+		{"wrapped driver error", errors.Wrap(&sqlStateError{"40P01"}, "Database query Error"), "40P01"},
+		{"message", errors.New("ERROR: could not serialize access (SQLSTATE 40001)"), "40001"},
+		{"cockroach restart", errors.New("restart transaction: TransactionRetryWithProtoRefreshError"), "40001"},
+		{"other", errors.New("syntax error"), ""},
+		{"nil", nil, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if state := errorSQLState(c.err); state != c.want {
+				t.Errorf("errorSQLState() = %q, want %q", state, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	options := &TransactionConfig{RetryDelay: 10, MaxRetryDelay: 40}
+
+	for attempt, base := range []time.Duration{10, 20, 40, 40, 40} {
+		base *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if delay := retryDelay(options, attempt); delay < base/2 || delay > base {
+				t.Fatalf("retryDelay(%d) = %s, want between %s and %s", attempt, delay, base/2, base)
+			}
+		}
+	}
+
+	if delay := retryDelay(&TransactionConfig{}, 3); delay != 0 {
+		t.Errorf("retryDelay() without delay = %s, want 0", delay)
+	}
+}
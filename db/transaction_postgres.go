@@ -0,0 +1,149 @@
+package db
+
+import (
+	goctx "context"
+	"database/sql"
+	"time"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+const (
+	// TYPEPostgresTransaction is a type id of postgres transaction class
+	TYPEPostgresTransaction = "postgres"
+)
+
+func init() {
+	RegisterTransaction(TYPEPostgresTransaction, NewPostgresTransaction)
+}
+
+// PostgresTransaction represents PostgreSQL database transaction
+type PostgresTransaction struct {
+	DefaultTransaction
+}
+
+// Begin starts a nested transaction using a savepoint
+func (t *PostgresTransaction) Begin() (Transaction, error) {
+	nested, err := t.nested()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresTransaction{DefaultTransaction: *nested}, nil
+}
+
+// Insert inserts new row into table
+func (t *PostgresTransaction) Insert(table string, data map[string]interface{}) (int, error) {
+	adp, err := t.adapter()
+	if err != nil {
+		return 0, err
+	}
+
+	query, binds, identity, err := adp.insertSQL(t.Ctx, table, data)
+	if err != nil {
+		return 0, errors.Wrap(err, "Database insert Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(adp.GetOptions().QueryTimeout)*time.Second)
+	defer cancel()
+
+	qp := adp.Profiler().Start(t.Ctx, query, binds, table)
+	if identity == "" {
+		result, err := t.Tx.ExecContext(qctx, query, binds...)
+		qp.EndResult(result, err)
+		if err != nil {
+			return 0, errors.Wrap(err, "Database insert Error")
+		}
+
+		return 0, nil
+	}
+
+	var id int64
+	err = t.Tx.QueryRowContext(qctx, query, binds...).Scan(&id)
+	qp.End(1, err)
+	if err != nil {
+		return 0, errors.Wrap(err, "Database insert Error")
+	}
+
+	return int(id), nil
+}
+
+// Update updates rows into table be condition
+func (t *PostgresTransaction) Update(table string, data map[string]interface{}, cond map[string]interface{}) (bool, error) {
+	adp, err := t.adapter()
+	if err != nil {
+		return false, err
+	}
+
+	query, binds, err := adp.updateSQL(table, data, cond)
+	if err != nil {
+		return false, errors.Wrap(err, "Database update Error")
+	}
+
+	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(adp.GetOptions().QueryTimeout)*time.Second)
+	defer cancel()
+
+	qp := adp.Profiler().Start(t.Ctx, query, binds, table)
+	result, err := t.Tx.ExecContext(qctx, query, binds...)
+	qp.EndResult(result, err)
+	if err != nil {
+		return false, errors.Wrap(err, "Database update Error")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return true, nil
+	}
+
+	return affected > 0, nil
+}
+
+// Delete removes rows from table
+func (t *PostgresTransaction) Delete(table string, cond map[string]interface{}) (bool, error) {
+	adp, err := t.adapter()
+	if err != nil {
+		return false, err
+	}
+
+	qctx, cancel := goctx.WithTimeout(t.Ctx, time.Duration(adp.GetOptions().QueryTimeout)*time.Second)
+	defer cancel()
+
+	query := adp.deleteSQL(table, cond)
+	qp := adp.Profiler().Start(t.Ctx, query, nil, table)
+	result, err := t.Tx.ExecContext(qctx, query)
+	qp.EndResult(result, err)
+	if err != nil {
+		return false, errors.Wrap(err, "Database delete Error")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return true, nil
+	}
+
+	return affected > 0, nil
+}
+
+// adapter returns transaction adapter as PostgreSQL adapter
+func (t *PostgresTransaction) adapter() (*Postgres, error) {
+	if t.Adp == nil {
+		return nil, errors.New("Database adapter is not set")
+	}
+
+	adp, ok := t.Adp.(*Postgres)
+	if !ok {
+		return nil, errors.Errorf("Postgres transaction requires PostgreSQL adapter, got '%T'", t.Adp)
+	}
+
+	return adp, nil
+}
+
+// NewPostgresTransaction creates PostgreSQL transaction
+func NewPostgresTransaction(tx *sql.Tx) (Transaction, error) {
+	return &PostgresTransaction{
+		DefaultTransaction: DefaultTransaction{
+			Tx:  tx,
+			Ctx: nil,
+		},
+	}, nil
+}
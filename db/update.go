@@ -0,0 +1,167 @@
+package db
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// UpdateStatement is an update statement interface
+type UpdateStatement interface {
+	SetAdapter(adapter Adapter) error
+	Table(table string) UpdateStatement
+	Set(col string, value interface{}) UpdateStatement
+	SetMap(data map[string]interface{}) UpdateStatement
+	Where(cond string, value interface{}) UpdateStatement
+	OrWhere(cond string, value interface{}) UpdateStatement
+	Binds() []interface{}
+	Err() error
+	Assemble() string
+	ToString() string
+}
+
+// DefaultUpdate is an update statement builder
+type DefaultUpdate struct {
+	Adapter Adapter
+	Name    string
+	Cols    []string
+	Data    map[string]interface{}
+	Bind    []interface{}
+	Errors  []error
+	where   *statementWhere
+}
+
+// SetAdapter sets the adapter interface to update object
+func (s *DefaultUpdate) SetAdapter(adapter Adapter) error {
+	s.Adapter = adapter
+	s.where.adapter = adapter
+	return nil
+}
+
+// Table sets the target table
+func (s *DefaultUpdate) Table(table string) UpdateStatement {
+	s.Name = table
+	return s
+}
+
+// Set sets a column value
+// Value may be a plain value, *SQLExpr or Select
+func (s *DefaultUpdate) Set(col string, value interface{}) UpdateStatement {
+	if _, ok := s.Data[col]; !ok {
+		s.Cols = append(s.Cols, col)
+	}
+
+	s.Data[col] = value
+	return s
+}
+
+// SetMap sets several column values
+func (s *DefaultUpdate) SetMap(data map[string]interface{}) UpdateStatement {
+	cols := make([]string, 0, len(data))
+	for col := range data {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	for _, col := range cols {
+		s.Set(col, data[col])
+	}
+
+	return s
+}
+
+// Where adds a WHERE condition to the statement by AND
+func (s *DefaultUpdate) Where(cond string, value interface{}) UpdateStatement {
+	s.where.add(cond, value, true)
+	return s
+}
+
+// OrWhere adds a WHERE condition to the statement by OR
+func (s *DefaultUpdate) OrWhere(cond string, value interface{}) UpdateStatement {
+	s.where.add(cond, value, false)
+	return s
+}
+
+// Binds returns binds of the last assembled statement
+func (s *DefaultUpdate) Binds() []interface{} {
+	return s.Bind
+}
+
+// Err pops last acuired error or nil if no errors
+func (s *DefaultUpdate) Err() error {
+	if err := s.where.err(); err != nil {
+		return err
+	}
+
+	if len(s.Errors) > 0 {
+		err := s.Errors[0]
+		s.Errors = append([]error{}, s.Errors[1:]...)
+		return err
+	}
+
+	return nil
+}
+
+// ToString converts update struct to string
+func (s *DefaultUpdate) ToString() string {
+	return s.Assemble()
+}
+
+// Assemble converts this object to an SQL UPDATE string
+func (s *DefaultUpdate) Assemble() string {
+	s.Bind = make([]interface{}, 0)
+	if s.Adapter == nil {
+		s.Errors = append(s.Errors, errors.New("Adapter is not set"))
+		return ""
+	}
+
+	if s.Name == "" {
+		s.Errors = append(s.Errors, errors.New("No table has been specified for the UPDATE statement"))
+		return ""
+	}
+
+	if len(s.Cols) == 0 {
+		s.Errors = append(s.Errors, errors.New("Nothing to update"))
+		return ""
+	}
+
+	binds := &placeholders{}
+	set := make([]string, 0, len(s.Cols))
+	for _, col := range s.Cols {
+		var value string
+		switch v := s.Data[col].(type) {
+		case *SQLExpr:
+			value = v.ToString()
+
+		case Select:
+			sql, err := binds.embed(s.Adapter, v)
+			if err != nil {
+				s.Errors = append(s.Errors, err)
+			}
+
+			value = "(" + sql + ")"
+
+		default:
+			value = binds.bind(v)
+		}
+
+		set = append(set, s.Adapter.QuoteIdentifier(col, true)+" = "+value)
+	}
+
+	sql := SQLUpdate + " " + s.Adapter.QuoteIdentifier(s.Name, true) + " " + SQLSet + " " + strings.Join(set, ", ")
+	sql, s.Bind = binds.resolve(s.Adapter, s.where.render(sql, binds), make([]interface{}, 0))
+	return sql
+}
+
+// NewUpdate creates a new update statement for adapter
+func NewUpdate(adapter Adapter) UpdateStatement {
+	return &DefaultUpdate{
+		Adapter: adapter,
+		Cols:    make([]string, 0),
+		Data:    make(map[string]interface{}),
+		Bind:    make([]interface{}, 0),
+		Errors:  make([]error, 0),
+		where:   &statementWhere{adapter: adapter, parts: make([]*statementCondition, 0), errors: make([]error, 0)},
+	}
+}
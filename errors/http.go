@@ -23,6 +23,11 @@ func (e *HTTPError) Code() int {
 	return e.code
 }
 
+// Cause returns the underlying error
+func (e *HTTPError) Cause() error {
+	return e.cause
+}
+
 // Format formats error
 func (e *HTTPError) Format(s fmt.State, verb rune) {
 	switch verb {
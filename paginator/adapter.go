@@ -0,0 +1,39 @@
+package paginator
+
+import (
+	"reflect"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Adapter is a paginator data source interface
+type Adapter interface {
+	Count(ctx context.Context) (int, error)
+	Items(ctx context.Context, offset int, limit int) (interface{}, error)
+}
+
+// NewAdapter creates a paginator adapter suitable for data
+// Supported data types are db.Select, db.Rowset, slices and arrays
+func NewAdapter(data interface{}) (Adapter, error) {
+	switch v := data.(type) {
+	case Adapter:
+		return v, nil
+
+	case db.Select:
+		return NewSelectAdapter(db.GetDefaultAdapter(), v), nil
+
+	case db.Rowset:
+		return NewRowsetAdapter(v), nil
+	}
+
+	if data != nil {
+		switch reflect.TypeOf(data).Kind() {
+		case reflect.Slice, reflect.Array:
+			return NewSliceAdapter(data)
+		}
+	}
+
+	return nil, errors.Errorf("No paginator adapter for data of type '%T'", data)
+}
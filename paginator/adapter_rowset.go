@@ -0,0 +1,43 @@
+package paginator
+
+import (
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+)
+
+// RowsetAdapter paginates an already fetched rowset
+type RowsetAdapter struct {
+	Rowset db.Rowset
+}
+
+// Count returns the number of rows in the rowset
+func (a *RowsetAdapter) Count(ctx context.Context) (int, error) {
+	return a.Rowset.Count(), nil
+}
+
+// Items returns rows of a page
+func (a *RowsetAdapter) Items(ctx context.Context, offset int, limit int) (interface{}, error) {
+	count := a.Rowset.Count()
+	if offset > count {
+		offset = count
+	}
+
+	end := offset + limit
+	if end > count {
+		end = count
+	}
+
+	rows := make([]db.Row, 0, end-offset)
+	for i := offset; i < end; i++ {
+		rows = append(rows, a.Rowset.GetOffset(i))
+	}
+
+	return rows, nil
+}
+
+// NewRowsetAdapter creates a new rowset adapter
+func NewRowsetAdapter(rowset db.Rowset) *RowsetAdapter {
+	return &RowsetAdapter{
+		Rowset: rowset,
+	}
+}
@@ -0,0 +1,155 @@
+package paginator
+
+import (
+	"strconv"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// RowCountColumn is the name of the column holding row count
+const RowCountColumn = "paginator_row_count"
+
+// SelectAdapter paginates a database select
+// Total item count is obtained with an automatic COUNT query
+type SelectAdapter struct {
+	Adapter     db.Adapter
+	Select      db.Select
+	CountSelect db.Select
+	rowCount    int
+	counted     bool
+}
+
+// SetCountSelect sets a custom select returning the total item count
+func (a *SelectAdapter) SetCountSelect(slct db.Select) *SelectAdapter {
+	a.CountSelect = slct
+	a.counted = false
+	return a
+}
+
+// Count returns the total number of rows in the result set
+func (a *SelectAdapter) Count(ctx context.Context) (int, error) {
+	if a.counted {
+		return a.rowCount, nil
+	}
+
+	if a.Adapter == nil {
+		return 0, errors.New("Paginator select adapter Error: Database adapter is not set")
+	}
+
+	slct := a.CountSelect
+	if slct == nil {
+		// Counted select is a copy, so limit of paginated select is kept
+		counted := a.Select.Clone().Limit(0, 0)
+		slct = a.Adapter.Select().FromAs(counted, "paginator_count", map[string]*db.SQLExpr{RowCountColumn: db.NewExpr("COUNT(*)")})
+	}
+
+	row, err := a.Adapter.QueryRow(ctx, slct)
+	if err != nil {
+		return 0, errors.Wrap(err, "Paginator select adapter Error")
+	}
+
+	count, err := rowCount(row)
+	if err != nil {
+		return 0, err
+	}
+
+	a.rowCount = count
+	a.counted = true
+	return a.rowCount, nil
+}
+
+// Items returns rows of a page
+func (a *SelectAdapter) Items(ctx context.Context, offset int, limit int) (interface{}, error) {
+	if a.Adapter == nil {
+		return nil, errors.New("Paginator select adapter Error: Database adapter is not set")
+	}
+
+	a.Select.Limit(limit, offset)
+	rows, err := a.Adapter.Query(ctx, a.Select)
+	if err != nil {
+		return nil, errors.Wrap(err, "Paginator select adapter Error")
+	}
+
+	return rows, nil
+}
+
+// TableSelectAdapter paginates a table select returning rowsets
+type TableSelectAdapter struct {
+	SelectAdapter
+	Table db.Table
+}
+
+// Items returns rowset of a page
+func (a *TableSelectAdapter) Items(ctx context.Context, offset int, limit int) (interface{}, error) {
+	a.Select.Limit(limit, offset)
+	rowset, err := a.Table.FetchAll(ctx, a.Select)
+	if err != nil {
+		return nil, errors.Wrap(err, "Paginator table select adapter Error")
+	}
+
+	return rowset, nil
+}
+
+// Extracts row count from count query result
+func rowCount(row map[string]interface{}) (int, error) {
+	var value interface{}
+	if v, ok := row[RowCountColumn]; ok {
+		value = v
+	} else {
+		for _, v := range row {
+			value = v
+			break
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+
+	case int:
+		return v, nil
+
+	case int32:
+		return int(v), nil
+
+	case int64:
+		return int(v), nil
+
+	case float64:
+		return int(v), nil
+
+	case []byte:
+		return strconv.Atoi(string(v))
+
+	case string:
+		return strconv.Atoi(v)
+	}
+
+	return 0, errors.Errorf("Paginator select adapter Error: Invalid row count type '%T'", value)
+}
+
+// NewSelectAdapter creates a new select adapter
+func NewSelectAdapter(adp db.Adapter, slct db.Select) *SelectAdapter {
+	return &SelectAdapter{
+		Adapter: adp,
+		Select:  slct,
+	}
+}
+
+// NewTableSelectAdapter creates a new table select adapter
+// If slct is nil the table default select is used
+func NewTableSelectAdapter(table db.Table, slct db.Select) *TableSelectAdapter {
+	if slct == nil {
+		slct = table.Select(true)
+	}
+
+	return &TableSelectAdapter{
+		SelectAdapter: SelectAdapter{
+			Adapter: table.GetAdapter(),
+			Select:  slct,
+		},
+		Table: table,
+	}
+}
@@ -0,0 +1,65 @@
+package paginator
+
+import (
+	goctx "context"
+	"strings"
+	"testing"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/db/sqlite"
+)
+
+// Opens in-memory database holding five items
+func newTestItems(t *testing.T) (db.Adapter, context.Context) {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{
+		"adapter": map[string]interface{}{
+			"type":   sqlite.TYPEAdapter,
+			"dbname": sqlite.Memory,
+		},
+	})
+
+	d, err := db.NewDB(cfg)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	db.SetInstance(d)
+	adp := d.Adapter()
+
+	ctx, _ := context.NewContext(goctx.Background())
+	for _, stmt := range []string{
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)`,
+		`INSERT INTO items (name) VALUES ('a'), ('b'), ('c'), ('d'), ('e')`,
+	} {
+		if _, err := adp.Exec(ctx, stmt); err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+	}
+
+	return adp, ctx
+}
+
+func TestSelectAdapterCountKeepsSelect(t *testing.T) {
+	adp, ctx := newTestItems(t)
+
+	slct := adp.Select().From("items", "*").Order("id").Limit(2, 1)
+	a := NewSelectAdapter(adp, slct)
+
+	count, err := a.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+
+	if count != 5 {
+		t.Errorf("Count() = %d, want 5", count)
+	}
+
+	if sql := slct.Assemble(); !strings.Contains(sql, "LIMIT 2 OFFSET 1") {
+		t.Errorf("Count changed limit of paginated select: %s", sql)
+	}
+}
@@ -0,0 +1,53 @@
+package paginator
+
+import (
+	"reflect"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// SliceAdapter paginates a slice or an array
+type SliceAdapter struct {
+	value reflect.Value
+}
+
+// Count returns the number of slice elements
+func (a *SliceAdapter) Count(ctx context.Context) (int, error) {
+	return a.value.Len(), nil
+}
+
+// Items returns a subslice of a page
+func (a *SliceAdapter) Items(ctx context.Context, offset int, limit int) (interface{}, error) {
+	count := a.value.Len()
+	if offset > count {
+		offset = count
+	}
+
+	end := offset + limit
+	if end > count {
+		end = count
+	}
+
+	return a.value.Slice(offset, end).Interface(), nil
+}
+
+// NewSliceAdapter creates a new slice adapter
+func NewSliceAdapter(data interface{}) (*SliceAdapter, error) {
+	value := reflect.ValueOf(data)
+	switch value.Kind() {
+	case reflect.Slice:
+
+	case reflect.Array:
+		slice := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), value.Len(), value.Len())
+		reflect.Copy(slice, value)
+		value = slice
+
+	default:
+		return nil, errors.Errorf("Paginator slice adapter Error: Invalid data type '%T'", data)
+	}
+
+	return &SliceAdapter{
+		value: value,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package paginator
+
+import (
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Config represents paginator configuration
+type Config struct {
+	ItemCountPerPage int
+	PageRange        int
+	ScrollingStyle   string
+}
+
+// Populate populates Config values using given Config source
+func (c *Config) Populate(cfg config.Config) error {
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	return c.Valid()
+}
+
+// Defaults sets configuration default values
+func (c *Config) Defaults() error {
+	c.ItemCountPerPage = 10
+	c.PageRange = 10
+	c.ScrollingStyle = TYPEScrollingSliding
+	return nil
+}
+
+// Valid validates the configuration
+func (c *Config) Valid() error {
+	if c.ItemCountPerPage < 1 {
+		return errors.New("Item count per page must be greater than zero")
+	}
+
+	if c.PageRange < 1 {
+		return errors.New("Page range must be greater than zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package paginator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Keyset paginates a database select by key columns instead of offsets
+// which keeps page queries fast on large tables
+type Keyset struct {
+	Adapter    db.Adapter
+	Select     db.Select
+	Columns    []string
+	Descending bool
+	Limit      int
+}
+
+// KeysetPage is a page fetched by keyset paginator
+type KeysetPage struct {
+	Items      []map[string]interface{}
+	NextCursor string
+	HasMore    bool
+}
+
+// Page returns rows following the cursor
+// An empty cursor returns the first page
+func (k *Keyset) Page(ctx context.Context, cursor string) (*KeysetPage, error) {
+	if k.Adapter == nil {
+		return nil, errors.New("Paginator keyset Error: Database adapter is not set")
+	}
+
+	if len(k.Columns) == 0 {
+		return nil, errors.New("Paginator keyset Error: Key columns are not set")
+	}
+
+	// Conditions of the page are added to a copy, so the keyset can fetch any number of pages
+	slct := k.Select.Clone()
+	if cursor != "" {
+		values, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(values) != len(k.Columns) {
+			return nil, errors.New("Paginator keyset Error: Cursor does not match key columns")
+		}
+
+		slct.Where(k.condition(values), nil)
+	}
+
+	direction := db.SQLAsc
+	if k.Descending {
+		direction = db.SQLDesc
+	}
+
+	for _, col := range k.Columns {
+		slct.Order(col + " " + direction)
+	}
+
+	slct.Limit(k.Limit+1, 0)
+	rows, err := k.Adapter.Query(ctx, slct)
+	if err != nil {
+		return nil, errors.Wrap(err, "Paginator keyset Error")
+	}
+
+	page := &KeysetPage{
+		Items: rows,
+	}
+
+	if len(rows) > k.Limit {
+		page.Items = rows[:k.Limit]
+		page.HasMore = true
+	}
+
+	if page.HasMore && len(page.Items) > 0 {
+		last := page.Items[len(page.Items)-1]
+		values := make([]interface{}, len(k.Columns))
+		for i, col := range k.Columns {
+			values[i] = last[keyName(col)]
+		}
+
+		page.NextCursor, err = EncodeCursor(values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// Builds a condition selecting rows after key values
+// Terms rely on AND precedence as the select wraps the whole condition
+func (k *Keyset) condition(values []interface{}) string {
+	op := " > ?"
+	if k.Descending {
+		op = " < ?"
+	}
+
+	terms := make([]string, len(k.Columns))
+	for i, col := range k.Columns {
+		and := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, k.Adapter.QuoteInto(k.Columns[j]+" = ?", values[j], 1))
+		}
+
+		and = append(and, k.Adapter.QuoteInto(col+op, values[i], 1))
+		terms[i] = strings.Join(and, " "+db.SQLAnd+" ")
+	}
+
+	return strings.Join(terms, " "+db.SQLOr+" ")
+}
+
+// Returns result key of a column
+func keyName(col string) string {
+	if pos := strings.LastIndex(col, "."); pos != -1 {
+		return col[pos+1:]
+	}
+
+	return col
+}
+
+// EncodeCursor encodes key values into an opaque cursor
+func EncodeCursor(values []interface{}) (string, error) {
+	normalized := make([]interface{}, len(values))
+	for i, value := range values {
+		if v, ok := value.([]byte); ok {
+			normalized[i] = string(v)
+		} else {
+			normalized[i] = value
+		}
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to encode paginator cursor")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes key values from a cursor
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid paginator cursor")
+	}
+
+	values := make([]interface{}, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, errors.Wrap(err, "Invalid paginator cursor")
+	}
+
+	for i, value := range values {
+		if number, ok := value.(json.Number); ok {
+			if v, err := number.Int64(); err == nil {
+				values[i] = v
+			} else if v, err := number.Float64(); err == nil {
+				values[i] = v
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// NewKeyset creates a new keyset paginator
+func NewKeyset(adp db.Adapter, slct db.Select, columns []string, limit int) *Keyset {
+	if limit < 1 {
+		limit = 10
+	}
+
+	return &Keyset{
+		Adapter: adp,
+		Select:  slct,
+		Columns: columns,
+		Limit:   limit,
+	}
+}
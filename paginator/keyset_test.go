@@ -0,0 +1,44 @@
+package paginator
+
+import "testing"
+
+func TestKeysetPages(t *testing.T) {
+	adp, ctx := newTestItems(t)
+
+	slct := adp.Select().From("items", "*")
+	sql := slct.Assemble()
+	k := &Keyset{Adapter: adp, Select: slct, Columns: []string{"id"}, Limit: 2}
+
+	names := ""
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		page, err := k.Page(ctx, cursor)
+		if err != nil {
+			t.Fatalf("Page: %v", err)
+		}
+
+		for _, item := range page.Items {
+			names += item["name"].(string)
+		}
+
+		if !page.HasMore {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	if names != "abcde" {
+		t.Fatalf("paged items = %q, want abcde", names)
+	}
+
+	if slct.Assemble() != sql {
+		t.Fatalf("Page changed keyset select: %s", slct.Assemble())
+	}
+
+	// Fetching the first page again is not affected by previous pages
+	page, err := k.Page(ctx, "")
+	if err != nil || len(page.Items) != 2 || page.Items[0]["name"] != "a" {
+		t.Fatalf("Page = %+v, %v", page, err)
+	}
+}
@@ -0,0 +1,274 @@
+package paginator
+
+import (
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Paginator splits a data source into pages
+type Paginator struct {
+	Options           *Config
+	adapter           Adapter
+	scrollingStyle    ScrollingStyle
+	currentPageNumber int
+	totalItemCount    int
+	counted           bool
+}
+
+// Pages represents a state of paginator suitable for rendering
+type Pages struct {
+	PageCount        int
+	ItemCountPerPage int
+	First            int
+	Current          int
+	Last             int
+	Previous         int
+	Next             int
+	PagesInRange     []int
+	FirstPageInRange int
+	LastPageInRange  int
+	CurrentItemCount int
+	TotalItemCount   int
+	FirstItemNumber  int
+	LastItemNumber   int
+}
+
+// Adapter returns paginator adapter
+func (p *Paginator) Adapter() Adapter {
+	return p.adapter
+}
+
+// SetCurrentPageNumber sets the current page number
+func (p *Paginator) SetCurrentPageNumber(page int) *Paginator {
+	p.currentPageNumber = page
+	return p
+}
+
+// CurrentPageNumber returns the current page number normalized to available pages
+func (p *Paginator) CurrentPageNumber(ctx context.Context) (int, error) {
+	return p.NormalizePageNumber(ctx, p.currentPageNumber)
+}
+
+// SetItemCountPerPage sets the number of items per page
+func (p *Paginator) SetItemCountPerPage(count int) *Paginator {
+	if count < 1 {
+		count = 1
+	}
+
+	p.Options.ItemCountPerPage = count
+	return p
+}
+
+// ItemCountPerPage returns the number of items per page
+func (p *Paginator) ItemCountPerPage() int {
+	return p.Options.ItemCountPerPage
+}
+
+// SetPageRange sets the number of pages shown in the range
+func (p *Paginator) SetPageRange(pageRange int) *Paginator {
+	if pageRange < 1 {
+		pageRange = 1
+	}
+
+	p.Options.PageRange = pageRange
+	return p
+}
+
+// PageRange returns the number of pages shown in the range
+func (p *Paginator) PageRange() int {
+	return p.Options.PageRange
+}
+
+// SetScrollingStyle sets the scrolling style by its type
+func (p *Paginator) SetScrollingStyle(styleType string) error {
+	style, err := NewScrollingStyle(styleType)
+	if err != nil {
+		return err
+	}
+
+	p.Options.ScrollingStyle = styleType
+	p.scrollingStyle = style
+	return nil
+}
+
+// ScrollingStyle returns the scrolling style
+func (p *Paginator) ScrollingStyle() ScrollingStyle {
+	return p.scrollingStyle
+}
+
+// TotalItemCount returns the total number of items in the data source
+func (p *Paginator) TotalItemCount(ctx context.Context) (int, error) {
+	if !p.counted {
+		count, err := p.adapter.Count(ctx)
+		if err != nil {
+			return 0, errors.Wrap(err, "Unable to count paginator items")
+		}
+
+		p.totalItemCount = count
+		p.counted = true
+	}
+
+	return p.totalItemCount, nil
+}
+
+// Count returns the number of pages
+func (p *Paginator) Count(ctx context.Context) (int, error) {
+	total, err := p.TotalItemCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return (total + p.Options.ItemCountPerPage - 1) / p.Options.ItemCountPerPage, nil
+}
+
+// NormalizePageNumber brings page number into the range of available pages
+func (p *Paginator) NormalizePageNumber(ctx context.Context, page int) (int, error) {
+	count, err := p.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if page > count {
+		page = count
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	return page, nil
+}
+
+// CurrentItems returns items of the current page
+func (p *Paginator) CurrentItems(ctx context.Context) (interface{}, error) {
+	page, err := p.CurrentPageNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ItemsByPage(ctx, page)
+}
+
+// ItemsByPage returns items of the page
+func (p *Paginator) ItemsByPage(ctx context.Context, page int) (interface{}, error) {
+	page, err := p.NormalizePageNumber(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := p.adapter.Items(ctx, (page-1)*p.Options.ItemCountPerPage, p.Options.ItemCountPerPage)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to fetch items of page %d", page)
+	}
+
+	return items, nil
+}
+
+// PagesInRange returns page numbers visible around the current page
+func (p *Paginator) PagesInRange(ctx context.Context) ([]int, error) {
+	count, err := p.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := p.CurrentPageNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return []int{}, nil
+	}
+
+	return p.scrollingStyle.Pages(page, count, p.Options.PageRange), nil
+}
+
+// Pages returns the paginator state
+func (p *Paginator) Pages(ctx context.Context) (*Pages, error) {
+	count, err := p.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := p.CurrentPageNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inRange, err := p.PagesInRange(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := &Pages{
+		PageCount:        count,
+		ItemCountPerPage: p.Options.ItemCountPerPage,
+		First:            1,
+		Current:          current,
+		Last:             count,
+		PagesInRange:     inRange,
+		TotalItemCount:   p.totalItemCount,
+	}
+
+	if count == 0 {
+		pages.First = 0
+		return pages, nil
+	}
+
+	if current > 1 {
+		pages.Previous = current - 1
+	}
+
+	if current < count {
+		pages.Next = current + 1
+	}
+
+	if len(inRange) > 0 {
+		pages.FirstPageInRange = inRange[0]
+		pages.LastPageInRange = inRange[len(inRange)-1]
+	}
+
+	pages.FirstItemNumber = (current-1)*p.Options.ItemCountPerPage + 1
+	pages.LastItemNumber = current * p.Options.ItemCountPerPage
+	if pages.LastItemNumber > pages.TotalItemCount {
+		pages.LastItemNumber = pages.TotalItemCount
+	}
+
+	pages.CurrentItemCount = pages.LastItemNumber - pages.FirstItemNumber + 1
+	return pages, nil
+}
+
+// NewPaginator creates a new paginator over data
+// Data may be an Adapter or any value supported by NewAdapter
+func NewPaginator(data interface{}, options config.Config) (*Paginator, error) {
+	cfg := &Config{}
+	cfg.Defaults()
+	if options != nil {
+		if err := cfg.Populate(options); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewPaginatorFromConfig(data, cfg)
+}
+
+// NewPaginatorFromConfig creates a new paginator over data from given config
+func NewPaginatorFromConfig(data interface{}, cfg *Config) (*Paginator, error) {
+	adapter, err := NewAdapter(data)
+	if err != nil {
+		return nil, err
+	}
+
+	style, err := NewScrollingStyle(cfg.ScrollingStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Paginator{
+		Options:           cfg,
+		adapter:           adapter,
+		scrollingStyle:    style,
+		currentPageNumber: 1,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package paginator
+
+import (
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Scrolling style types
+const (
+	TYPEScrollingAll     = "all"
+	TYPEScrollingElastic = "elastic"
+	TYPEScrollingJumping = "jumping"
+	TYPEScrollingSliding = "sliding"
+)
+
+var (
+	buildScrollingStyleHandlers = map[string]func() (ScrollingStyle, error){}
+)
+
+// ScrollingStyle decides which page numbers are shown around the current page
+type ScrollingStyle interface {
+	Pages(current int, pageCount int, pageRange int) []int
+}
+
+// NewScrollingStyle creates a new scrolling style specified by type
+func NewScrollingStyle(styleType string) (ScrollingStyle, error) {
+	if f, ok := buildScrollingStyleHandlers[styleType]; ok {
+		return f()
+	}
+
+	return nil, errors.Errorf("Unrecognized paginator scrolling style \"%v\"", styleType)
+}
+
+// RegisterScrollingStyle registers a handler for scrolling style creation
+func RegisterScrollingStyle(styleType string, handler func() (ScrollingStyle, error)) {
+	buildScrollingStyleHandlers[styleType] = handler
+}
+
+// Returns page numbers from lower to upper bounded by page count
+func pagesInRange(lower int, upper int, pageCount int) []int {
+	if lower < 1 {
+		lower = 1
+	}
+
+	if upper > pageCount {
+		upper = pageCount
+	}
+
+	pages := make([]int, 0)
+	for page := lower; page <= upper; page++ {
+		pages = append(pages, page)
+	}
+
+	return pages
+}
@@ -0,0 +1,18 @@
+package paginator
+
+func init() {
+	RegisterScrollingStyle(TYPEScrollingAll, NewScrollingAll)
+}
+
+// ScrollingAll returns every page
+type ScrollingAll struct{}
+
+// Pages returns page numbers
+func (s *ScrollingAll) Pages(current int, pageCount int, pageRange int) []int {
+	return pagesInRange(1, pageCount, pageCount)
+}
+
+// NewScrollingAll creates a new all scrolling style
+func NewScrollingAll() (ScrollingStyle, error) {
+	return &ScrollingAll{}, nil
+}
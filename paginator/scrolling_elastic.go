@@ -0,0 +1,30 @@
+package paginator
+
+func init() {
+	RegisterScrollingStyle(TYPEScrollingElastic, NewScrollingElastic)
+}
+
+// ScrollingElastic is a sliding style whose range grows
+// as the current page moves away from the edges
+type ScrollingElastic struct {
+	ScrollingSliding
+}
+
+// Pages returns page numbers
+func (s *ScrollingElastic) Pages(current int, pageCount int, pageRange int) []int {
+	originalRange := pageRange
+	pageRange = current*2 - 1
+
+	if originalRange+current-1 < pageRange {
+		pageRange = originalRange + current - 1
+	} else if originalRange+current-1 > pageCount {
+		pageRange = originalRange + pageCount - current
+	}
+
+	return s.ScrollingSliding.Pages(current, pageCount, pageRange)
+}
+
+// NewScrollingElastic creates a new elastic scrolling style
+func NewScrollingElastic() (ScrollingStyle, error) {
+	return &ScrollingElastic{}, nil
+}
@@ -0,0 +1,25 @@
+package paginator
+
+func init() {
+	RegisterScrollingStyle(TYPEScrollingJumping, NewScrollingJumping)
+}
+
+// ScrollingJumping moves the range by whole blocks of pages
+// when the current page leaves it
+type ScrollingJumping struct{}
+
+// Pages returns page numbers
+func (s *ScrollingJumping) Pages(current int, pageCount int, pageRange int) []int {
+	delta := current % pageRange
+	if delta == 0 {
+		delta = pageRange
+	}
+
+	offset := current - delta
+	return pagesInRange(offset+1, offset+pageRange, pageCount)
+}
+
+// NewScrollingJumping creates a new jumping scrolling style
+func NewScrollingJumping() (ScrollingStyle, error) {
+	return &ScrollingJumping{}, nil
+}
@@ -0,0 +1,33 @@
+package paginator
+
+func init() {
+	RegisterScrollingStyle(TYPEScrollingSliding, NewScrollingSliding)
+}
+
+// ScrollingSliding keeps the current page in the middle of the range
+// as long as possible
+type ScrollingSliding struct{}
+
+// Pages returns page numbers
+func (s *ScrollingSliding) Pages(current int, pageCount int, pageRange int) []int {
+	if pageRange > pageCount {
+		pageRange = pageCount
+	}
+
+	delta := (pageRange + 1) / 2
+	if current-delta > pageCount-pageRange {
+		return pagesInRange(pageCount-pageRange+1, pageCount, pageCount)
+	}
+
+	if current-delta < 0 {
+		delta = current
+	}
+
+	offset := current - delta
+	return pagesInRange(offset+1, offset+pageRange, pageCount)
+}
+
+// NewScrollingSliding creates a new sliding scrolling style
+func NewScrollingSliding() (ScrollingStyle, error) {
+	return &ScrollingSliding{}, nil
+}
@@ -0,0 +1,251 @@
+package cache
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/noxyicm/wsf/cache"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/controller/response"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/registry"
+	wsfhttp "github.com/noxyicm/wsf/service/http"
+)
+
+const (
+	// TYPECacheAdminMiddleware is a name of this middleware
+	TYPECacheAdminMiddleware = "cacheadmin"
+
+	// CacheAdminTokenHeader is a header carrying admin token
+	CacheAdminTokenHeader = "X-Cache-Admin-Token"
+)
+
+var cleaningModes = map[string]int64{
+	"all":         cache.CleaningModeAll,
+	"old":         cache.CleaningModeOld,
+	"matching":    cache.CleaningModeMatchingTag,
+	"notmatching": cache.CleaningModeNotMatchingTag,
+	"matchingany": cache.CleaningModeMatchingAnyTag,
+}
+
+func init() {
+	wsfhttp.RegisterMiddleware(TYPECacheAdminMiddleware, NewCacheAdminMiddleware)
+}
+
+// CacheAdminMiddleware exposes statistics and management of caches registered as resources
+//
+//	GET    {path}                  stats of all caches
+//	GET    {path}/{cache}          stats of cache
+//	GET    {path}/{cache}/ids      ids of stored items, optionally filtered by "tag" query values
+//	GET    {path}/{cache}/tags     tags of stored items
+//	GET    {path}/{cache}/items/id metadata of item
+//	DELETE {path}/{cache}/items/id removes item
+//	POST   {path}/{cache}/clear    clears items by "mode" and "tag" query values
+//	POST   {path}/{cache}/reset    resets stats of cache
+type CacheAdminMiddleware struct {
+	Options *wsfhttp.MiddlewareConfig
+	Path    string
+	Token   string
+	Caches  []string
+}
+
+// Init initializes middleware
+func (m *CacheAdminMiddleware) Init(options *wsfhttp.MiddlewareConfig) (bool, error) {
+	m.Options = options
+
+	if upath, ok := m.Options.Params["path"]; ok {
+		if m.Path, ok = upath.(string); !ok || m.Path == "" {
+			return false, errors.New("Path must be a non empty string")
+		}
+	}
+	m.Path = "/" + strings.Trim(m.Path, "/")
+
+	if utoken, ok := m.Options.Params["token"]; ok {
+		m.Token, _ = utoken.(string)
+	}
+
+	if m.Token == "" {
+		return false, errors.New("Cache admin token must be specified")
+	}
+
+	if ucaches, ok := m.Options.Params["caches"]; ok {
+		m.Caches = make([]string, 0)
+		switch ucaches.(type) {
+		case []string:
+			m.Caches = ucaches.([]string)
+
+		case []interface{}:
+			for _, uv := range ucaches.([]interface{}) {
+				switch v := uv.(type) {
+				case string:
+					m.Caches = append(m.Caches, v)
+				}
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Handle middleware
+func (m *CacheAdminMiddleware) Handle(s *wsfhttp.Service, r request.Interface, w response.Interface) bool {
+	path := r.PathInfo()
+	if path != m.Path && !strings.HasPrefix(path, m.Path+"/") {
+		return false
+	}
+
+	rqs := r.GetRequest()
+	if subtle.ConstantTimeCompare([]byte(rqs.Header.Get(CacheAdminTokenHeader)), []byte(m.Token)) != 1 {
+		m.respond(w, http.StatusForbidden, map[string]string{"error": "Forbidden"})
+		return true
+	}
+
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(path, m.Path), "/"), "/", 3)
+	if parts[0] == "" {
+		if rqs.Method != http.MethodGet {
+			m.respond(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+			return true
+		}
+
+		stats := make(map[string]*cache.Stats)
+		for _, name := range m.Caches {
+			if c := m.cache(name); c != nil {
+				stats[name] = c.Stats()
+			}
+		}
+
+		m.respond(w, http.StatusOK, stats)
+		return true
+	}
+
+	c := m.cache(parts[0])
+	if c == nil {
+		m.respond(w, http.StatusNotFound, map[string]string{"error": "Cache '" + parts[0] + "' is not found"})
+		return true
+	}
+
+	action := ""
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+
+	query := rqs.URL.Query()
+	switch {
+	case action == "" && rqs.Method == http.MethodGet:
+		m.respond(w, http.StatusOK, c.Stats())
+
+	case action == "ids" && rqs.Method == http.MethodGet:
+		var ids []string
+		var err error
+		if tags := query["tag"]; len(tags) > 0 {
+			ids, err = c.IDsMatchingTags(tags)
+		} else {
+			ids, err = c.IDs()
+		}
+
+		m.respondResult(w, ids, err)
+
+	case action == "tags" && rqs.Method == http.MethodGet:
+		tags, err := c.Tags()
+		m.respondResult(w, tags, err)
+
+	case action == "items" && len(parts) == 3 && rqs.Method == http.MethodGet:
+		meta, err := c.Metadata(parts[2])
+		if err == nil && meta == nil {
+			m.respond(w, http.StatusNotFound, map[string]string{"error": "Item '" + parts[2] + "' is not found"})
+			return true
+		}
+
+		m.respondResult(w, meta, err)
+
+	case action == "items" && len(parts) == 3 && rqs.Method == http.MethodDelete:
+		if !c.Remove(parts[2]) {
+			m.respondFailure(w, c.Error(), "Unable to remove item")
+			return true
+		}
+
+		c.Logger.Infof("[WSF Cache]: Item '%s' of cache '%s' removed through admin", nil, parts[2], parts[0])
+		m.respond(w, http.StatusOK, map[string]bool{"removed": true})
+
+	case action == "clear" && rqs.Method == http.MethodPost:
+		mode, ok := cleaningModes[query.Get("mode")]
+		if !ok {
+			m.respond(w, http.StatusBadRequest, map[string]string{"error": "Invalid cleaning mode '" + query.Get("mode") + "'"})
+			return true
+		}
+
+		if !c.Clear(mode, query["tag"]) {
+			m.respondFailure(w, c.Error(), "Unable to clear cache")
+			return true
+		}
+
+		c.Logger.Infof("[WSF Cache]: Cache '%s' cleared through admin with mode '%s' and tags %v", nil, parts[0], query.Get("mode"), query["tag"])
+		m.respond(w, http.StatusOK, map[string]bool{"cleared": true})
+
+	case action == "reset" && rqs.Method == http.MethodPost:
+		c.LogStats()
+		c.ResetStats()
+		m.respond(w, http.StatusOK, c.Stats())
+
+	default:
+		m.respond(w, http.StatusNotFound, map[string]string{"error": "Not found"})
+	}
+
+	return true
+}
+
+// Returns cache registered as resource
+func (m *CacheAdminMiddleware) cache(name string) *cache.Core {
+	for _, allowed := range m.Caches {
+		if allowed == name {
+			c, _ := registry.GetResource(name).(*cache.Core)
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (m *CacheAdminMiddleware) respondResult(w response.Interface, result interface{}, err error) {
+	if err != nil {
+		m.respond(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	m.respond(w, http.StatusOK, result)
+}
+
+func (m *CacheAdminMiddleware) respondFailure(w response.Interface, err error, message string) {
+	if err == nil {
+		m.respondResult(w, nil, errors.New(message))
+		return
+	}
+
+	m.respondResult(w, nil, errors.Wrap(err, message))
+}
+
+func (m *CacheAdminMiddleware) respond(w response.Interface, code int, result interface{}) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		code = http.StatusInternalServerError
+		body = []byte(`{"error":"Unable to encode response"}`)
+	}
+
+	w.SetHeader("Content-Type", "application/json")
+	w.SetHeader("Cache-Control", "no-store")
+	w.SetResponseCode(code)
+	w.SetBody(body)
+	w.Write()
+}
+
+// NewCacheAdminMiddleware creates new cache admin middleware
+func NewCacheAdminMiddleware(cfg *wsfhttp.MiddlewareConfig) (mi wsfhttp.Middleware, err error) {
+	c := &CacheAdminMiddleware{
+		Path:   "/_cache",
+		Caches: []string{"cache"},
+	}
+	c.Options = cfg
+	return c, nil
+}
@@ -0,0 +1,553 @@
+package cache
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/noxyicm/wsf/cache"
+	"github.com/noxyicm/wsf/controller/request"
+	"github.com/noxyicm/wsf/controller/response"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/registry"
+	"github.com/noxyicm/wsf/service"
+	wsfhttp "github.com/noxyicm/wsf/service/http"
+	"github.com/noxyicm/wsf/service/http/event"
+	"github.com/noxyicm/wsf/session"
+)
+
+const (
+	// TYPEOutputCacheMiddleware is a name of this middleware
+	TYPEOutputCacheMiddleware = "outputcache"
+
+	// OutputCacheTag is a tag of every cached page
+	OutputCacheTag = "outputcache"
+
+	// OutputCacheTagsData is a response data key holding additional tags of the page
+	OutputCacheTagsData = "outputcache.tags"
+
+	// OutputCacheLifetimeData is a response data key holding lifetime of the page in seconds
+	OutputCacheLifetimeData = "outputcache.lifetime"
+
+	// OutputCacheDisableData is a response data key disabling caching of the page
+	OutputCacheDisableData = "outputcache.disable"
+
+	// OutputCacheStatusHeader is a header reporting output cache status of the response
+	OutputCacheStatusHeader = "X-Cache"
+)
+
+var (
+	outputCaches   = make([]*OutputCacheMiddleware, 0)
+	outputCachesMu sync.RWMutex
+)
+
+type outputCacheKey struct{}
+
+func init() {
+	wsfhttp.RegisterMiddleware(TYPEOutputCacheMiddleware, NewOutputCacheMiddleware)
+}
+
+// OutputCacheMiddleware caches complete responses in a cache registered as resource
+// Pages are keyed by method, path, query and values of Vary headers.
+// Requests carrying credentials, bypass cookies or a session holding IdentityKey are never served from cache,
+// responses setting cookies or marked as private are never stored
+type OutputCacheMiddleware struct {
+	Options       *wsfhttp.MiddlewareConfig
+	Cache         string
+	Lifetime      int64
+	Methods       []string
+	Vary          []string
+	BypassCookies []string
+	IdentityKey   string
+	Rules         []*OutputCacheRule
+	listen        sync.Once
+}
+
+// OutputCacheRule customizes caching of pages matching a path prefix or a pattern
+type OutputCacheRule struct {
+	Path     string
+	Pattern  string
+	Enable   bool
+	Lifetime int64
+	Tags     []string
+	regexp   *regexp.Regexp
+}
+
+// Match returns true if path matches the rule
+func (r *OutputCacheRule) Match(path string) bool {
+	if r.regexp != nil {
+		return r.regexp.MatchString(path)
+	}
+
+	return path == r.Path || strings.HasPrefix(path, strings.TrimSuffix(r.Path, "/")+"/")
+}
+
+// outputCachePage is a stored response
+type outputCachePage struct {
+	Code     int
+	Headers  map[string][]string
+	Body     []byte
+	Created  int64
+	Lifetime int64
+}
+
+// outputCacheMark marks a request whose response should be stored
+type outputCacheMark struct {
+	middleware *OutputCacheMiddleware
+	key        string
+	path       string
+	rule       *OutputCacheRule
+}
+
+// Init initializes middleware
+func (m *OutputCacheMiddleware) Init(options *wsfhttp.MiddlewareConfig) (bool, error) {
+	m.Options = options
+
+	if ucache, ok := m.Options.Params["cache"]; ok {
+		if m.Cache, ok = ucache.(string); !ok || m.Cache == "" {
+			return false, errors.New("Cache must be a non empty string")
+		}
+	}
+
+	if ulifetime, ok := m.Options.Params["lifetime"]; ok {
+		lifetime, err := strconv.ParseInt(strings.TrimSpace(toString(ulifetime)), 10, 64)
+		if err != nil || lifetime <= 0 {
+			return false, errors.New("Lifetime must be a positive number of seconds")
+		}
+
+		m.Lifetime = lifetime
+	}
+
+	if umethods, ok := m.Options.Params["methods"]; ok {
+		m.Methods = make([]string, 0)
+		for _, method := range toStrings(umethods) {
+			m.Methods = append(m.Methods, strings.ToUpper(method))
+		}
+	}
+
+	if uvary, ok := m.Options.Params["vary"]; ok {
+		m.Vary = toStrings(uvary)
+	}
+
+	if ucookies, ok := m.Options.Params["bypassCookies"]; ok {
+		m.BypassCookies = toStrings(ucookies)
+	}
+
+	if uidentity, ok := m.Options.Params["identityKey"]; ok {
+		if m.IdentityKey, ok = uidentity.(string); !ok {
+			return false, errors.New("Identity key must be a string")
+		}
+	}
+
+	if urules, ok := m.Options.Params["rules"]; ok {
+		rules, ok := urules.([]interface{})
+		if !ok {
+			return false, errors.New("Rules must be a list")
+		}
+
+		for i, urule := range rules {
+			rule := &OutputCacheRule{Enable: true}
+			if err := mapstructure.WeakDecode(urule, rule); err != nil {
+				return false, errors.Wrapf(err, "Invalid output cache rule %d", i)
+			}
+
+			if rule.Pattern != "" {
+				re, err := regexp.Compile(rule.Pattern)
+				if err != nil {
+					return false, errors.Wrapf(err, "Invalid pattern of output cache rule %d", i)
+				}
+
+				rule.regexp = re
+			} else if rule.Path == "" {
+				return false, errors.Errorf("Output cache rule %d must specify path or pattern", i)
+			}
+
+			m.Rules = append(m.Rules, rule)
+		}
+	}
+
+	outputCachesMu.Lock()
+	outputCaches = append(outputCaches, m)
+	outputCachesMu.Unlock()
+
+	return true, nil
+}
+
+// Handle middleware
+func (m *OutputCacheMiddleware) Handle(s *wsfhttp.Service, r request.Interface, w response.Interface) bool {
+	m.listen.Do(func() {
+		s.AddListener(m.store)
+	})
+
+	rqs := r.GetRequest()
+	if !m.cacheable(rqs.Method) || m.bypass(r) {
+		return false
+	}
+
+	path := r.PathInfo()
+	rule := m.rule(path)
+	if rule != nil && !rule.Enable {
+		return false
+	}
+
+	c := m.cache()
+	if c == nil || !c.Enabled() {
+		return false
+	}
+
+	key := m.key(r)
+	page := &outputCachePage{}
+	if c.Read(key, page, false) && page.Code > 0 {
+		for name, values := range page.Headers {
+			for _, value := range values {
+				w.AddHeader(name, value)
+			}
+		}
+
+		age := time.Now().Unix() - page.Created
+		if age < 0 {
+			age = 0
+		}
+
+		w.SetHeader("Age", strconv.FormatInt(age, 10))
+		w.SetHeader(OutputCacheStatusHeader, "HIT")
+		w.SetResponseCode(page.Code)
+		w.SetBody(page.Body)
+		w.Write()
+		return true
+	}
+
+	r.SetContext(context.WithValue(r.Context(), outputCacheKey{}, &outputCacheMark{
+		middleware: m,
+		key:        key,
+		path:       path,
+		rule:       rule,
+	}))
+
+	return false
+}
+
+// Stores response of marked request
+// Page is collected while response is not sent yet and is written into cache asynchronously
+func (m *OutputCacheMiddleware) store(ev int, ctx service.Event) {
+	if ev != wsfhttp.EventHTTPResponse || ctx.Error() != nil {
+		return
+	}
+
+	e, ok := ctx.(*event.Response)
+	if !ok {
+		return
+	}
+
+	mark, ok := e.Request.Context().Value(outputCacheKey{}).(*outputCacheMark)
+	if !ok || mark.middleware != m {
+		return
+	}
+
+	rsp, ok := e.Response.(*response.HTTP)
+	if !ok || rsp.Code != http.StatusOK || rsp.IsException() || rsp.IsRedirect() || !m.storable(rsp) || m.bypass(e.Request) {
+		return
+	}
+
+	c := m.cache()
+	if c == nil {
+		return
+	}
+
+	lifetime := m.Lifetime
+	tags := []string{OutputCacheTag, PathTag(mark.path)}
+	if mark.rule != nil {
+		if mark.rule.Lifetime > 0 {
+			lifetime = mark.rule.Lifetime
+		}
+
+		tags = append(tags, mark.rule.Tags...)
+	}
+
+	if ulifetime := rsp.GetData(OutputCacheLifetimeData); ulifetime != nil {
+		if l, err := strconv.ParseInt(toString(ulifetime), 10, 64); err == nil && l > 0 {
+			lifetime = l
+		}
+	}
+
+	if utags := rsp.GetData(OutputCacheTagsData); utags != nil {
+		tags = append(tags, toStrings(utags)...)
+	}
+
+	if _, ok := rsp.Headers["cache-control"]; !ok {
+		rsp.SetHeader("Cache-Control", "public, max-age="+strconv.FormatInt(lifetime, 10))
+	}
+
+	if len(m.Vary) > 0 {
+		rsp.SetHeader("Vary", strings.Join(m.Vary, ", "))
+	}
+
+	page := &outputCachePage{
+		Code:     rsp.Code,
+		Headers:  m.headers(rsp),
+		Created:  time.Now().Unix(),
+		Lifetime: lifetime,
+	}
+
+	for _, segment := range rsp.Body.Stack() {
+		if b, ok := segment.([]byte); ok {
+			page.Body = append(page.Body, b...)
+		}
+	}
+
+	rsp.SetHeader(OutputCacheStatusHeader, "MISS")
+
+	// Listeners are called under lock of http handler, so page is written outside of it
+	go m.write(c, page, mark, tags, lifetime)
+}
+
+// Writes page into cache
+func (m *OutputCacheMiddleware) write(c *cache.Core, page *outputCachePage, mark *outputCacheMark, tags []string, lifetime int64) {
+	if !c.Write(page, mark.key, tags, lifetime) {
+		c.Logger.Warning(errors.Wrapf(c.Error(), "[WSF Cache]: Unable to store page '%s'", mark.path), nil)
+	}
+}
+
+// Returns true if requests of method are cacheable
+func (m *OutputCacheMiddleware) cacheable(method string) bool {
+	for _, allowed := range m.Methods {
+		if allowed == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if request must not be served from cache
+func (m *OutputCacheMiddleware) bypass(r request.Interface) bool {
+	if r.Header("Authorization") != "" {
+		return true
+	}
+
+	for _, name := range m.BypassCookies {
+		if r.Cookie(name) != "" {
+			return true
+		}
+	}
+
+	if cc := strings.ToLower(r.Header("Cache-Control")); strings.Contains(cc, "no-cache") || strings.Contains(cc, "no-store") {
+		return true
+	}
+
+	return m.authenticated(r)
+}
+
+// Returns true if response may be stored
+func (m *OutputCacheMiddleware) storable(rsp *response.HTTP) bool {
+	if disable, ok := rsp.GetData(OutputCacheDisableData).(bool); ok && disable {
+		return false
+	}
+
+	sessionName := ""
+	if session.Created() {
+		sessionName = session.Instance().Options().SessionName
+	}
+
+	for name := range rsp.Cookies {
+		if name != sessionName {
+			return false
+		}
+	}
+
+	if _, ok := rsp.Headers["set-cookie"]; ok {
+		return false
+	}
+
+	for _, value := range rsp.Headers["cache-control"] {
+		value = strings.ToLower(value)
+		if strings.Contains(value, "private") || strings.Contains(value, "no-store") || strings.Contains(value, "no-cache") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns headers of response to store
+func (m *OutputCacheMiddleware) headers(rsp *response.HTTP) map[string][]string {
+	skip := map[string]bool{"set-cookie": true, "age": true, "date": true, "x-cache": true}
+	if session.Created() {
+		skip[strings.ToLower(session.Instance().Options().SessionNameInHTTPHeader)] = true
+	}
+
+	headers := make(map[string][]string)
+	for name, values := range rsp.Headers {
+		if !skip[name] {
+			headers[name] = append([]string{}, values...)
+		}
+	}
+
+	return headers
+}
+
+// Returns first rule matching path
+func (m *OutputCacheMiddleware) rule(path string) *OutputCacheRule {
+	for _, rule := range m.Rules {
+		if rule.Match(path) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// Returns cache key of request
+func (m *OutputCacheMiddleware) key(r request.Interface) string {
+	rqs := r.GetRequest()
+
+	h := md5.New()
+	h.Write([]byte(rqs.Method + "\n" + r.PathInfo() + "\n" + rqs.URL.Query().Encode() + "\n"))
+	for _, name := range m.Vary {
+		h.Write([]byte(strings.ToLower(name) + ":" + r.Header(name) + "\n"))
+	}
+
+	return OutputCacheTag + "_" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Returns cache registered as resource
+func (m *OutputCacheMiddleware) cache() *cache.Core {
+	c, _ := registry.GetResource(m.Cache).(*cache.Core)
+	return c
+}
+
+// Returns true if request belongs to a session with an authenticated identity
+func (m *OutputCacheMiddleware) authenticated(r request.Interface) bool {
+	if !session.Created() || m.IdentityKey == "" {
+		return false
+	}
+
+	sid, err := session.Instance().GetSID(r)
+	if err != nil || sid == "" {
+		return false
+	}
+
+	s, ok := session.Get(sid)
+	if !ok {
+		// Middleware runs before session is started, so stored session is loaded into a temporary one
+		mgr := session.Instance()
+		if !mgr.SessionExist(sid) {
+			return false
+		}
+
+		if s, err = session.NewSession(mgr.Options().Session.GetString("type"), mgr.Options().Session); err != nil {
+			return true
+		}
+
+		// Session failing to load is replaced by a new one when started
+		if err := mgr.SessionLoad(sid, s); err != nil {
+			return false
+		}
+	}
+
+	return s.Has(m.IdentityKey)
+}
+
+// Purge removes pages tagged with any of the tags from caches of all output cache middlewares
+func Purge(tags ...string) error {
+	outputCachesMu.RLock()
+	defer outputCachesMu.RUnlock()
+
+	for _, m := range outputCaches {
+		c := m.cache()
+		if c == nil {
+			continue
+		}
+
+		if !c.Clear(cache.CleaningModeMatchingAnyTag, tags) {
+			return errors.Wrapf(c.Error(), "Unable to purge pages of cache '%s'", m.Cache)
+		}
+	}
+
+	return nil
+}
+
+// PurgePath removes pages of path from caches of all output cache middlewares
+func PurgePath(path string) error {
+	return Purge(PathTag(path))
+}
+
+// PurgeAll removes all pages from caches of all output cache middlewares
+func PurgeAll() error {
+	return Purge(OutputCacheTag)
+}
+
+// PathTag returns tag of pages of path
+func PathTag(path string) string {
+	h := md5.Sum([]byte(path))
+	return OutputCacheTag + "_path_" + hex.EncodeToString(h[:])
+}
+
+// TagResponse adds tags to the page stored from response
+func TagResponse(w response.Interface, tags ...string) {
+	w.SetData(OutputCacheTagsData, append(toStrings(w.GetData(OutputCacheTagsData)), tags...))
+}
+
+// DisableResponse prevents response from being stored
+func DisableResponse(w response.Interface) {
+	w.SetData(OutputCacheDisableData, true)
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+
+	case int:
+		return strconv.Itoa(v)
+
+	case int64:
+		return strconv.FormatInt(v, 10)
+
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+func toStrings(v interface{}) []string {
+	strs := make([]string, 0)
+	switch v := v.(type) {
+	case string:
+		strs = append(strs, v)
+
+	case []string:
+		strs = append(strs, v...)
+
+	case []interface{}:
+		for _, uv := range v {
+			if s, ok := uv.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+	}
+
+	return strs
+}
+
+// NewOutputCacheMiddleware creates new output cache middleware
+func NewOutputCacheMiddleware(cfg *wsfhttp.MiddlewareConfig) (mi wsfhttp.Middleware, err error) {
+	c := &OutputCacheMiddleware{
+		Cache:         "cache",
+		Lifetime:      300,
+		Methods:       []string{http.MethodGet, http.MethodHead},
+		BypassCookies: []string{},
+		IdentityKey:   "WSFAuth",
+		Rules:         make([]*OutputCacheRule, 0),
+	}
+	c.Options = cfg
+	return c, nil
+}
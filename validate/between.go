@@ -0,0 +1,94 @@
+package validate
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Between failure codes
+const (
+	BetweenNotNumeric       = "notNumeric"
+	BetweenNotBetween       = "notBetween"
+	BetweenNotBetweenStrict = "notBetweenStrict"
+)
+
+func init() {
+	Register("Between", reflect.TypeOf((*Between)(nil)).Elem())
+}
+
+// Between validator checks a number is in range of Min and Max
+// Numeric strings are accepted
+type Between struct {
+	Abstract  `mapstructure:",squash"`
+	Min       float64
+	Max       float64
+	Inclusive bool
+}
+
+// Valid returns true if value is between Min and Max
+func (v *Between) Valid(value interface{}) bool {
+	v.reset()
+	n, ok := toFloat(value)
+	if !ok {
+		return v.fail(BetweenNotNumeric, value, nil)
+	}
+
+	params := map[string]interface{}{"min": formatFloat(v.Min), "max": formatFloat(v.Max)}
+	if v.Inclusive {
+		if n < v.Min || n > v.Max {
+			return v.fail(BetweenNotBetween, value, params)
+		}
+	} else if n <= v.Min || n >= v.Max {
+		return v.fail(BetweenNotBetweenStrict, value, params)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *Between) Defaults() error {
+	v.Inclusive = true
+	v.templates(map[string]string{
+		BetweenNotNumeric:       "'%value%' is not a number",
+		BetweenNotBetween:       "'%value%' is not between '%min%' and '%max%', inclusively",
+		BetweenNotBetweenStrict: "'%value%' is not strictly between '%min%' and '%max%'",
+	})
+
+	return nil
+}
+
+// Returns value as float if it is a number or a numeric string
+func toFloat(value interface{}) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// NewBetween creates new between validator
+func NewBetween(min float64, max float64, inclusive bool) (Interface, error) {
+	v := &Between{}
+	v.Defaults()
+	v.Min = min
+	v.Max = max
+	v.Inclusive = inclusive
+	return v, nil
+}
@@ -0,0 +1,109 @@
+package validate
+
+import (
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/translate"
+)
+
+// Chain is a validator running validators in order they were added
+// Value is valid if all of the validators succeed
+type Chain struct {
+	validators []*chainItem
+	messages   map[string]string
+	codes      []string
+}
+
+type chainItem struct {
+	validator      Interface
+	breakOnFailure bool
+}
+
+// Add appends validator to chain
+// If breakOnFailure is true and the validator fails the rest of the chain is skipped
+func (c *Chain) Add(v Interface, breakOnFailure bool) *Chain {
+	c.validators = append(c.validators, &chainItem{validator: v, breakOnFailure: breakOnFailure})
+	return c
+}
+
+// Prepend inserts validator in front of chain
+func (c *Chain) Prepend(v Interface, breakOnFailure bool) *Chain {
+	c.validators = append([]*chainItem{{validator: v, breakOnFailure: breakOnFailure}}, c.validators...)
+	return c
+}
+
+// AddByName creates a registered validator and appends it to chain
+func (c *Chain) AddByName(validatorName string, options map[string]interface{}, breakOnFailure bool) error {
+	v, err := NewValidator(validatorName, options)
+	if err != nil {
+		return errors.Wrap(err, "Unable to add validator to chain")
+	}
+
+	c.Add(v, breakOnFailure)
+	return nil
+}
+
+// Valid returns true if value passes all of the validators
+func (c *Chain) Valid(value interface{}) bool {
+	c.messages = make(map[string]string)
+	c.codes = make([]string, 0)
+
+	valid := true
+	for _, item := range c.validators {
+		if item.validator.Valid(value) {
+			continue
+		}
+
+		valid = false
+		for code, message := range item.validator.Messages() {
+			c.messages[code] = message
+		}
+
+		if e, ok := item.validator.(interface{ Errors() []string }); ok {
+			c.codes = append(c.codes, e.Errors()...)
+		}
+
+		if item.breakOnFailure {
+			break
+		}
+	}
+
+	return valid
+}
+
+// Messages returns failure messages of the last validation by failure code
+func (c *Chain) Messages() map[string]string {
+	return c.messages
+}
+
+// Errors returns failure codes of the last validation
+func (c *Chain) Errors() []string {
+	return c.codes
+}
+
+// Defaults sets default properties
+func (c *Chain) Defaults() error {
+	return nil
+}
+
+// SetTranslator sets translator of all of the chained validators
+func (c *Chain) SetTranslator(t translate.Interface) {
+	for _, item := range c.validators {
+		if tv, ok := item.validator.(interface{ SetTranslator(translate.Interface) }); ok {
+			tv.SetTranslator(t)
+		}
+	}
+}
+
+// Len returns number of validators in chain
+func (c *Chain) Len() int {
+	return len(c.validators)
+}
+
+// NewChain creates new validator chain
+func NewChain() *Chain {
+	return &Chain{
+		validators: make([]*chainItem, 0),
+		messages:   make(map[string]string),
+		codes:      make([]string, 0),
+	}
+}
@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"reflect"
+	"time"
+)
+
+// Date failure codes
+const (
+	DateInvalid     = "dateInvalid"
+	DateFalseFormat = "dateFalseFormat"
+)
+
+func init() {
+	Register("Date", reflect.TypeOf((*Date)(nil)).Elem())
+}
+
+// Date validator checks a string is a date of Format
+// Format is a time layout, time.Time values are always valid
+type Date struct {
+	Abstract `mapstructure:",squash"`
+	Format   string
+}
+
+// Valid returns true if value is a date of Format
+func (v *Date) Valid(value interface{}) bool {
+	v.reset()
+	switch d := value.(type) {
+	case time.Time:
+		return true
+
+	case string:
+		if _, err := time.Parse(v.Format, d); err != nil {
+			return v.fail(DateFalseFormat, value, map[string]interface{}{"format": v.Format})
+		}
+
+		return true
+	}
+
+	return v.fail(DateInvalid, value, nil)
+}
+
+// Defaults sets default properties
+func (v *Date) Defaults() error {
+	v.Format = "2006-01-02"
+	v.templates(map[string]string{
+		DateInvalid:     "Invalid type given. String or time expected",
+		DateFalseFormat: "'%value%' does not fit the date format '%format%'",
+	})
+
+	return nil
+}
+
+// NewDate creates new date validator
+func NewDate(format string) (Interface, error) {
+	v := &Date{}
+	v.Defaults()
+	if format != "" {
+		v.Format = format
+	}
+
+	return v, nil
+}
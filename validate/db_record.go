@@ -0,0 +1,163 @@
+package validate
+
+import (
+	goctx "context"
+	"reflect"
+
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Db record failure codes
+const (
+	DbNoRecordFound = "noRecordFound"
+	DbRecordFound   = "recordFound"
+	DbError         = "dbError"
+)
+
+func init() {
+	Register("DbRecordExists", reflect.TypeOf((*DbRecordExists)(nil)).Elem())
+	Register("DbNoRecordExists", reflect.TypeOf((*DbNoRecordExists)(nil)).Elem())
+}
+
+// dbRecord is a base of validators looking up a record by Field in Table
+// If Table is not set, table named TableName is used through Adapter or the default database adapter.
+// Exclude adds conditions of records to ignore, e.g. {"id <> ?": 10} to skip the edited record
+type dbRecord struct {
+	Abstract  `mapstructure:",squash"`
+	Table     db.Table `mapstructure:"-"`
+	TableName string   `mapstructure:"table"`
+	Adapter   string
+	Field     string
+	Exclude   map[string]interface{}
+	Context   context.Context
+}
+
+// Returns true if a record with value of field exists
+func (v *dbRecord) exists(value interface{}) (bool, error) {
+	tbl, err := v.table()
+	if err != nil {
+		return false, err
+	}
+
+	ctx := v.Context
+	if ctx == nil {
+		if ctx, err = context.NewContext(goctx.Background()); err != nil {
+			return false, err
+		}
+	}
+
+	slct := tbl.Select(true).Where(tbl.GetAdapter().QuoteIdentifier(v.Field, true)+" = ?", value)
+	for cond, bind := range v.Exclude {
+		slct.Where(cond, bind)
+	}
+
+	// Row fetched by table holds its columns even if nothing is found, so rows are counted
+	rowset, err := tbl.FetchAll(ctx, slct.Limit(1, 0))
+	if err != nil {
+		return false, err
+	}
+
+	return rowset.Count() > 0, nil
+}
+
+// Returns table to look records up in
+func (v *dbRecord) table() (db.Table, error) {
+	if v.Table != nil {
+		return v.Table, nil
+	}
+
+	if v.TableName == "" {
+		return nil, errors.New("Table to look records up in is not set")
+	}
+
+	tbl := db.NewEmptyDefaultTable(&db.TableConfig{Adapter: v.Adapter, DefaultSource: db.DefaultNone})
+	tbl.Name = v.TableName
+	if tbl.Adapter == nil {
+		if v.Adapter != "" {
+			return nil, errors.Errorf("Database adapter '%s' is not configured", v.Adapter)
+		}
+
+		if db.Instance() != nil {
+			tbl.SetAdapter(db.Instance().Adapter())
+		}
+	}
+
+	if err := tbl.Setup(); err != nil {
+		return nil, errors.Wrapf(err, "Unable to set up table '%s'", v.TableName)
+	}
+
+	v.Table = tbl
+	return tbl, nil
+}
+
+// Defaults sets default properties
+func (v *dbRecord) Defaults() error {
+	v.Exclude = make(map[string]interface{})
+	v.templates(map[string]string{
+		DbNoRecordFound: "No record matching '%value%' was found",
+		DbRecordFound:   "A record matching '%value%' was found",
+		DbError:         "Unable to look up a record matching '%value%'",
+	})
+
+	return nil
+}
+
+// DbRecordExists validator checks a record with value of Field exists in Table
+type DbRecordExists struct {
+	dbRecord `mapstructure:",squash"`
+}
+
+// Valid returns true if a record with value exists
+func (v *DbRecordExists) Valid(value interface{}) bool {
+	v.reset()
+	exists, err := v.exists(value)
+	if err != nil {
+		return v.fail(DbError, value, nil)
+	}
+
+	if !exists {
+		return v.fail(DbNoRecordFound, value, nil)
+	}
+
+	return true
+}
+
+// DbNoRecordExists validator checks no record with value of Field exists in Table
+type DbNoRecordExists struct {
+	dbRecord `mapstructure:",squash"`
+}
+
+// Valid returns true if no record with value exists
+func (v *DbNoRecordExists) Valid(value interface{}) bool {
+	v.reset()
+	exists, err := v.exists(value)
+	if err != nil {
+		return v.fail(DbError, value, nil)
+	}
+
+	if exists {
+		return v.fail(DbRecordFound, value, nil)
+	}
+
+	return true
+}
+
+// NewDbRecordExists creates new validator of record existence
+func NewDbRecordExists(table db.Table, field string) (Interface, error) {
+	v := &DbRecordExists{}
+	v.Defaults()
+	v.Table = table
+	v.Field = field
+	return v, nil
+}
+
+// NewDbNoRecordExists creates new validator of record uniqueness
+func NewDbNoRecordExists(table db.Table, field string) (Interface, error) {
+	v := &DbNoRecordExists{}
+	v.Defaults()
+	v.Table = table
+	v.Field = field
+	return v, nil
+}
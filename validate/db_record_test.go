@@ -0,0 +1,117 @@
+package validate
+
+import (
+	goctx "context"
+	"testing"
+
+	"github.com/noxyicm/wsf/config"
+	"github.com/noxyicm/wsf/context"
+	"github.com/noxyicm/wsf/db"
+	"github.com/noxyicm/wsf/db/sqlite"
+)
+
+// Opens in-memory database holding users table
+func newTestUsers(t *testing.T) db.Adapter {
+	t.Helper()
+
+	cfg := config.NewBridge()
+	cfg.Merge(map[string]interface{}{
+		"adapter": map[string]interface{}{
+			"type":   sqlite.TYPEAdapter,
+			"dbname": sqlite.Memory,
+		},
+	})
+
+	d, err := db.NewDB(cfg)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+
+	db.SetInstance(d)
+	adp := d.Adapter()
+
+	ctx, _ := context.NewContext(goctx.Background())
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)`,
+		`INSERT INTO users (id, email) VALUES (1, 'alice@example.com'), (2, 'bob@example.com')`,
+	} {
+		if _, err := adp.Exec(ctx, stmt); err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+	}
+
+	return adp
+}
+
+func TestDbRecordExists(t *testing.T) {
+	adp := newTestUsers(t)
+
+	tbl := db.NewEmptyDefaultTable(&db.TableConfig{DefaultSource: db.DefaultNone})
+	tbl.Name = "users"
+	tbl.SetAdapter(adp)
+	if err := tbl.Setup(); err != nil {
+		t.Fatalf("table setup: %v", err)
+	}
+
+	v, _ := NewDbRecordExists(tbl, "email")
+	if !v.Valid("alice@example.com") {
+		t.Fatalf("Valid(existing) = false, messages %v", v.Messages())
+	}
+
+	if v.Valid("carol@example.com") || !hasCode(v, DbNoRecordFound) {
+		t.Fatalf("Valid(missing) failures = %v, want %s", v.Messages(), DbNoRecordFound)
+	}
+
+	nv, _ := NewDbNoRecordExists(tbl, "email")
+	if nv.Valid("alice@example.com") || !hasCode(nv, DbRecordFound) {
+		t.Fatalf("Valid(existing) failures = %v, want %s", nv.Messages(), DbRecordFound)
+	}
+
+	if !nv.Valid("carol@example.com") {
+		t.Fatalf("Valid(missing) = false, messages %v", nv.Messages())
+	}
+}
+
+func TestDbNoRecordExistsByTableName(t *testing.T) {
+	newTestUsers(t)
+
+	v, err := NewValidator("DbNoRecordExists", map[string]interface{}{
+		"table":   "users",
+		"field":   "email",
+		"exclude": map[string]interface{}{"id <> ?": 1},
+	})
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+
+	// Edited record itself is excluded
+	if !v.Valid("alice@example.com") {
+		t.Fatalf("Valid(excluded) = false, messages %v", v.Messages())
+	}
+
+	if v.Valid("bob@example.com") || !hasCode(v, DbRecordFound) {
+		t.Fatalf("Valid(existing) failures = %v, want %s", v.Messages(), DbRecordFound)
+	}
+
+	v, err = NewValidator("DbRecordExists", map[string]interface{}{"table": "users", "field": "email", "adapter": "missing"})
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+
+	if v.Valid("alice@example.com") || !hasCode(v, DbError) {
+		t.Fatalf("Valid with missing adapter failures = %v, want %s", v.Messages(), DbError)
+	}
+}
+
+func TestDbRecordWithoutTable(t *testing.T) {
+	v, _ := NewDbRecordExists(nil, "email")
+	if v.Valid("alice@example.com") || !hasCode(v, DbError) {
+		t.Fatalf("Valid without table failures = %v, want %s", v.Messages(), DbError)
+	}
+}
+
+// Returns true if validator failed with code
+func hasCode(v Interface, code string) bool {
+	_, ok := v.Messages()[code]
+	return ok
+}
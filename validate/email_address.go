@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"net/mail"
+	"reflect"
+	"strings"
+)
+
+// EmailAddress failure codes
+const (
+	EmailAddressInvalid         = "emailAddressInvalid"
+	EmailAddressInvalidFormat   = "emailAddressInvalidFormat"
+	EmailAddressInvalidHostname = "emailAddressInvalidHostname"
+	EmailAddressLengthExceeded  = "emailAddressLengthExceeded"
+)
+
+func init() {
+	Register("EmailAddress", reflect.TypeOf((*EmailAddress)(nil)).Elem())
+}
+
+// EmailAddress validator checks a plain email address like "user@example.com"
+// Domain part is checked by Hostname validator
+type EmailAddress struct {
+	Abstract   `mapstructure:",squash"`
+	AllowIP    bool
+	AllowLocal bool
+}
+
+// Valid returns true if value is a valid email address
+func (v *EmailAddress) Valid(value interface{}) bool {
+	v.reset()
+	s, ok := value.(string)
+	if !ok {
+		return v.fail(EmailAddressInvalid, value, nil)
+	}
+
+	if len(s) > 320 {
+		return v.fail(EmailAddressLengthExceeded, value, nil)
+	}
+
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return v.fail(EmailAddressInvalidFormat, value, nil)
+	}
+
+	at := strings.LastIndex(s, "@")
+	local, domain := s[:at], s[at+1:]
+	if len(local) > 64 {
+		return v.fail(EmailAddressLengthExceeded, value, nil)
+	}
+
+	hostname := &Hostname{AllowIP: v.AllowIP, AllowLocal: v.AllowLocal}
+	if !hostname.Valid(domain) {
+		return v.fail(EmailAddressInvalidHostname, value, map[string]interface{}{"hostname": domain})
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *EmailAddress) Defaults() error {
+	v.templates(map[string]string{
+		EmailAddressInvalid:         "Invalid type given. String expected",
+		EmailAddressInvalidFormat:   "'%value%' is not a valid email address in the basic format local-part@hostname",
+		EmailAddressInvalidHostname: "'%hostname%' is not a valid hostname for email address '%value%'",
+		EmailAddressLengthExceeded:  "'%value%' exceeds the allowed length",
+	})
+
+	return nil
+}
+
+// NewEmailAddress creates new email address validator
+func NewEmailAddress() (Interface, error) {
+	v := &EmailAddress{}
+	v.Defaults()
+	return v, nil
+}
@@ -0,0 +1,96 @@
+package validate
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/noxyicm/wsf/application/file"
+)
+
+// FileMimeType failure codes
+const (
+	FileMimeTypeFalse       = "fileMimeTypeFalse"
+	FileMimeTypeNotDetected = "fileMimeTypeNotDetected"
+	FileMimeTypeNotReadable = "fileMimeTypeNotReadable"
+)
+
+func init() {
+	Register("FileMimeType", reflect.TypeOf((*FileMimeType)(nil)).Elem())
+}
+
+// FileMimeType validator checks mime type of an uploaded file or a file by path
+// Types may contain wildcards like "image/*"
+type FileMimeType struct {
+	Abstract `mapstructure:",squash"`
+	Types    []string
+}
+
+// Valid returns true if file mime type is one of Types
+func (v *FileMimeType) Valid(value interface{}) bool {
+	v.reset()
+	var detected string
+	switch f := value.(type) {
+	case *file.File:
+		if f == nil || f.Error != nil {
+			return v.fail(FileMimeTypeNotReadable, fileName(value), nil)
+		}
+
+		detected = f.Mime
+
+	case string:
+		fh, err := os.Open(f)
+		if err != nil {
+			return v.fail(FileMimeTypeNotReadable, fileName(value), nil)
+		}
+		defer fh.Close()
+
+		sniff := make([]byte, 512)
+		n, err := fh.Read(sniff)
+		if err != nil && err != io.EOF {
+			return v.fail(FileMimeTypeNotReadable, fileName(value), nil)
+		}
+
+		detected = http.DetectContentType(sniff[:n])
+
+	default:
+		return v.fail(FileMimeTypeNotReadable, fileName(value), nil)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil || mediaType == "" {
+		return v.fail(FileMimeTypeNotDetected, fileName(value), nil)
+	}
+
+	for _, t := range v.Types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+
+	return v.fail(FileMimeTypeFalse, fileName(value), map[string]interface{}{"type": mediaType})
+}
+
+// Defaults sets default properties
+func (v *FileMimeType) Defaults() error {
+	v.Types = make([]string, 0)
+	v.templates(map[string]string{
+		FileMimeTypeFalse:       "File '%value%' has a false mimetype of '%type%'",
+		FileMimeTypeNotDetected: "The mimetype of file '%value%' could not be detected",
+		FileMimeTypeNotReadable: "File '%value%' is not readable or does not exist",
+	})
+
+	return nil
+}
+
+// NewFileMimeType creates new file mime type validator
+func NewFileMimeType(types ...string) (Interface, error) {
+	v := &FileMimeType{}
+	v.Defaults()
+	v.Types = types
+	return v, nil
+}
@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"os"
+	"reflect"
+
+	"github.com/noxyicm/wsf/application/file"
+)
+
+// FileSize failure codes
+const (
+	FileSizeTooBig   = "fileSizeTooBig"
+	FileSizeTooSmall = "fileSizeTooSmall"
+	FileSizeNotFound = "fileSizeNotFound"
+)
+
+func init() {
+	Register("FileSize", reflect.TypeOf((*FileSize)(nil)).Elem())
+}
+
+// FileSize validator checks size in bytes of an uploaded file or a file by path
+// Max of 0 means the size is not limited
+type FileSize struct {
+	Abstract `mapstructure:",squash"`
+	Min      int64
+	Max      int64
+}
+
+// Valid returns true if file size is between Min and Max
+func (v *FileSize) Valid(value interface{}) bool {
+	v.reset()
+	var size int64
+	switch f := value.(type) {
+	case *file.File:
+		if f == nil || f.Error != nil {
+			return v.fail(FileSizeNotFound, fileName(value), nil)
+		}
+
+		size = f.Size
+
+	case string:
+		info, err := os.Stat(f)
+		if err != nil || info.IsDir() {
+			return v.fail(FileSizeNotFound, fileName(value), nil)
+		}
+
+		size = info.Size()
+
+	default:
+		return v.fail(FileSizeNotFound, fileName(value), nil)
+	}
+
+	params := map[string]interface{}{"min": v.Min, "max": v.Max, "size": size}
+	if size < v.Min {
+		return v.fail(FileSizeTooSmall, fileName(value), params)
+	}
+
+	if v.Max > 0 && size > v.Max {
+		return v.fail(FileSizeTooBig, fileName(value), params)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *FileSize) Defaults() error {
+	v.templates(map[string]string{
+		FileSizeTooBig:   "Maximum allowed size for file '%value%' is '%max%' bytes but '%size%' bytes detected",
+		FileSizeTooSmall: "Minimum expected size for file '%value%' is '%min%' bytes but '%size%' bytes detected",
+		FileSizeNotFound: "File '%value%' is not readable or does not exist",
+	})
+
+	return nil
+}
+
+// Returns name of validated file for messages
+func fileName(value interface{}) interface{} {
+	if f, ok := value.(*file.File); ok && f != nil {
+		return f.Name
+	}
+
+	return value
+}
+
+// NewFileSize creates new file size validator
+func NewFileSize(min int64, max int64) (Interface, error) {
+	v := &FileSize{Min: min, Max: max}
+	v.Defaults()
+	return v, nil
+}
@@ -0,0 +1,61 @@
+package validate
+
+import (
+	"reflect"
+)
+
+// GreaterThan failure codes
+const (
+	GreaterThanNotNumeric   = "notNumeric"
+	GreaterThanNotGreater   = "notGreaterThan"
+	GreaterThanNotInclusive = "notGreaterThanInclusive"
+)
+
+func init() {
+	Register("GreaterThan", reflect.TypeOf((*GreaterThan)(nil)).Elem())
+}
+
+// GreaterThan validator checks a number is greater than Min
+type GreaterThan struct {
+	Abstract  `mapstructure:",squash"`
+	Min       float64
+	Inclusive bool
+}
+
+// Valid returns true if value is greater than Min
+func (v *GreaterThan) Valid(value interface{}) bool {
+	v.reset()
+	n, ok := toFloat(value)
+	if !ok {
+		return v.fail(GreaterThanNotNumeric, value, nil)
+	}
+
+	params := map[string]interface{}{"min": formatFloat(v.Min)}
+	if v.Inclusive {
+		if n < v.Min {
+			return v.fail(GreaterThanNotInclusive, value, params)
+		}
+	} else if n <= v.Min {
+		return v.fail(GreaterThanNotGreater, value, params)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *GreaterThan) Defaults() error {
+	v.templates(map[string]string{
+		GreaterThanNotNumeric:   "'%value%' is not a number",
+		GreaterThanNotGreater:   "'%value%' is not greater than '%min%'",
+		GreaterThanNotInclusive: "'%value%' is not greater or equal than '%min%'",
+	})
+
+	return nil
+}
+
+// NewGreaterThan creates new greater than validator
+func NewGreaterThan(min float64, inclusive bool) (Interface, error) {
+	v := &GreaterThan{Min: min, Inclusive: inclusive}
+	v.Defaults()
+	return v, nil
+}
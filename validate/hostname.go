@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"net"
+	"reflect"
+	"strings"
+)
+
+// Hostname failure codes
+const (
+	HostnameInvalid             = "hostnameInvalid"
+	HostnameInvalidHostname     = "hostnameInvalidHostname"
+	HostnameIPAddressNotAllowed = "hostnameIpAddressNotAllowed"
+	HostnameLocalNameNotAllowed = "hostnameLocalNameNotAllowed"
+	HostnameUnknownTLD          = "hostnameUnknownTld"
+)
+
+func init() {
+	Register("Hostname", reflect.TypeOf((*Hostname)(nil)).Elem())
+}
+
+// Hostname validator checks DNS hostnames
+// IP addresses and local names like "localhost" are rejected unless allowed
+type Hostname struct {
+	Abstract   `mapstructure:",squash"`
+	AllowIP    bool
+	AllowLocal bool
+}
+
+// Valid returns true if value is a valid hostname
+func (v *Hostname) Valid(value interface{}) bool {
+	v.reset()
+	s, ok := value.(string)
+	if !ok {
+		return v.fail(HostnameInvalid, value, nil)
+	}
+
+	if ip := net.ParseIP(strings.Trim(s, "[]")); ip != nil {
+		if !v.AllowIP {
+			return v.fail(HostnameIPAddressNotAllowed, value, nil)
+		}
+
+		return true
+	}
+
+	host := strings.TrimSuffix(s, ".")
+	if host == "" || len(host) > 253 {
+		return v.fail(HostnameInvalidHostname, value, nil)
+	}
+
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		if !validHostnameLabel(label) {
+			return v.fail(HostnameInvalidHostname, value, nil)
+		}
+	}
+
+	if len(labels) == 1 {
+		if !v.AllowLocal {
+			return v.fail(HostnameLocalNameNotAllowed, value, nil)
+		}
+
+		return true
+	}
+
+	if !validTLD(labels[len(labels)-1]) {
+		return v.fail(HostnameUnknownTLD, value, nil)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *Hostname) Defaults() error {
+	v.templates(map[string]string{
+		HostnameInvalid:             "Invalid type given. String expected",
+		HostnameInvalidHostname:     "'%value%' does not match the expected structure for a DNS hostname",
+		HostnameIPAddressNotAllowed: "'%value%' appears to be an IP address, but IP addresses are not allowed",
+		HostnameLocalNameNotAllowed: "'%value%' appears to be a local network name but local network names are not allowed",
+		HostnameUnknownTLD:          "'%value%' appears to be a DNS hostname but cannot match TLD against known list",
+	})
+
+	return nil
+}
+
+// Returns true if label is a valid DNS label
+func validHostnameLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if c != '-' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns true if label may be a top level domain
+func validTLD(label string) bool {
+	if strings.HasPrefix(strings.ToLower(label), "xn--") {
+		return len(label) > 4
+	}
+
+	if len(label) < 2 {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewHostname creates new hostname validator
+func NewHostname(allowIP bool, allowLocal bool) (Interface, error) {
+	v := &Hostname{AllowIP: allowIP, AllowLocal: allowLocal}
+	v.Defaults()
+	return v, nil
+}
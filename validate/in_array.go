@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"reflect"
+)
+
+// InArray failure codes
+const (
+	InArrayNotInArray = "notInArray"
+)
+
+func init() {
+	Register("InArray", reflect.TypeOf((*InArray)(nil)).Elem())
+}
+
+// InArray validator checks a value is one of Haystack values
+// Unless Strict is set values are compared by their string representation
+type InArray struct {
+	Abstract `mapstructure:",squash"`
+	Haystack []interface{}
+	Strict   bool
+}
+
+// Valid returns true if value is found in Haystack
+func (v *InArray) Valid(value interface{}) bool {
+	v.reset()
+	for _, item := range v.Haystack {
+		if v.Strict {
+			if reflect.DeepEqual(item, value) {
+				return true
+			}
+		} else if valueString(item) == valueString(value) {
+			return true
+		}
+	}
+
+	return v.fail(InArrayNotInArray, value, nil)
+}
+
+// Defaults sets default properties
+func (v *InArray) Defaults() error {
+	v.Haystack = make([]interface{}, 0)
+	v.templates(map[string]string{
+		InArrayNotInArray: "'%value%' was not found in the haystack",
+	})
+
+	return nil
+}
+
+// NewInArray creates new in array validator
+func NewInArray(haystack []interface{}, strict bool) (Interface, error) {
+	v := &InArray{}
+	v.Defaults()
+	v.Haystack = haystack
+	v.Strict = strict
+	return v, nil
+}
@@ -0,0 +1,69 @@
+package validate
+
+import (
+	"net"
+	"reflect"
+	"strings"
+)
+
+// IP failure codes
+const (
+	IPInvalid      = "ipInvalid"
+	IPNotIPAddress = "notIpAddress"
+)
+
+func init() {
+	Register("Ip", reflect.TypeOf((*IP)(nil)).Elem())
+}
+
+// IP validator checks IPv4 and IPv6 addresses
+type IP struct {
+	Abstract  `mapstructure:",squash"`
+	AllowIPv4 bool
+	AllowIPv6 bool
+}
+
+// Valid returns true if value is an IP address of allowed version
+func (v *IP) Valid(value interface{}) bool {
+	v.reset()
+	s, ok := value.(string)
+	if !ok {
+		return v.fail(IPInvalid, value, nil)
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return v.fail(IPNotIPAddress, value, nil)
+	}
+
+	if strings.Contains(s, ":") {
+		if !v.AllowIPv6 {
+			return v.fail(IPNotIPAddress, value, nil)
+		}
+	} else if !v.AllowIPv4 {
+		return v.fail(IPNotIPAddress, value, nil)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *IP) Defaults() error {
+	v.AllowIPv4 = true
+	v.AllowIPv6 = true
+	v.templates(map[string]string{
+		IPInvalid:      "Invalid type given. String expected",
+		IPNotIPAddress: "'%value%' does not appear to be a valid IP address",
+	})
+
+	return nil
+}
+
+// NewIP creates new IP address validator
+func NewIP(allowIPv4 bool, allowIPv6 bool) (Interface, error) {
+	v := &IP{}
+	v.Defaults()
+	v.AllowIPv4 = allowIPv4
+	v.AllowIPv6 = allowIPv6
+	return v, nil
+}
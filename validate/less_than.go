@@ -0,0 +1,61 @@
+package validate
+
+import (
+	"reflect"
+)
+
+// LessThan failure codes
+const (
+	LessThanNotNumeric   = "notNumeric"
+	LessThanNotLess      = "notLessThan"
+	LessThanNotInclusive = "notLessThanInclusive"
+)
+
+func init() {
+	Register("LessThan", reflect.TypeOf((*LessThan)(nil)).Elem())
+}
+
+// LessThan validator checks a number is less than Max
+type LessThan struct {
+	Abstract  `mapstructure:",squash"`
+	Max       float64
+	Inclusive bool
+}
+
+// Valid returns true if value is less than Max
+func (v *LessThan) Valid(value interface{}) bool {
+	v.reset()
+	n, ok := toFloat(value)
+	if !ok {
+		return v.fail(LessThanNotNumeric, value, nil)
+	}
+
+	params := map[string]interface{}{"max": formatFloat(v.Max)}
+	if v.Inclusive {
+		if n > v.Max {
+			return v.fail(LessThanNotInclusive, value, params)
+		}
+	} else if n >= v.Max {
+		return v.fail(LessThanNotLess, value, params)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *LessThan) Defaults() error {
+	v.templates(map[string]string{
+		LessThanNotNumeric:   "'%value%' is not a number",
+		LessThanNotLess:      "'%value%' is not less than '%max%'",
+		LessThanNotInclusive: "'%value%' is not less or equal than '%max%'",
+	})
+
+	return nil
+}
+
+// NewLessThan creates new less than validator
+func NewLessThan(max float64, inclusive bool) (Interface, error) {
+	v := &LessThan{Max: max, Inclusive: inclusive}
+	v.Defaults()
+	return v, nil
+}
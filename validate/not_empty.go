@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+)
+
+// NotEmpty failure codes
+const (
+	NotEmptyIsEmpty = "isEmpty"
+)
+
+func init() {
+	Register("NotEmpty", reflect.TypeOf((*NotEmpty)(nil)).Elem())
+}
+
+// NotEmpty validator fails on nil, blank strings and empty slices and maps
+type NotEmpty struct {
+	Abstract `mapstructure:",squash"`
+}
+
+// Valid returns true if value is not empty
+func (v *NotEmpty) Valid(value interface{}) bool {
+	v.reset()
+	if value == nil {
+		return v.fail(NotEmptyIsEmpty, value, nil)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		if strings.TrimSpace(rv.String()) == "" {
+			return v.fail(NotEmptyIsEmpty, value, nil)
+		}
+
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if rv.Len() == 0 {
+			return v.fail(NotEmptyIsEmpty, value, nil)
+		}
+
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return v.fail(NotEmptyIsEmpty, value, nil)
+		}
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *NotEmpty) Defaults() error {
+	v.templates(map[string]string{
+		NotEmptyIsEmpty: "Value is required and can't be empty",
+	})
+
+	return nil
+}
+
+// NewNotEmpty creates new not empty validator
+func NewNotEmpty() (Interface, error) {
+	v := &NotEmpty{}
+	v.Defaults()
+	return v, nil
+}
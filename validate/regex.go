@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/noxyicm/wsf/errors"
+)
+
+// Regex failure codes
+const (
+	RegexInvalid  = "regexInvalid"
+	RegexNotMatch = "regexNotMatch"
+	RegexErrorous = "regexErrorous"
+)
+
+func init() {
+	Register("Regex", reflect.TypeOf((*Regex)(nil)).Elem())
+}
+
+// Regex validator matches a string against a regular expression
+type Regex struct {
+	Abstract `mapstructure:",squash"`
+	Pattern  string
+	regex    *regexp.Regexp
+}
+
+// Valid returns true if value matches Pattern
+func (v *Regex) Valid(value interface{}) bool {
+	v.reset()
+	s, ok := value.(string)
+	if !ok {
+		return v.fail(RegexInvalid, value, nil)
+	}
+
+	if v.regex == nil || v.regex.String() != v.Pattern {
+		rx, err := regexp.Compile(v.Pattern)
+		if err != nil {
+			return v.fail(RegexErrorous, value, map[string]interface{}{"pattern": v.Pattern})
+		}
+
+		v.regex = rx
+	}
+
+	if !v.regex.MatchString(s) {
+		return v.fail(RegexNotMatch, value, map[string]interface{}{"pattern": v.Pattern})
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *Regex) Defaults() error {
+	v.templates(map[string]string{
+		RegexInvalid:  "Invalid type given. String expected",
+		RegexNotMatch: "'%value%' does not match against pattern '%pattern%'",
+		RegexErrorous: "There was an internal error while using the pattern '%pattern%'",
+	})
+
+	return nil
+}
+
+// NewRegex creates new regex validator
+func NewRegex(pattern string) (Interface, error) {
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid pattern '%s'", pattern)
+	}
+
+	v := &Regex{Pattern: pattern, regex: rx}
+	v.Defaults()
+	return v, nil
+}
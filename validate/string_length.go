@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"reflect"
+	"unicode/utf8"
+)
+
+// StringLength failure codes
+const (
+	StringLengthInvalid  = "stringLengthInvalid"
+	StringLengthTooShort = "stringLengthTooShort"
+	StringLengthTooLong  = "stringLengthTooLong"
+)
+
+func init() {
+	Register("StringLength", reflect.TypeOf((*StringLength)(nil)).Elem())
+}
+
+// StringLength validator checks number of characters of a string
+// Max of 0 means the length is not limited
+type StringLength struct {
+	Abstract `mapstructure:",squash"`
+	Min      int
+	Max      int
+}
+
+// Valid returns true if length of value is between Min and Max
+func (v *StringLength) Valid(value interface{}) bool {
+	v.reset()
+	s, ok := value.(string)
+	if !ok {
+		return v.fail(StringLengthInvalid, value, nil)
+	}
+
+	params := map[string]interface{}{"min": v.Min, "max": v.Max}
+	length := utf8.RuneCountInString(s)
+	if length < v.Min {
+		return v.fail(StringLengthTooShort, value, params)
+	}
+
+	if v.Max > 0 && length > v.Max {
+		return v.fail(StringLengthTooLong, value, params)
+	}
+
+	return true
+}
+
+// Defaults sets default properties
+func (v *StringLength) Defaults() error {
+	v.templates(map[string]string{
+		StringLengthInvalid:  "Invalid type given. String expected",
+		StringLengthTooShort: "'%value%' is less than %min% characters long",
+		StringLengthTooLong:  "'%value%' is more than %max% characters long",
+	})
+
+	return nil
+}
+
+// NewStringLength creates new string length validator
+func NewStringLength(min int, max int) (Interface, error) {
+	v := &StringLength{Min: min, Max: max}
+	v.Defaults()
+	return v, nil
+}
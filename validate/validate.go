@@ -0,0 +1,209 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/registry"
+	"github.com/noxyicm/wsf/translate"
+)
+
+var (
+	defaultTranslator translate.Interface
+)
+
+// Interface is a validator interface
+type Interface interface {
+	Valid(value interface{}) bool
+	Messages() map[string]string
+	Defaults() error
+}
+
+// Register registers a validator
+func Register(validatorName string, validatorType reflect.Type) {
+	vt := registry.Get("validatorTypes")
+	validators := make(map[string]reflect.Type)
+	if vt != nil {
+		validators = vt.(map[string]reflect.Type)
+	}
+
+	validators[validatorName] = validatorType
+	registry.Set("validatorTypes", validators)
+}
+
+// IsRegistered returns true if validator is registered
+func IsRegistered(validatorName string) bool {
+	vt := registry.Get("validatorTypes")
+	if vt == nil {
+		return false
+	}
+
+	_, ok := vt.(map[string]reflect.Type)[validatorName]
+	return ok
+}
+
+// NewValidator creates a registered validator and populates it with options
+func NewValidator(validatorName string, options map[string]interface{}) (Interface, error) {
+	vt := registry.Get("validatorTypes")
+	if vt == nil {
+		return nil, errors.New("There are no validator types")
+	}
+
+	t, ok := vt.(map[string]reflect.Type)[validatorName]
+	if !ok {
+		return nil, errors.Errorf("Unrecognized validator type '%s'", validatorName)
+	}
+
+	v, ok := reflect.New(t).Interface().(Interface)
+	if !ok {
+		return nil, errors.Errorf("Validator type '%s' does not implement validate.Interface", validatorName)
+	}
+
+	if err := v.Defaults(); err != nil {
+		return nil, errors.Wrapf(err, "Unable to create validator '%s'", validatorName)
+	}
+
+	if len(options) > 0 {
+		if err := mapstructure.WeakDecode(options, v); err != nil {
+			return nil, errors.Wrapf(err, "Invalid options of validator '%s'", validatorName)
+		}
+	}
+
+	return v, nil
+}
+
+// SetDefaultTranslator sets translator used by validators with no translator set
+func SetDefaultTranslator(t translate.Interface) {
+	defaultTranslator = t
+}
+
+// DefaultTranslator returns translator used by validators with no translator set
+// Falls back to the global translate instance
+func DefaultTranslator() translate.Interface {
+	if defaultTranslator != nil {
+		return defaultTranslator
+	}
+
+	return translate.Instance()
+}
+
+// Abstract is a extendable validator base
+// Message templates may reference validator options like "%min%" and the validated value as "%value%",
+// templates are translated before substitution
+type Abstract struct {
+	Templates  map[string]string `mapstructure:"messages"`
+	Translator translate.Interface
+	Locale     string
+	messages   map[string]string
+	codes      []string
+}
+
+// Messages returns failure messages of the last validation by failure code
+func (a *Abstract) Messages() map[string]string {
+	return a.messages
+}
+
+// Errors returns failure codes of the last validation
+func (a *Abstract) Errors() []string {
+	return a.codes
+}
+
+// SetMessage sets a message template of failure code
+func (a *Abstract) SetMessage(code string, template string) {
+	if a.Templates == nil {
+		a.Templates = make(map[string]string)
+	}
+
+	a.Templates[code] = template
+}
+
+// SetTranslator sets validator translator
+func (a *Abstract) SetTranslator(t translate.Interface) {
+	a.Translator = t
+}
+
+// SetLocale sets locale messages are translated to
+func (a *Abstract) SetLocale(locale string) {
+	a.Locale = locale
+}
+
+// Resets failures of previous validation
+func (a *Abstract) reset() {
+	a.messages = make(map[string]string)
+	a.codes = make([]string, 0)
+}
+
+// Records a failure of code
+func (a *Abstract) fail(code string, value interface{}, params map[string]interface{}) bool {
+	if a.messages == nil {
+		a.reset()
+	}
+
+	template, ok := a.Templates[code]
+	if !ok {
+		template = code
+	}
+
+	message := a.translate(template)
+	message = strings.ReplaceAll(message, "%value%", valueString(value))
+	for name, param := range params {
+		message = strings.ReplaceAll(message, "%"+name+"%", valueString(param))
+	}
+
+	a.messages[code] = message
+	a.codes = append(a.codes, code)
+	return false
+}
+
+// Returns translated message template
+func (a *Abstract) translate(template string) string {
+	t := a.Translator
+	if t == nil {
+		t = DefaultTranslator()
+	}
+
+	if t == nil {
+		return template
+	}
+
+	if a.Locale != "" {
+		return t.TranslateForLocale(template, a.Locale)
+	}
+
+	return t.Translate(template)
+}
+
+// Sets message templates missing in validator
+func (a *Abstract) templates(templates map[string]string) {
+	if a.Templates == nil {
+		a.Templates = make(map[string]string)
+	}
+
+	for code, template := range templates {
+		if _, ok := a.Templates[code]; !ok {
+			a.Templates[code] = template
+		}
+	}
+}
+
+// Returns value as string for messages
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+
+	case string:
+		return v
+
+	case []string:
+		return strings.Join(v, ", ")
+
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,116 @@
+package view
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/noxyicm/wsf/errors"
+	"github.com/noxyicm/wsf/log"
+	"github.com/noxyicm/wsf/paginator"
+)
+
+const (
+	// TYPEHelperPaginationControl is the name of view helper
+	TYPEHelperPaginationControl = "paginationControl"
+
+	// PaginationControlPagePlaceholder is replaced by page number in url pattern
+	PaginationControlPagePlaceholder = ":page"
+)
+
+var paginationControlTemplate = `{{if gt .Pages.PageCount 1}}<nav class="pagination"><ul>` +
+	`{{if .Pages.Previous}}<li class="previous"><a href="{{.URL .Pages.Previous}}">&laquo;</a></li>{{else}}<li class="previous disabled"><span>&laquo;</span></li>{{end}}` +
+	`{{range .Pages.PagesInRange}}{{if eq . $.Pages.Current}}<li class="current"><span>{{.}}</span></li>{{else}}<li><a href="{{$.URL .}}">{{.}}</a></li>{{end}}{{end}}` +
+	`{{if .Pages.Next}}<li class="next"><a href="{{.URL .Pages.Next}}">&raquo;</a></li>{{else}}<li class="next disabled"><span>&raquo;</span></li>{{end}}` +
+	`</ul></nav>{{end}}`
+
+func init() {
+	RegisterHelper(TYPEHelperPaginationControl, NewPaginationControl)
+}
+
+// PaginationControl is a view helper rendering paginator pages
+type PaginationControl struct {
+	name string
+	view Interface
+	tpl  *template.Template
+}
+
+// Name returns helper name
+func (h *PaginationControl) Name() string {
+	return h.name
+}
+
+// Init the helper
+func (h *PaginationControl) Init(vi Interface, options map[string]interface{}) error {
+	h.SetView(vi)
+
+	if err := vi.AddTemplateFunc(h.name, h.RenderControl); err != nil {
+		return errors.Wrap(err, "Unable to add function to template")
+	}
+
+	return nil
+}
+
+// Setup the helper
+func (h *PaginationControl) Setup() error {
+	return nil
+}
+
+// SetView sets view
+func (h *PaginationControl) SetView(vi Interface) error {
+	h.view = vi
+	return nil
+}
+
+// Render renders helper content
+func (h *PaginationControl) Render() error {
+	return nil
+}
+
+// SetTemplate replaces the control markup
+// Template receives .Pages and .URL page function
+func (h *PaginationControl) SetTemplate(text string) error {
+	tpl, err := template.New(h.name).Parse(text)
+	if err != nil {
+		return errors.Wrap(err, "Unable to parse pagination control template")
+	}
+
+	h.tpl = tpl
+	return nil
+}
+
+// RenderControl renders pagination control for pages
+// Every occurrence of :page in url pattern is replaced by page number
+func (h *PaginationControl) RenderControl(pages *paginator.Pages, url string) template.HTML {
+	if pages == nil {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	if err := h.tpl.Execute(buf, &paginationControlData{Pages: pages, pattern: url}); err != nil {
+		log.Notice("[View_Helper_PaginationControl] error equired while rendering content: "+err.Error(), nil)
+		return ""
+	}
+
+	return template.HTML(buf.String())
+}
+
+// paginationControlData is a data passed to pagination control template
+type paginationControlData struct {
+	Pages   *paginator.Pages
+	pattern string
+}
+
+// URL returns url of the page
+func (d *paginationControlData) URL(page int) string {
+	return strings.Replace(d.pattern, PaginationControlPagePlaceholder, strconv.Itoa(page), -1)
+}
+
+// NewPaginationControl creates a new pagination control view helper
+func NewPaginationControl() (HelperInterface, error) {
+	return &PaginationControl{
+		name: "PaginationControl",
+		tpl:  template.Must(template.New("PaginationControl").Parse(paginationControlTemplate)),
+	}, nil
+}